@@ -2,78 +2,360 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/williamzujkowski/idrac6-manager/internal/api"
+	"github.com/williamzujkowski/idrac6-manager/internal/version"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
 	"github.com/williamzujkowski/idrac6-manager/web"
 )
 
 func main() {
+	configPath := flag.String("config", "", "YAML config file with hosts (reload with SIGHUP or POST /api/reload)")
 	addr := flag.String("addr", ":8080", "listen address")
-	host := flag.String("host", "", "iDRAC host (ip:port or ip)")
+	host := flag.String("host", "", "iDRAC host (ip, ip:port, or an http/https URL)")
 	user := flag.String("user", "root", "iDRAC username")
 	pass := flag.String("pass", "", "iDRAC password")
 	apiKey := flag.String("api-key", "", "optional API key for authentication")
+	basicUser := flag.String("basic-auth-user", "", "accept HTTP Basic logins with this username and --basic-auth-pass")
+	basicPass := flag.String("basic-auth-pass", "", "password for --basic-auth-user (or IDRAC_BASIC_AUTH_PASS env)")
+	basicAPIKey := flag.Bool("basic-auth-api-key", false, "accept HTTP Basic logins with any username and the API key as the password")
 	hostID := flag.String("host-id", "default", "host identifier")
 	hostName := flag.String("host-name", "", "display name for the host")
+	basePath := flag.String("base-path", "", "mount all routes under this subpath (e.g. /idrac)")
+	loginConcurrency := flag.Int("login-concurrency", 1, "simultaneous logins per host; other requests for the host queue in order and share the new session")
+	idleTimeout := flag.Duration("idle-timeout", 0, "log out iDRAC sessions idle for this long (0 disables)")
+	tlsVerify := flag.Bool("tls-verify", false, "verify iDRAC TLS certificates against the system roots")
+	tlsCA := flag.String("tls-ca", "", "PEM CA bundle for verifying iDRAC certificates (implies --tls-verify)")
+	sourceAddr := flag.String("source-address", "", "local IP that connections to iDRACs (HTTPS, SSH, IPMI) originate from, on multi-homed hosts")
+	queryTimeout := flag.Duration("query-timeout", idrac.DefaultTimeouts.Query, "timeout for iDRAC web API reads and logins")
+	actionTimeout := flag.Duration("action-timeout", idrac.DefaultTimeouts.Action, "timeout for iDRAC web API actions such as power changes")
+	racadmTimeout := flag.Duration("racadm-timeout", 0, "timeout for each RACADM command over SSH, e.g. 2m (0 disables)")
+	ipmiPersistent := flag.Bool("ipmi-persistent", false, "keep IPMI sessions open across requests instead of connecting per call")
+	slowThreshold := flag.Duration("slow-threshold", 2*time.Second, "latency above which /api/status reports a host as slow")
+	breakerThreshold := flag.Int("breaker-threshold", 5, "consecutive failures that open a host's circuit breaker (negative disables)")
+	breakerCooldown := flag.Duration("breaker-cooldown", 30*time.Second, "how long an open circuit breaker fails requests fast")
+	selStreamThreshold := flag.Int("sel-stream-threshold", 0, "read SELs above this many records via RACADM: windows by range, full reads streamed (0 disables)")
+	maxSELEntries := flag.Int("sel-max-entries", 500, "cap on entries returned by a full SEL read (negative disables)")
+	refreshInterval := flag.Duration("refresh-interval", 0, "poll power and sensors in the background at this interval, e.g. 30s (0 disables)")
+	refreshConcurrency := flag.Int("refresh-concurrency", 4, "maximum simultaneous background refreshes")
+	bulkConcurrency := flag.Int("bulk-concurrency", 8, "hosts queried at once by /api/status, /api/sensors, and group power")
+	maxBulkConcurrency := flag.Int("max-bulk-concurrency", 64, "upper limit on the per-request ?concurrency= override")
+	staleWindow := flag.Duration("stale-window", 0, "serve the last good sensors, power, or system info for this long when the iDRAC is unreachable, e.g. 5m (0 disables)")
+	readOnly := flag.Bool("read-only", false, "reject every API request that could change a host or the configuration (GETs only)")
+	demo := flag.Bool("demo", false, "serve synthetic data for demo hosts (or the configured ones) without contacting any iDRAC")
+	webhooks := flag.String("webhook", "", "comma-separated URLs every sensor threshold and intrusion event is POSTed to as JSON (needs --refresh-interval)")
+	metricLabels := flag.String("metric-labels", "", "comma-separated host metadata keys exported as /metrics labels, e.g. location,rack,env")
+	logFormat := flag.String("log-format", "text", "log format: text or json (request logs keyed by request_id)")
+	selfTest := flag.Bool("selftest", false, "probe each host's web API, IPMI, and SSH, print what works with hints for what does not, and exit")
+	debugMode := flag.Bool("debug", false, "log raw iDRAC request URLs and responses (secrets redacted) and enable the raw data endpoints")
+	debugErrors := flag.Bool("debug-errors", false, "include panic details and stack traces in error responses")
 	flag.Parse()
 
-	if *host == "" {
-		*host = os.Getenv("IDRAC_HOST")
+	if envKey := os.Getenv("IDRAC_API_KEY"); envKey != "" {
+		*apiKey = envKey
+	}
+	if envPass := os.Getenv("IDRAC_BASIC_AUTH_PASS"); envPass != "" {
+		*basicPass = envPass
+	}
+	if (*basicUser == "") != (*basicPass == "") {
+		fmt.Fprintln(os.Stderr, "Error: --basic-auth-user and --basic-auth-pass must be set together")
+		os.Exit(1)
+	}
+	if envDebug := os.Getenv("IDRAC_DEBUG"); envDebug != "" && envDebug != "0" && envDebug != "false" {
+		*debugMode = true
+	}
+
+	switch *logFormat {
+	case "text":
+	case "json":
+		slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+	default:
+		fmt.Fprintf(os.Stderr, "Error: --log-format must be text or json, got %q\n", *logFormat)
+		os.Exit(1)
+	}
+
+	var sourceIP net.IP
+	if *sourceAddr != "" {
+		if sourceIP = net.ParseIP(*sourceAddr); sourceIP == nil {
+			fmt.Fprintf(os.Stderr, "Error: --source-address must be an IP address, got %q\n", *sourceAddr)
+			os.Exit(1)
+		}
+	}
+
+	cfg := &api.Config{
+		WebFS:              web.FS(),
+		APIKey:             *apiKey,
+		BasicAuth:          api.BasicAuth{Username: *basicUser, Password: *basicPass, APIKey: *basicAPIKey},
+		Debug:              *debugMode,
+		DebugErrors:        *debugErrors,
+		ReadOnly:           *readOnly,
+		Demo:               *demo,
+		LogJSON:            *logFormat == "json",
+		MetricLabels:       splitList(*metricLabels),
+		Webhooks:           splitList(*webhooks),
+		BasePath:           *basePath,
+		ClientIdleTTL:      *idleTimeout,
+		LoginConcurrency:   *loginConcurrency,
+		TLSVerify:          *tlsVerify,
+		SourceAddress:      sourceIP,
+		QueryTimeout:       *queryTimeout,
+		ActionTimeout:      *actionTimeout,
+		RACADMTimeout:      *racadmTimeout,
+		IPMIPersistent:     *ipmiPersistent,
+		SELStreamThreshold: *selStreamThreshold,
+		MaxSELEntries:      *maxSELEntries,
+		RefreshInterval:    *refreshInterval,
+		RefreshConcurrency: *refreshConcurrency,
+		BulkConcurrency:    *bulkConcurrency,
+		MaxBulkConcurrency: *maxBulkConcurrency,
+		StaleWindow:        *staleWindow,
+		SlowThreshold:      *slowThreshold,
+		BreakerThreshold:   *breakerThreshold,
+		BreakerCooldown:    *breakerCooldown,
+	}
+
+	if *configPath != "" {
+		if *host != "" {
+			fmt.Fprintln(os.Stderr, "Error: --host and --config are mutually exclusive")
+			os.Exit(1)
+		}
+		fc, err := api.LoadConfigFile(*configPath)
+		if err != nil {
+			log.Fatalf("Loading config: %v", err)
+		}
+		cfg.Hosts = fc.HostMap()
+		cfg.ConfigPath = *configPath
+		cfg.SensorNames = fc.SensorNames
+		cfg.SensorThresholds = fc.SensorThresholds
+		cfg.Webhooks = append(cfg.Webhooks, fc.Webhooks...)
+		cfg.Groups = fc.Groups
+		if cfg.APIKey == "" {
+			cfg.APIKey = fc.APIKey
+		}
+		if fc.BasicAuth != nil && *basicUser == "" && !*basicAPIKey {
+			cfg.BasicAuth = *fc.BasicAuth
+		}
+		if fc.Listen != "" && !flagSet("addr") {
+			*addr = fc.Listen
+		}
+	} else if *demo && *host == "" && os.Getenv("IDRAC_HOST") == "" {
+		cfg.Hosts = demoHosts()
+	} else {
+		cfg.Hosts = singleHostConfig(*host, *user, *pass, *hostID, *hostName)
+	}
+
+	if *tlsCA != "" {
+		pool, err := idrac.LoadCABundle(*tlsCA)
+		if err != nil {
+			log.Fatalf("Loading CA bundle: %v", err)
+		}
+		cfg.TLSVerify = true
+		cfg.TLSRootCAs = pool
+	}
+
+	if cfg.BasicAuth.APIKey && cfg.APIKey == "" {
+		fmt.Fprintln(os.Stderr, "Error: --basic-auth-api-key needs an API key (--api-key, IDRAC_API_KEY, or api_key in the config)")
+		os.Exit(1)
+	}
+
+	for _, u := range cfg.Webhooks {
+		if err := api.ValidateWebhookURL(u); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+	}
+	if len(cfg.Webhooks) > 0 && cfg.RefreshInterval <= 0 {
+		fmt.Fprintln(os.Stderr, "Error: webhooks need --refresh-interval; events come from the background refresh")
+		os.Exit(1)
+	}
+
+	if *selfTest {
+		if *demo {
+			fmt.Fprintln(os.Stderr, "Error: --selftest and --demo are mutually exclusive")
+			os.Exit(1)
+		}
+		os.Exit(runSelfTest(cfg))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	router := api.NewRouterContext(ctx, cfg)
+
+	v := version.Get()
+	log.Printf("iDRAC6 Manager %s (%s, built %s) starting on %s", v.Version, v.Commit, v.Date, *addr)
+	switch {
+	case *configPath != "":
+		log.Printf("Managing %d hosts from %s", len(cfg.Hosts), *configPath)
+	case cfg.Hosts[*hostID] == nil:
+		log.Printf("Managing %d demo hosts", len(cfg.Hosts))
+	default:
+		log.Printf("Managing host: %s (%s)", cfg.Hosts[*hostID].Name, cfg.Hosts[*hostID].Host)
+	}
+	if cfg.APIKey != "" {
+		log.Printf("API key authentication enabled")
 	}
-	if *host == "" {
-		fmt.Fprintln(os.Stderr, "Error: --host or IDRAC_HOST is required")
+	if cfg.BasicAuth.Username != "" || cfg.BasicAuth.APIKey {
+		log.Printf("HTTP Basic authentication enabled")
+	}
+	if *readOnly {
+		log.Printf("Read-only mode: write endpoints are disabled")
+	}
+	if *demo {
+		log.Printf("Demo mode: serving synthetic data, no iDRAC is contacted")
+	}
+	if len(cfg.Webhooks) > 0 {
+		log.Printf("Delivering events to %d webhooks", len(cfg.Webhooks))
+	}
+	if *debugMode {
+		log.Printf("Debug logging enabled: raw iDRAC responses will be logged (secrets redacted)")
+	}
+	if *debugErrors {
+		log.Printf("Error responses include panic details and stack traces")
+	}
+	log.Printf("Web UI: http://localhost%s", *addr)
+
+	srv := &http.Server{Addr: *addr, Handler: router}
+	shutdown := make(chan struct{})
+	go func() {
+		defer close(shutdown)
+		<-ctx.Done()
+		log.Printf("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Shutdown: %v", err)
+		}
+	}()
+	if err := srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		log.Fatalf("Server failed: %v", err)
+	}
+	<-shutdown
+}
+
+// shutdownTimeout is how long in-flight requests get to finish after
+// SIGINT or SIGTERM.
+const shutdownTimeout = 10 * time.Second
+
+// runSelfTest prints a self-test report for every configured host and
+// returns the exit status: 0 if every transport of every host works.
+func runSelfTest(cfg *api.Config) int {
+	ids := make([]string, 0, len(cfg.Hosts))
+	for id := range cfg.Hosts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	status := 0
+	reports := api.SelfTest(context.Background(), cfg, ids)
+	for _, id := range ids {
+		report := reports[id]
+		fmt.Printf("%s (%s)\n", id, report.Host)
+		for _, check := range report.Checks {
+			if check.OK {
+				fmt.Printf("  %-5s ok    %s (%d ms)\n", check.Transport, check.Sample, check.DurationMS)
+				continue
+			}
+			stage := "unreachable"
+			switch {
+			case check.Authenticated:
+				stage = "failed"
+			case check.Reachable:
+				stage = "auth failed"
+			}
+			fmt.Printf("  %-5s %s: %s\n        hint: %s\n", check.Transport, stage, check.Error, check.Hint)
+		}
+		if !report.OK {
+			status = 1
+		}
+	}
+	return status
+}
+
+// singleHostConfig builds the host map for the flag/env single-host mode,
+// exiting if required settings are missing.
+func singleHostConfig(host, user, pass, hostID, hostName string) map[string]*api.HostConfig {
+	if host == "" {
+		host = os.Getenv("IDRAC_HOST")
+	}
+	if host == "" {
+		fmt.Fprintln(os.Stderr, "Error: --host, IDRAC_HOST, or --config is required")
 		flag.Usage()
 		os.Exit(1)
 	}
 
 	if envUser := os.Getenv("IDRAC_USER"); envUser != "" {
-		*user = envUser
+		user = envUser
 	}
 	if envPass := os.Getenv("IDRAC_PASS"); envPass != "" {
-		*pass = envPass
+		pass = envPass
 	}
-	if *pass == "" {
+	if pass == "" {
 		fmt.Fprintln(os.Stderr, "Error: --pass or IDRAC_PASS is required")
 		flag.Usage()
 		os.Exit(1)
 	}
-	if envKey := os.Getenv("IDRAC_API_KEY"); envKey != "" {
-		*apiKey = envKey
-	}
 
-	displayName := *hostName
+	displayName := hostName
 	if displayName == "" {
-		displayName = *host
+		displayName = host
 	}
 
-	cfg := &api.Config{
-		Hosts: map[string]*api.HostConfig{
-			*hostID: {
-				Name:     displayName,
-				Host:     *host,
-				Username: *user,
-				Password: *pass,
-			},
+	return map[string]*api.HostConfig{
+		hostID: {
+			Name:     displayName,
+			Host:     host,
+			Username: user,
+			Password: pass,
 		},
-		WebFS:  web.FS(),
-		APIKey: *apiKey,
 	}
+}
 
-	router := api.NewRouter(cfg)
-
-	log.Printf("iDRAC6 Manager starting on %s", *addr)
-	log.Printf("Managing host: %s (%s)", displayName, *host)
-	if *apiKey != "" {
-		log.Printf("API key authentication enabled")
+// demoHosts returns the fake hosts --demo serves when no host is given.
+// Their addresses are never dialed.
+func demoHosts() map[string]*api.HostConfig {
+	hosts := make(map[string]*api.HostConfig)
+	for _, h := range []struct{ id, name, rack string }{
+		{"r710-a", "Demo R710 A", "rack-1"},
+		{"r710-b", "Demo R710 B", "rack-1"},
+		{"r710-c", "Demo R710 C", "rack-2"},
+	} {
+		hosts[h.id] = &api.HostConfig{Name: h.name, Host: h.id + ".demo.invalid", Username: "root", Password: "calvin", Tags: []string{h.rack}}
 	}
-	log.Printf("Web UI: http://localhost%s", *addr)
+	return hosts
+}
 
-	if err := http.ListenAndServe(*addr, router); err != nil {
-		log.Fatalf("Server failed: %v", err)
+// flagSet reports whether the named flag was given on the command line.
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// splitList splits a comma-separated flag value, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
 	}
+	return out
 }
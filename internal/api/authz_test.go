@@ -0,0 +1,93 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAuthorize(t *testing.T) {
+	server := mockIDRAC(t)
+
+	var mu sync.Mutex
+	var seen []AuthzRequest
+	cfg := &Config{
+		APIKey: "secret",
+		Debug:  true,
+		Hosts: map[string]*HostConfig{
+			"s1": {Host: server.Addr(), Username: "root", Password: "calvin"},
+		},
+		Authorize: func(_ context.Context, req AuthzRequest) (bool, string) {
+			mu.Lock()
+			seen = append(seen, req)
+			mu.Unlock()
+			if req.Action == "power.restart" {
+				return true, ""
+			}
+			return false, "change freeze"
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, tt := range []struct {
+		method, path, body string
+		want               int
+	}{
+		{"POST", "/api/hosts/s1/power", `{"action":"restart"}`, http.StatusOK},
+		{"POST", "/api/hosts/s1/power", `{"action":"off"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/ipmi/power", `{"action":"off"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/power/policy", `{"policy":"always-on"}`, http.StatusForbidden},
+		{"DELETE", "/api/hosts/s1/sel?confirm=true", "", http.StatusForbidden},
+		{"DELETE", "/api/hosts/s1/crashscreen", "", http.StatusForbidden},
+		{"POST", "/api/hosts/s1/certificate/regenerate?confirm=s1", "", http.StatusForbidden},
+		{"POST", "/api/hosts/s1/virtualmedia", `{"url":"http://x/a.iso"}`, http.StatusForbidden},
+		{"DELETE", "/api/hosts/s1/virtualmedia", "", http.StatusForbidden},
+		{"POST", "/api/hosts/s1/power/stats/reset", "", http.StatusForbidden},
+		{"POST", "/api/hosts/s1/firmware/update", `{"imageUrl":"http://x/firmimg.d6","confirm":true}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/boot/once", `{"device":"pxe"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/bootorder", `{"device":"PXE"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/ipmi/watchdog", `{"action":"reset","timeoutSeconds":60}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/ipmi/watchdog/reset", "", http.StatusForbidden},
+		{"POST", "/api/hosts/s1/idrac/name", `{"name":"rac-s1"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/idrac/password", `{"currentPassword":"calvin","newPassword":"s3cret"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/assettag", `{"assetTag":"A123"}`, http.StatusForbidden},
+		{"POST", "/api/hosts/s1/lcd", `{"message":"hello"}`, http.StatusForbidden},
+		{"DELETE", "/api/hosts/s1/sessions/3", "", http.StatusForbidden},
+		{"POST", "/api/hosts/s1/raw", `{"set":"pwState:0"}`, http.StatusForbidden},
+	} {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("X-API-Key", "secret")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s %s: status = %d, want %d: %s", tt.method, tt.path, tt.body, w.Code, tt.want, w.Body.String())
+			continue
+		}
+		if tt.want == http.StatusForbidden {
+			var body apiError
+			json.NewDecoder(w.Body).Decode(&body)
+			if body.Code != "forbidden" || !strings.Contains(body.Message, "change freeze") {
+				t.Errorf("%s %s: error = %+v, want the policy's reason", tt.method, tt.path, body)
+			}
+		}
+	}
+
+	if !server.PowerOn() {
+		t.Error("denied power-off reached the iDRAC")
+	}
+	want := []string{"power.restart", "power.off", "ipmi.power.off", "power.policy.always-on", "sel.clear", "crashscreen.clear", "certificate.regenerate", "virtualmedia.mount", "virtualmedia.unmount",
+		"power.stats.reset", "firmware.update", "boot.once", "bootorder.set", "ipmi.watchdog.set", "ipmi.watchdog.reset",
+		"idrac.name", "idrac.password", "assettag.set", "lcd.set", "session.kill", "raw.set"}
+	if len(seen) != len(want) {
+		t.Fatalf("hook saw %d requests, want %d", len(seen), len(want))
+	}
+	for i, req := range seen {
+		if req.Action != want[i] || req.HostID != "s1" || req.Identity != IdentityAPIKey || req.RequestID == "" {
+			t.Errorf("request %d = %+v, want %s on s1 by %s", i, req, want[i], IdentityAPIKey)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// defaultBreakerThreshold is used when Config.BreakerThreshold is zero.
+	defaultBreakerThreshold = 5
+	// defaultBreakerCooldown is used when Config.BreakerCooldown is zero.
+	defaultBreakerCooldown = 30 * time.Second
+)
+
+// Circuit breaker states reported by /api/status.
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half-open"
+)
+
+// breaker is a per-host circuit breaker. After threshold consecutive
+// failures it opens and rejects requests until cooldown has passed, then
+// lets a single trial request through: success closes it, failure reopens
+// it for another cooldown.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time // zero while closed
+	trial    bool      // a half-open trial request is in flight
+}
+
+// allow reports whether a request may proceed and, if not, how long until
+// the next trial.
+func (b *breaker) allow(now time.Time) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true, 0
+	}
+	if wait := b.openedAt.Add(b.cooldown).Sub(now); wait > 0 {
+		return false, wait
+	}
+	if b.trial {
+		return false, b.cooldown
+	}
+	b.trial = true
+	return true, 0
+}
+
+// record updates the breaker with a request outcome.
+func (b *breaker) record(ok bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if ok {
+		b.failures = 0
+		b.openedAt = time.Time{}
+		return
+	}
+	b.failures++
+	if !b.openedAt.IsZero() || b.failures >= b.threshold {
+		b.openedAt = now
+	}
+}
+
+// state returns closed, open, or half-open (cooldown over, awaiting a trial).
+func (b *breaker) state(now time.Time) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case b.openedAt.IsZero():
+		return breakerClosed
+	case now.Before(b.openedAt.Add(b.cooldown)):
+		return breakerOpen
+	default:
+		return breakerHalfOpen
+	}
+}
+
+// getBreaker returns the host's breaker, or nil if breakers are disabled.
+func (h *Handlers) getBreaker(hostID string) *breaker {
+	threshold, cooldown := h.config.BreakerThreshold, h.config.BreakerCooldown
+	if threshold < 0 {
+		return nil
+	}
+	if threshold == 0 {
+		threshold = defaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = defaultBreakerCooldown
+	}
+
+	v, _ := h.breakers.LoadOrStore(hostID, &breaker{threshold: threshold, cooldown: cooldown})
+	return v.(*breaker)
+}
+
+// breakerFailure reports whether a response status suggests the iDRAC is
+// unreachable or failing, as opposed to a client error or a feature the
+// host lacks (501).
+func breakerFailure(status int) bool {
+	return status == http.StatusInternalServerError ||
+		status == http.StatusBadGateway ||
+		status == http.StatusGatewayTimeout
+}
+
+// circuitBreaker fast-fails requests to a host whose breaker is open with
+// 503 and a Retry-After header, so a dead iDRAC costs no dial timeouts.
+// Reads that can be answered from the last-known-good cache still are, and
+// a stale answer counts as a failure.
+func (h *Handlers) circuitBreaker(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hostID := chi.URLParam(r, "hostID")
+		b := h.getBreaker(hostID)
+		if b == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ok, wait := b.allow(time.Now())
+		if !ok {
+			secs := int(math.Ceil(wait.Seconds()))
+			msg := fmt.Sprintf("host %s is unavailable: circuit open after %d consecutive failures; retry in %ds", hostID, b.threshold, secs)
+			if kind, ok := staleRoute(r); ok &&
+				h.serveStale(w, hostID, kind, &apiError{Status: http.StatusServiceUnavailable, Code: "unavailable", Message: msg}) {
+				return
+			}
+			w.Header().Set("Retry-After", strconv.Itoa(secs))
+			writeError(w, http.StatusServiceUnavailable, msg)
+			return
+		}
+
+		// Record from a defer so a panicking handler counts as a failure
+		// and never leaves a half-open trial outstanding.
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		completed := false
+		defer func() {
+			stale := rec.Header().Get("Warning") == staleWarning
+			b.record(completed && !stale && !breakerFailure(rec.status), time.Now())
+		}()
+		next.ServeHTTP(rec, r)
+		completed = true
+	})
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestBreaker(t *testing.T) {
+	b := &breaker{threshold: 3, cooldown: time.Minute}
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		b.record(false, now)
+	}
+	if ok, _ := b.allow(now); !ok || b.state(now) != breakerClosed {
+		t.Fatal("breaker should stay closed below the threshold")
+	}
+
+	b.record(false, now)
+	if ok, wait := b.allow(now.Add(time.Second)); ok || wait != 59*time.Second {
+		t.Errorf("allow() = %v, %s; want rejected with 59s left", ok, wait)
+	}
+
+	later := now.Add(time.Minute)
+	if got := b.state(later); got != breakerHalfOpen {
+		t.Errorf("state after cooldown = %s, want half-open", got)
+	}
+	if ok, _ := b.allow(later); !ok {
+		t.Fatal("first request after cooldown should be allowed as a trial")
+	}
+	if ok, _ := b.allow(later); ok {
+		t.Error("only one trial request should be allowed at a time")
+	}
+
+	// A failed trial reopens immediately, without waiting for the threshold.
+	b.record(false, later)
+	if got := b.state(later); got != breakerOpen {
+		t.Errorf("state after failed trial = %s, want open", got)
+	}
+
+	b.allow(later.Add(time.Minute))
+	b.record(true, later.Add(time.Minute))
+	if got := b.state(later.Add(time.Minute)); got != breakerClosed {
+		t.Errorf("state after successful trial = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerMiddleware(t *testing.T) {
+	h := &Handlers{config: &Config{BreakerThreshold: 2, BreakerCooldown: time.Minute}}
+	calls := 0
+	r := chi.NewRouter()
+	r.With(h.circuitBreaker).Get("/hosts/{hostID}/power", func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		writeError(w, http.StatusInternalServerError, "dial tcp: i/o timeout")
+	})
+
+	codes := make([]int, 3)
+	for i := range codes {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/dead/power", nil))
+		codes[i] = w.Code
+		if w.Code == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+			t.Error("503 should carry Retry-After")
+		}
+	}
+	if codes[2] != http.StatusServiceUnavailable || calls != 2 {
+		t.Errorf("codes = %v, handler calls = %d; want third request rejected without a call", codes, calls)
+	}
+
+	h.config.BreakerThreshold = -1
+	h.breakers.Delete("dead")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/dead/power", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("disabled breaker: status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+}
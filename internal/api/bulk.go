@@ -0,0 +1,138 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBulkConcurrency bounds how many hosts are queried at once
+	// when Config.BulkConcurrency is zero.
+	defaultBulkConcurrency = 8
+	// defaultMaxBulkConcurrency caps ?concurrency= when
+	// Config.MaxBulkConcurrency is zero.
+	defaultMaxBulkConcurrency = 64
+	// bulkHostTimeout bounds how long a single host may take.
+	bulkHostTimeout = 20 * time.Second
+)
+
+// hostResult is the per-host outcome of a bulk operation.
+type hostResult struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+}
+
+// bulkConcurrency returns how many hosts a bulk request may query at
+// once: its "concurrency" query parameter, up to Config.MaxBulkConcurrency,
+// or Config.BulkConcurrency. The error is a 400 apiError.
+func (h *Handlers) bulkConcurrency(r *http.Request) (int, error) {
+	n := h.config.BulkConcurrency
+	if n <= 0 {
+		n = defaultBulkConcurrency
+	}
+	limit := h.config.MaxBulkConcurrency
+	if limit <= 0 {
+		limit = defaultMaxBulkConcurrency
+	}
+	n = min(n, limit)
+	q := r.URL.Query().Get("concurrency")
+	if q == "" {
+		return n, nil
+	}
+	n, err := strconv.Atoi(q)
+	if err != nil || n < 1 || n > limit {
+		return 0, &apiError{Status: http.StatusBadRequest, Code: "bad_request", Message: fmt.Sprintf("concurrency must be an integer from 1 to %d", limit)}
+	}
+	return n, nil
+}
+
+// forEachHost runs fn for every host ID using a worker pool of size
+// concurrency. Each call gets a context derived from ctx that ends after
+// the per-host timeout, so a slow host's requests are cancelled rather
+// than left running; a host that times out is reported as an error. Hosts
+// not yet started when ctx ends are reported with its error.
+func forEachHost(ctx context.Context, ids []string, concurrency int, fn func(ctx context.Context, hostID string) (interface{}, error)) map[string]hostResult {
+	results := make(map[string]hostResult, len(ids))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, id := range ids {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			results[id] = hostResult{Error: ctx.Err().Error()}
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			hctx, cancel := context.WithTimeout(ctx, bulkHostTimeout)
+			defer cancel()
+
+			type outcome struct {
+				data interface{}
+				err  error
+			}
+			done := make(chan outcome, 1)
+			go func() {
+				data, err := fn(hctx, id)
+				done <- outcome{data, err}
+			}()
+
+			var res hostResult
+			select {
+			case o := <-done:
+				if o.err != nil {
+					res.Error = o.err.Error()
+				} else {
+					res.Data = o.data
+				}
+			case <-hctx.Done():
+				res.Error = fmt.Sprintf("timed out after %s", bulkHostTimeout)
+				if ctx.Err() != nil {
+					res.Error = ctx.Err().Error()
+				}
+			}
+
+			mu.Lock()
+			results[id] = res
+			mu.Unlock()
+		}(id)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// GetAllSensors returns sensor readings for every host that is not
+// disabled, querying up to ?concurrency= hosts at once.
+func (h *Handlers) GetAllSensors(w http.ResponseWriter, r *http.Request) {
+	concurrency, err := h.bulkConcurrency(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	results := forEachHost(r.Context(), h.activeHostIDs(), concurrency, func(ctx context.Context, hostID string) (interface{}, error) {
+		client, err := h.getClient(ctx, hostID)
+		if err != nil {
+			return nil, err
+		}
+		sensors, err := client.GetSensors()
+		if err != nil {
+			return nil, err
+		}
+		h.renameSensors(hostID, sensors)
+		return sensors, nil
+	})
+
+	writeJSON(w, http.StatusOK, results)
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestForEachHost(t *testing.T) {
+	ids := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"}
+
+	for _, concurrency := range []int{1, 3, defaultBulkConcurrency} {
+		var inFlight, peak atomic.Int32
+		results := forEachHost(context.Background(), ids, concurrency, func(_ context.Context, id string) (interface{}, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			if id == "c" {
+				return nil, errors.New("unreachable")
+			}
+			return id + "-ok", nil
+		})
+
+		if len(results) != len(ids) {
+			t.Fatalf("got %d results, want %d", len(results), len(ids))
+		}
+		if results["a"].Data != "a-ok" {
+			t.Errorf("a: data = %v, want a-ok", results["a"].Data)
+		}
+		if results["c"].Error != "unreachable" {
+			t.Errorf("c: error = %q, want unreachable", results["c"].Error)
+		}
+		if p := peak.Load(); p > int32(concurrency) || p < 1 {
+			t.Errorf("peak concurrency = %d, want 1 to %d", p, concurrency)
+		}
+	}
+}
+
+func TestForEachHost_CancelsWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	results := forEachHost(ctx, []string{"a", "b"}, 1, func(ctx context.Context, id string) (interface{}, error) {
+		if id == "a" {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+		}
+		return nil, ctx.Err()
+	})
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("the running host's call was not cancelled")
+	}
+	for _, id := range []string{"a", "b"} {
+		if results[id].Error != context.Canceled.Error() {
+			t.Errorf("%s: error = %q, want %q", id, results[id].Error, context.Canceled)
+		}
+	}
+}
+
+func TestBulkConcurrency(t *testing.T) {
+	h := &Handlers{config: &Config{BulkConcurrency: 4, MaxBulkConcurrency: 16}}
+	for _, tt := range []struct {
+		query string
+		want  int
+	}{
+		{"", 4},
+		{"?concurrency=1", 1},
+		{"?concurrency=16", 16},
+		{"?concurrency=17", 0},
+		{"?concurrency=0", 0},
+		{"?concurrency=many", 0},
+	} {
+		n, err := h.bulkConcurrency(httptest.NewRequest("GET", "/api/status"+tt.query, nil))
+		if n != tt.want || (err != nil) != (tt.want == 0) {
+			t.Errorf("%q: concurrency = %d, %v; want %d", tt.query, n, err, tt.want)
+		}
+	}
+
+	if n, _ := (&Handlers{config: &Config{}}).bulkConcurrency(httptest.NewRequest("GET", "/", nil)); n != defaultBulkConcurrency {
+		t.Errorf("default concurrency = %d, want %d", n, defaultBulkConcurrency)
+	}
+
+	router := NewRouter(&Config{Hosts: map[string]*HostConfig{}})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/sensors?concurrency=1000", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("GET /api/sensors?concurrency=1000 status = %d, want 400", w.Code)
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// certificateRegenerateGuidance tells the caller what to expect after a
+// certificate regeneration.
+const certificateRegenerateGuidance = "the iDRAC is resetting to load its new certificate: its web UI, SSH, and IPMI drop for one to two minutes, and the host keeps running. Browsers that stored an exception for the old certificate will warn once more"
+
+// RegenerateCertificate replaces the host's iDRAC web certificate with a
+// new self-signed one ("racadm sslresetcfg") and resets the iDRAC so it
+// takes effect. Because the reset drops every iDRAC session, the caller
+// must confirm by passing the host ID as confirm (query or JSON body).
+// Cached clients are dropped, without logging out of the resetting iDRAC,
+// so the next request connects afresh.
+func (h *Handlers) RegenerateCertificate(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	hc, ok := h.hostConfig(hostID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+
+	confirm := r.URL.Query().Get("confirm")
+	if confirm == "" && r.ContentLength != 0 {
+		var req struct {
+			Confirm string `json:"confirm"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		confirm = req.Confirm
+	}
+	if confirm != hostID {
+		writeError(w, http.StatusBadRequest, "regenerating the certificate resets the iDRAC and drops every session; pass confirm="+hostID+" to proceed")
+		return
+	}
+	setSpanAction(r, "regenerate certificate")
+	if !h.authorize(w, r, "certificate.regenerate", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err := admin.RegenerateCertificate(); err != nil {
+		handleError(w, err)
+		return
+	}
+	log.Printf("audit: regenerated the iDRAC certificate on %s (%s)", hostID, r.RemoteAddr)
+	// The reset ends every session, so there is nothing to log out of.
+	h.abandonHost(hostID)
+	if err := admin.ResetController(); err != nil {
+		handleError(w, err)
+		return
+	}
+	log.Printf("audit: reset the iDRAC on %s to load its new certificate", hostID)
+
+	resp := map[string]string{"status": "regenerated", "guidance": certificateRegenerateGuidance}
+	if hc.CABundle != "" || h.config.TLSVerify {
+		resp["warning"] = "TLS verification is on for this host and the new self-signed certificate will not verify; update its CA bundle or pin the new certificate"
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// getClient returns the pooled iDRAC client for the given host, logging in
+// on first use, bound to ctx so its requests are cancelled with it and
+// traced under its span. Handlers pass the request context.
+func (h *Handlers) getClient(ctx context.Context, hostID string) (idrac.HostClient, error) {
+	if h.config.Demo {
+		return h.getDemoClient(hostID)
+	}
+	if client, ok := h.pool.Lookup(hostID); ok {
+		return client.WithContext(ctx), nil
+	}
+
+	hostCfg, ok := h.hostConfig(hostID)
+	if !ok {
+		return nil, fmt.Errorf("host %q not found", hostID)
+	}
+
+	opts, err := h.config.clientOptions(hostCfg)
+	if err != nil {
+		return nil, fmt.Errorf("configuring client for %s: %w", hostCfg.Host, err)
+	}
+
+	client, err := h.pool.GetContext(ctx, idrac.Target{
+		ID:       hostID,
+		Host:     hostCfg.Host,
+		Username: hostCfg.Username,
+		Password: hostCfg.Password,
+		Options:  opts,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client.WithContext(ctx), nil
+}
+
+// getDemoClient returns the host's demo client, creating it on first use
+// so its simulated state lasts for the life of the server.
+func (h *Handlers) getDemoClient(hostID string) (*idrac.DemoClient, error) {
+	if cached, ok := h.demo.Load(hostID); ok {
+		return cached.(*idrac.DemoClient), nil
+	}
+	if _, ok := h.hostConfig(hostID); !ok {
+		return nil, fmt.Errorf("host %q not found", hostID)
+	}
+	client, _ := h.demo.LoadOrStore(hostID, idrac.NewDemoClient(hostID))
+	return client.(*idrac.DemoClient), nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+// mockIDRAC starts a mock iDRAC6 that is closed when the test ends.
+func mockIDRAC(t *testing.T) *idractest.Server {
+	t.Helper()
+	server := idractest.NewServer(idractest.Options{})
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetClient_ReusesSession(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{
+			"s1": {Host: addr, Username: "root", Password: "calvin"},
+		}},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+
+	first, err := h.getClient(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("getClient() error = %v", err)
+	}
+	second, err := h.getClient(context.Background(), "s1")
+	if err != nil {
+		t.Fatalf("getClient() error = %v", err)
+	}
+	if first != second {
+		t.Error("getClient() should return the pooled client")
+	}
+	if got := h.pool.Stats().Logins; got != 1 {
+		t.Errorf("logins = %d, want 1", got)
+	}
+
+	if _, err := h.getClient(context.Background(), "missing"); err == nil {
+		t.Error("getClient(missing) should fail")
+	}
+}
+
+func TestClientOptions_HostCABundleOverridesGlobal(t *testing.T) {
+	server := mockIDRAC(t)
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	pemData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, pemData, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	h := &Handlers{
+		config: &Config{
+			// Global verification with an empty pool would reject the mock
+			TLSVerify:  true,
+			TLSRootCAs: x509.NewCertPool(),
+			Hosts: map[string]*HostConfig{
+				"global": {Host: server.Addr(), Username: "root", Password: "calvin"},
+				"pinned": {Host: server.Addr(), Username: "root", Password: "calvin", CABundle: path},
+			},
+		},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+
+	if _, err := h.getClient(context.Background(), "global"); err == nil {
+		t.Error("global pool without the mock CA should fail verification")
+	}
+	if _, err := h.getClient(context.Background(), "pinned"); err != nil {
+		t.Errorf("per-host CA bundle should win over global roots, got %v", err)
+	}
+}
+
+func TestRawData(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+	hosts := map[string]*HostConfig{"s1": {Host: addr, Username: "root", Password: "calvin"}}
+
+	get := func(cfg *Config, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/hosts/s1/raw"+query, nil)
+		req.Header.Set("X-API-Key", cfg.APIKey)
+		w := httptest.NewRecorder()
+		NewRouter(cfg).ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get(&Config{Hosts: hosts, APIKey: "k"}, "?get=pwState"); w.Code != http.StatusNotFound {
+		t.Errorf("without debug: status = %d, want 404", w.Code)
+	}
+	if w := get(&Config{Hosts: hosts, Debug: true}, "?get=pwState"); w.Code != http.StatusForbidden {
+		t.Errorf("without API key: status = %d, want 403", w.Code)
+	}
+
+	cfg := &Config{Hosts: hosts, Debug: true, APIKey: "k"}
+	if w := get(cfg, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("no get: status = %d, want 400", w.Code)
+	}
+	w := get(cfg, "?get=pwState")
+	if w.Code != http.StatusOK || w.Body.String() != "<root><pwState>1</pwState></root>" {
+		t.Fatalf("raw get = %d %q", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/xml") {
+		t.Errorf("Content-Type = %q, want text/xml", ct)
+	}
+
+	set := func(cfg *Config, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/hosts/s1/raw", strings.NewReader(body))
+		req.Header.Set("X-API-Key", cfg.APIKey)
+		w := httptest.NewRecorder()
+		NewRouter(cfg).ServeHTTP(w, req)
+		return w
+	}
+	if w := set(cfg, `{"set":"pwState:0"}`); w.Code != http.StatusOK {
+		t.Errorf("raw set: status = %d %q", w.Code, w.Body.String())
+	}
+	if w := set(cfg, `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("raw set without a param: status = %d, want 400", w.Code)
+	}
+	if w := get(cfg, "?set=pwState:0"); w.Code != http.StatusBadRequest {
+		t.Errorf("set over GET: status = %d, want 400", w.Code)
+	}
+	readOnly := &Config{Hosts: hosts, Debug: true, APIKey: "k", ReadOnly: true}
+	if w := set(readOnly, `{"set":"pwState:0"}`); w.Code != http.StatusForbidden {
+		t.Errorf("raw set in read-only mode: status = %d, want 403", w.Code)
+	}
+}
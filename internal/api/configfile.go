@@ -0,0 +1,120 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"gopkg.in/yaml.v3"
+)
+
+// FileConfig is the layout of the YAML configuration file.
+// It doubles as the format of GET /api/config/export and POST
+// /api/config/import, where it is encoded as JSON.
+type FileConfig struct {
+	Hosts  []FileHost `json:"hosts" yaml:"hosts"`
+	APIKey string     `json:"-" yaml:"api_key,omitempty"`
+	// BasicAuth enables HTTP Basic logins; see Config.BasicAuth.
+	BasicAuth *BasicAuth `json:"-" yaml:"basic_auth,omitempty"`
+	Listen    string     `json:"-" yaml:"listen,omitempty"`
+	// SensorNames is the global sensor rename map; see Config.SensorNames.
+	SensorNames map[string]string `json:"-" yaml:"sensor_names,omitempty"`
+	// SensorThresholds is the global threshold override map; see
+	// Config.SensorThresholds.
+	SensorThresholds map[string]float64 `json:"-" yaml:"sensor_thresholds,omitempty"`
+	// Webhooks are event webhook URLs; see Config.Webhooks.
+	Webhooks []string `json:"-" yaml:"webhooks,omitempty"`
+	// Groups maps group names to members; see Config.Groups.
+	Groups map[string]*GroupConfig `json:"-" yaml:"groups,omitempty"`
+}
+
+// FileHost is a host entry in the configuration file.
+type FileHost struct {
+	ID         string `json:"id" yaml:"id"`
+	HostConfig `yaml:",inline"`
+}
+
+// LoadConfigFile reads and validates a YAML configuration file. Unknown
+// keys are rejected so typos don't silently drop settings.
+func LoadConfigFile(path string) (*FileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+
+	var fc FileConfig
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&fc); err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+
+	if err := fc.validate(nil); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	return &fc, nil
+}
+
+// validate checks host IDs are present and unique and each host has its
+// connection settings. A host may omit its password only if keepPassword
+// reports that an existing password will be kept for it.
+func (fc *FileConfig) validate(keepPassword func(id string) bool) error {
+	seen := make(map[string]bool, len(fc.Hosts))
+	for i, h := range fc.Hosts {
+		if h.ID == "" {
+			return fmt.Errorf("host %d has no id", i+1)
+		}
+		if seen[h.ID] {
+			return fmt.Errorf("duplicate host id %q", h.ID)
+		}
+		seen[h.ID] = true
+		if h.Host == "" || h.Username == "" {
+			return fmt.Errorf("host %q needs host, username, and password", h.ID)
+		}
+		if _, err := idrac.ParseHost(h.Host); err != nil {
+			return fmt.Errorf("host %q: %w", h.ID, err)
+		}
+		if h.QueryTimeout < 0 || h.ActionTimeout < 0 || h.RACADMTimeout < 0 {
+			return fmt.Errorf("host %q: timeouts must not be negative", h.ID)
+		}
+		keep := keepPassword != nil && keepPassword(h.ID)
+		if h.Password == "" && !keep {
+			return fmt.Errorf("host %q needs host, username, and password", h.ID)
+		}
+		for j, cr := range h.Credentials {
+			if cr.Username == "" || (cr.Password == "" && !keep) {
+				return fmt.Errorf("host %q credential %d needs username and password", h.ID, j+1)
+			}
+		}
+	}
+	if b := fc.BasicAuth; b != nil && (b.Username == "") != (b.Password == "") {
+		return fmt.Errorf("basic_auth needs both username and password")
+	}
+	for name, g := range fc.Groups {
+		if name == "" || g == nil || len(g.Hosts) == 0 && len(g.Tags) == 0 {
+			return fmt.Errorf("group %q needs hosts or tags", name)
+		}
+		for _, id := range g.Hosts {
+			if !seen[id] {
+				return fmt.Errorf("group %q lists unknown host %q", name, id)
+			}
+		}
+	}
+	for _, u := range fc.Webhooks {
+		if err := ValidateWebhookURL(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HostMap returns the configured hosts keyed by ID.
+func (fc *FileConfig) HostMap() map[string]*HostConfig {
+	hosts := make(map[string]*HostConfig, len(fc.Hosts))
+	for _, h := range fc.Hosts {
+		hc := h.HostConfig
+		hosts[h.ID] = &hc
+	}
+	return hosts
+}
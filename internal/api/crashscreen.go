@@ -0,0 +1,66 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// crashScreenStatus is the crash screen report with the license tier,
+// since Dell lists crash screen capture as an iDRAC6 Enterprise feature.
+type crashScreenStatus struct {
+	*idrac.CrashScreen
+	License idrac.License `json:"license"`
+}
+
+// GetCrashScreen reports whether the host's watchdog is set up to capture
+// a crash screen and, from the SEL, whether one was likely captured and
+// when. The image itself is only viewable in the iDRAC web UI.
+func (h *Handlers) GetCrashScreen(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	cs, err := admin.GetCrashScreen()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if client, err := h.getClient(r.Context(), hostID); err == nil {
+		if sel, err := client.GetSEL(); err == nil {
+			if e, ok := sel.LastWatchdogEvent(); ok {
+				cs.Available, cs.LastCrash = true, e.Timestamp
+			}
+		}
+	}
+
+	license, _ := h.getLicense(hostID)
+	writeJSON(w, http.StatusOK, crashScreenStatus{CrashScreen: cs, License: license})
+}
+
+// ClearCrashScreen discards the host's last crash screen.
+func (h *Handlers) ClearCrashScreen(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	if !h.authorize(w, r, "crashscreen.clear", hostID) {
+		return
+	}
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := admin.ClearCrashScreen(); err != nil {
+		handleError(w, err)
+		return
+	}
+	log.Printf("audit: cleared crash screen on %s", hostID)
+	writeJSON(w, http.StatusOK, map[string]string{"status": "cleared"})
+}
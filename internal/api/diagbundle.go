@@ -0,0 +1,256 @@
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/internal/version"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// diagFile is one collected file of a diagnostic bundle with its outcome.
+type diagFile struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	Bytes      int    `json:"bytes,omitempty"`
+	DurationMS int64  `json:"durationMs"`
+
+	data []byte
+	err  error
+}
+
+// diagManifest is manifest.json: what the bundle holds and what failed.
+type diagManifest struct {
+	Host      string       `json:"host"`
+	Generated time.Time    `json:"generated"`
+	Manager   version.Info `json:"manager"`
+	// Firmware summarizes versions from whichever system info sources
+	// answered, for the top of a support ticket.
+	Firmware map[string]string `json:"firmware,omitempty"`
+	Files    []*diagFile       `json:"files"`
+}
+
+// GetDiagBundle gathers system info, RACADM getsysinfo, the SEL, the RAC
+// log, sensor readings, power state, and "racadm racdump" concurrently
+// and returns them as a tar.gz (or ?format=zip) with a manifest.json
+// recording which collectors succeeded. A failed collector leaves its file
+// out; only if all fail is the request an error.
+func (h *Handlers) GetDiagBundle(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "tar.gz"
+	}
+	if format != "tar.gz" && format != "zip" {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown format: %q (valid: tar.gz, zip)", format))
+		return
+	}
+
+	client, clientErr := h.getClient(r.Context(), hostID)
+	webAPI := func(fn func(idrac.HostClient) (interface{}, error)) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			if clientErr != nil {
+				return nil, clientErr
+			}
+			return fn(client)
+		}
+	}
+	racadm := func(fn func(*idrac.Admin) (interface{}, error)) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			admin, err := h.requestAdmin(r, hostID)
+			if err != nil {
+				return nil, err
+			}
+			return fn(admin)
+		}
+	}
+
+	var sysInfo *idrac.SystemInfo
+	var racInfo *idrac.RACSysInfo
+	collectors := []struct {
+		name string
+		fn   func() (interface{}, error)
+	}{
+		{"system.json", webAPI(func(c idrac.HostClient) (interface{}, error) {
+			info, err := c.GetSystemInfo()
+			sysInfo = info
+			return info, err
+		})},
+		{"getsysinfo.json", racadm(func(a *idrac.Admin) (interface{}, error) {
+			info, err := a.GetSysInfo()
+			racInfo = info
+			return info, err
+		})},
+		{"sel.json", webAPI(func(c idrac.HostClient) (interface{}, error) { return c.GetSEL() })},
+		{"sensors.json", webAPI(func(c idrac.HostClient) (interface{}, error) {
+			sensors, err := c.GetSensors()
+			if err != nil {
+				return nil, err
+			}
+			h.renameSensors(hostID, sensors)
+			return sensors, nil
+		})},
+		{"power.json", webAPI(func(c idrac.HostClient) (interface{}, error) { return c.GetPowerState() })},
+		{"raclog.txt", racadm(func(a *idrac.Admin) (interface{}, error) { return a.GetRACLog() })},
+		{"racdump.txt", racadm(func(a *idrac.Admin) (interface{}, error) { return a.RACDump() })},
+	}
+
+	manifest := &diagManifest{Host: hostID, Generated: time.Now().UTC(), Manager: version.Get()}
+	manifest.Files = make([]*diagFile, len(collectors))
+	var wg sync.WaitGroup
+	for i, c := range collectors {
+		f := &diagFile{Name: c.name}
+		manifest.Files[i] = f
+		wg.Add(1)
+		go func(fn func() (interface{}, error)) {
+			defer wg.Done()
+			start := time.Now()
+			v, err := fn()
+			f.DurationMS = time.Since(start).Milliseconds()
+			if err == nil {
+				f.data, err = diagEncode(v)
+			}
+			if err != nil {
+				f.err, f.Error = err, err.Error()
+				return
+			}
+			f.OK, f.Bytes = true, len(f.data)
+		}(c.fn)
+	}
+	wg.Wait()
+
+	if manifest.okCount() == 0 {
+		handleError(w, manifest.Files[0].err)
+		return
+	}
+	manifest.Firmware = diagFirmware(sysInfo, racInfo)
+
+	stamp := manifest.Generated.Format("20060102-150405")
+	dir := fmt.Sprintf("%s-diag-%s", hostID, stamp)
+	var buf bytes.Buffer
+	var err error
+	if format == "zip" {
+		err = writeDiagZip(&buf, dir, manifest)
+	} else {
+		err = writeDiagTarGz(&buf, dir, manifest)
+	}
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	log.Printf("audit: diagnostic bundle for %s (%d of %d collectors ok)", hostID, manifest.okCount(), len(manifest.Files))
+	w.Header().Set("Content-Type", map[string]string{"zip": "application/zip", "tar.gz": "application/gzip"}[format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, dir, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes()) //nolint:errcheck
+}
+
+func (m *diagManifest) okCount() int {
+	n := 0
+	for _, f := range m.Files {
+		if f.OK {
+			n++
+		}
+	}
+	return n
+}
+
+// diagEncode renders collector output: text verbatim, anything else as
+// indented JSON.
+func diagEncode(v interface{}) ([]byte, error) {
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.MarshalIndent(v, "", "  ")
+}
+
+// diagFirmware collects the firmware versions either system info source
+// reported.
+func diagFirmware(web *idrac.SystemInfo, rac *idrac.RACSysInfo) map[string]string {
+	fw := make(map[string]string)
+	set := func(key, v string) {
+		if v != "" && fw[key] == "" {
+			fw[key] = v
+		}
+	}
+	if web != nil {
+		set("bios", web.BIOSVersion)
+		set("idrac", web.FWVersion)
+		set("lifecycleController", web.LCCVersion)
+	}
+	if rac != nil {
+		set("bios", rac.BIOSVersion)
+		set("idrac", rac.FirmwareVersion)
+		set("idracBuild", rac.FirmwareBuild)
+	}
+	if len(fw) == 0 {
+		return nil
+	}
+	return fw
+}
+
+// diagEntries returns the bundle's files in order, manifest first.
+func diagEntries(m *diagManifest) ([]*diagFile, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	entries := []*diagFile{{Name: "manifest.json", data: data}}
+	for _, f := range m.Files {
+		if f.OK {
+			entries = append(entries, f)
+		}
+	}
+	return entries, nil
+}
+
+func writeDiagTarGz(buf *bytes.Buffer, dir string, m *diagManifest) error {
+	entries, err := diagEntries(m)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range entries {
+		hdr := &tar.Header{Name: dir + "/" + f.Name, Mode: 0o644, Size: int64(len(f.data)), ModTime: m.Generated}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeDiagZip(buf *bytes.Buffer, dir string, m *diagManifest) error {
+	entries, err := diagEntries(m)
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(buf)
+	for _, f := range entries {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: dir + "/" + f.Name, Method: zip.Deflate, Modified: m.Generated})
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestGetDiagBundle(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{
+			// Nothing listens on port 1, so the RACADM collectors fail fast.
+			"s1": {Host: addr, Username: "root", Password: "calvin", SSHPort: 1},
+		}},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+	r := chi.NewRouter()
+	r.Get("/hosts/{hostID}/diagnostics", h.GetDiagBundle)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/s1/diagnostics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/gzip" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	gz, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string][]byte)
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[path.Base(hdr.Name)] = data
+	}
+	for _, name := range []string{"manifest.json", "system.json", "sel.json", "sensors.json", "power.json"} {
+		if files[name] == nil {
+			t.Errorf("bundle is missing %s", name)
+		}
+	}
+	if files["racdump.txt"] != nil {
+		t.Error("failed collector's file should be left out")
+	}
+
+	var manifest struct {
+		Firmware map[string]string `json:"firmware"`
+		Files    []struct {
+			Name  string `json:"name"`
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		} `json:"files"`
+	}
+	if err := json.Unmarshal(files["manifest.json"], &manifest); err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Firmware["bios"] != "6.6.0" {
+		t.Errorf("firmware = %v, want the mock's BIOS version", manifest.Firmware)
+	}
+	for _, f := range manifest.Files {
+		racadm := f.Name == "getsysinfo.json" || f.Name == "raclog.txt" || f.Name == "racdump.txt"
+		if f.OK == racadm || racadm && f.Error == "" {
+			t.Errorf("manifest entry %+v", f)
+		}
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/s1/diagnostics?format=zip", nil))
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil || len(zr.File) != 5 {
+		t.Fatalf("zip bundle: %v, %d files", err, len(zr.File))
+	}
+}
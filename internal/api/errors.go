@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+// statusError is implemented by the idrac, ssh, and ipmi error types,
+// which know whether a failure was a rejected login, a timeout, or a
+// missing resource.
+type statusError interface {
+	error
+	HTTPStatus() int
+	ErrorCode() string
+}
+
+// apiError is an error response: HTTP status, stable code, and message.
+type apiError struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"error"`
+	// RequestID is filled in from the X-Request-ID response header.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+func (e *apiError) Error() string { return e.Message }
+
+// toAPIError maps err to a response. The outermost classified error in
+// the chain decides the status; context timeouts are 504 and anything
+// unclassified is 500.
+func toAPIError(err error) *apiError {
+	var ae *apiError
+	if errors.As(err, &ae) {
+		return ae
+	}
+	var se statusError
+	if errors.As(err, &se) {
+		return &apiError{Status: se.HTTPStatus(), Code: se.ErrorCode(), Message: err.Error()}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &apiError{Status: http.StatusGatewayTimeout, Code: "timeout", Message: err.Error()}
+	}
+	return &apiError{Status: http.StatusInternalServerError, Code: "internal", Message: err.Error()}
+}
+
+// handleError writes err as a JSON error response with the status and
+// code it maps to. Use it for errors from the iDRAC, RACADM, or IPMI;
+// writeError remains for request validation with a known status.
+func handleError(w http.ResponseWriter, err error) {
+	ae := *toAPIError(err)
+	ae.RequestID = w.Header().Get(requestIDHeader)
+	writeJSON(w, ae.Status, &ae)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/internal/ipmi"
+	"github.com/williamzujkowski/idrac6-manager/internal/ssh"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+func TestToAPIError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"bad credentials", fmt.Errorf("login: %w", idrac.ErrBadCredentials), http.StatusBadGateway, idrac.CodeAuthFailed},
+		{"enterprise", idrac.ErrRequiresEnterprise, http.StatusNotImplemented, idrac.CodeRequiresEnterprise},
+		{"ssh timeout", fmt.Errorf("getting SEL: %w", &ssh.Error{Status: http.StatusGatewayTimeout, Code: ssh.CodeTimeout, Err: errors.New("i/o timeout")}), http.StatusGatewayTimeout, ssh.CodeTimeout},
+		{"ipmi invalid", &ipmi.Error{Status: http.StatusBadRequest, Code: ipmi.CodeInvalid, Err: errors.New("unknown boot device")}, http.StatusBadRequest, ipmi.CodeInvalid},
+		{"deadline", fmt.Errorf("waiting: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, "timeout"},
+		{"unclassified", errors.New("boom"), http.StatusInternalServerError, "internal"},
+	}
+	for _, tt := range tests {
+		ae := toAPIError(tt.err)
+		if ae.Status != tt.status || ae.Code != tt.code || ae.Message != tt.err.Error() {
+			t.Errorf("%s: toAPIError = %+v, want %d %s", tt.name, ae, tt.status, tt.code)
+		}
+	}
+}
+
+func TestHandleError_BadCredentials(t *testing.T) {
+	server := idractest.NewServer(idractest.Options{Username: "root", Password: "calvin"})
+	defer server.Close()
+
+	cfg := &Config{Hosts: map[string]*HostConfig{
+		"s1": {Host: server.Addr(), Username: "root", Password: "wrong"},
+	}}
+	w := httptest.NewRecorder()
+	NewRouter(cfg).ServeHTTP(w, httptest.NewRequest("GET", "/api/hosts/s1/power", nil))
+
+	var body map[string]string
+	json.Unmarshal(w.Body.Bytes(), &body) //nolint:errcheck
+	if w.Code != http.StatusBadGateway || body["code"] != idrac.CodeAuthFailed || body["error"] == "" {
+		t.Errorf("response = %d %v, want 502 %s", w.Code, body, idrac.CodeAuthFailed)
+	}
+}
@@ -0,0 +1,251 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// Sensor event types.
+const (
+	eventThresholdBreach    = "threshold_breach"
+	eventThresholdRecovered = "threshold_recovered"
+	eventIntrusionDetected  = "intrusion_detected"
+)
+
+// eventHeartbeat is how often an idle event stream sends a comment to keep
+// proxies from closing it.
+const eventHeartbeat = 30 * time.Second
+
+// eventBuffer is how many events a slow subscriber may fall behind by
+// before further events are dropped for it.
+const eventBuffer = 64
+
+// sensorEvent is a synthetic SEL-style event: raised by the background
+// refresher when a sensor crosses its critical threshold or recovers, or
+// when a read finds the chassis intrusion switch newly open. Value, Unit,
+// and Threshold are set for threshold events, State for intrusion.
+type sensorEvent struct {
+	ID        int64     `json:"id"`
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	Host      string    `json:"host"`
+	Sensor    string    `json:"sensor"`
+	RawName   string    `json:"rawName,omitempty"`
+	Value     *float64  `json:"value,omitempty"`
+	Unit      string    `json:"unit,omitempty"`
+	Threshold float64   `json:"threshold,omitempty"`
+	State     string    `json:"state,omitempty"`
+	Time      time.Time `json:"time"`
+}
+
+// eventHub fans sensor events out to stream subscribers and remembers
+// which sensors are in breach, so a persisting breach is reported once and
+// its recovery once.
+type eventHub struct {
+	mu       sync.Mutex
+	nextID   int64
+	subs     map[chan sensorEvent]struct{}
+	breached map[string]map[string]bool // host -> raw sensor name
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{
+		subs:     make(map[chan sensorEvent]struct{}),
+		breached: make(map[string]map[string]bool),
+	}
+}
+
+// subscribe returns a channel of future events and a function that
+// unsubscribes it.
+func (eh *eventHub) subscribe() (<-chan sensorEvent, func()) {
+	ch := make(chan sensorEvent, eventBuffer)
+	eh.mu.Lock()
+	eh.subs[ch] = struct{}{}
+	eh.mu.Unlock()
+	return ch, func() {
+		eh.mu.Lock()
+		delete(eh.subs, ch)
+		eh.mu.Unlock()
+	}
+}
+
+// publish numbers ev and sends it to every subscriber. Called with eh.mu
+// held. A subscriber whose buffer is full misses the event rather than
+// stalling the refresher.
+func (eh *eventHub) publish(ev sensorEvent) {
+	eh.nextID++
+	ev.ID = eh.nextID
+	for ch := range eh.subs {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// sensorThreshold returns a configured critical threshold for a reading:
+// a per-host SensorThresholds entry, then a global one. ok is false when
+// neither names the sensor and the iDRAC-reported thresholds apply.
+func (h *Handlers) sensorThreshold(hc *HostConfig, s idrac.SensorReading) (threshold float64, ok bool) {
+	raw := s.RawName
+	if raw == "" {
+		raw = s.Name
+	}
+	if hc != nil {
+		for name, v := range hc.SensorThresholds {
+			if strings.EqualFold(name, raw) {
+				return v, true
+			}
+		}
+	}
+	for name, v := range h.config.SensorThresholds {
+		if strings.EqualFold(name, raw) {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
+// criticalBounds returns a reading with only its critical thresholds, a
+// configured one replacing the iDRAC's on the side that matters for the
+// sensor type (the low side for fans, the high side otherwise), and the
+// threshold an event about it should name: the low one when the reading
+// is at or below it, else the high one.
+func (h *Handlers) criticalBounds(hc *HostConfig, s idrac.SensorReading, low, high bool) (idrac.SensorReading, float64) {
+	crit := idrac.SensorReading{Value: s.Value, MinCritical: s.MinCritical, Critical: s.Critical}
+	if v, ok := h.sensorThreshold(hc, s); ok {
+		if high {
+			crit.Critical = v
+		} else {
+			crit.MinCritical = v
+		}
+	}
+	if low && crit.MinCritical != 0 && (!high || crit.Critical == 0 || s.Value <= crit.MinCritical) {
+		return crit, crit.MinCritical
+	}
+	return crit, crit.Critical
+}
+
+// checkThresholds judges a host's readings against their critical
+// thresholds, on the sides that matter for each sensor type, and
+// publishes an event for each sensor that entered or left breach since
+// the last check. A sensor whose reading is unknown keeps its previous
+// state. Readings must already be renamed. It is a no-op without an
+// event hub.
+func (h *Handlers) checkThresholds(hostID string, data *idrac.SensorData) {
+	if h.events == nil {
+		return
+	}
+	hc, _ := h.hostConfig(hostID)
+	now := time.Now()
+
+	h.events.mu.Lock()
+	defer h.events.mu.Unlock()
+
+	prev := h.events.breached[hostID]
+	next := make(map[string]bool)
+	for _, group := range []struct {
+		readings []idrac.SensorReading
+		kind     string
+	}{{data.Temperatures, "temperatures"}, {data.Fans, "fans"}, {data.Voltages, "voltages"}} {
+		low, high := idrac.HealthBounds(group.kind)
+		for _, s := range group.readings {
+			if s.Health == idrac.SensorUnknown {
+				if prev[s.RawName] {
+					next[s.RawName] = true
+				}
+				continue
+			}
+			crit, threshold := h.criticalBounds(hc, s, low, high)
+			health := idrac.SensorHealth(crit, low, high)
+			if health == "" {
+				continue
+			}
+			ev := sensorEvent{
+				Host:      hostID,
+				Sensor:    s.Name,
+				RawName:   s.RawName,
+				Value:     &s.Value,
+				Unit:      s.Unit,
+				Threshold: threshold,
+				Time:      now,
+			}
+			switch breach := health == idrac.SeverityCritical; {
+			case breach && !prev[s.RawName]:
+				ev.Type, ev.Severity = eventThresholdBreach, idrac.SeverityCritical
+				log.Printf("sensor %s on %s at %g %s breached critical threshold %g", s.Name, hostID, s.Value, s.Unit, threshold)
+				h.events.publish(ev)
+			case !breach && prev[s.RawName]:
+				ev.Type, ev.Severity = eventThresholdRecovered, idrac.SeverityNormal
+				log.Printf("sensor %s on %s recovered at %g %s", s.Name, hostID, s.Value, s.Unit)
+				h.events.publish(ev)
+			}
+			if health == idrac.SeverityCritical {
+				next[s.RawName] = true
+			}
+		}
+	}
+	h.events.breached[hostID] = next
+}
+
+// Events streams sensor threshold events as Server-Sent Events: an
+// "event:" line with the type, an "id:" line, and the JSON event as
+// "data:". The "host" query parameter limits the stream to one host.
+// Events come from the background refresher, so the stream requires it.
+func (h *Handlers) Events(w http.ResponseWriter, r *http.Request) {
+	if h.events == nil {
+		writeError(w, http.StatusServiceUnavailable, "the event stream requires the background refresher (--refresh-interval)")
+		return
+	}
+	host := r.URL.Query().Get("host")
+	if host != "" {
+		if _, ok := h.hostConfig(host); !ok {
+			writeError(w, http.StatusNotFound, "host not found: "+host)
+			return
+		}
+	}
+
+	events, unsubscribe := h.events.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	rc.Flush() //nolint:errcheck
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case ev := <-events:
+			if host != "" && ev.Host != host {
+				continue
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, ev.Type, data); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-h.done:
+			return
+		}
+		rc.Flush() //nolint:errcheck
+	}
+}
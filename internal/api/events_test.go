@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func tempReading(value float64) *idrac.SensorData {
+	return &idrac.SensorData{
+		Temperatures: []idrac.SensorReading{
+			{Name: "Inlet", RawName: "System Board Ambient Temp", Value: value, Unit: "degrees C", Critical: 47},
+			{Name: "CPU1 Temp", RawName: "CPU1 Temp", Value: 45, Unit: "degrees C", Critical: 90},
+		},
+	}
+}
+
+func TestCheckThresholds(t *testing.T) {
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{"s1": {}}},
+		events: newEventHub(),
+	}
+	events, unsubscribe := h.events.subscribe()
+	defer unsubscribe()
+
+	next := func() *sensorEvent {
+		select {
+		case ev := <-events:
+			return &ev
+		default:
+			return nil
+		}
+	}
+
+	h.checkThresholds("s1", tempReading(30))
+	if ev := next(); ev != nil {
+		t.Fatalf("event below threshold: %+v", ev)
+	}
+
+	h.checkThresholds("s1", tempReading(48))
+	ev := next()
+	if ev == nil || ev.Type != eventThresholdBreach || ev.Sensor != "Inlet" || ev.Threshold != 47 || ev.Severity != idrac.SeverityCritical {
+		t.Fatalf("breach event = %+v", ev)
+	}
+
+	h.checkThresholds("s1", tempReading(50))
+	if ev := next(); ev != nil {
+		t.Fatalf("persisting breach re-reported: %+v", ev)
+	}
+
+	h.checkThresholds("s1", tempReading(40))
+	if ev := next(); ev == nil || ev.Type != eventThresholdRecovered || *ev.Value != 40 {
+		t.Fatalf("recovery event = %+v", ev)
+	}
+
+	// A global override applies unless the host overrides it.
+	h.config.SensorThresholds = map[string]float64{"cpu1 temp": 40}
+	h.checkThresholds("s1", tempReading(30))
+	if ev := next(); ev == nil || ev.RawName != "CPU1 Temp" || ev.Threshold != 40 {
+		t.Fatalf("global override event = %+v", ev)
+	}
+	h.config.Hosts["s1"].SensorThresholds = map[string]float64{"CPU1 Temp": 60}
+	h.checkThresholds("s1", tempReading(30))
+	if ev := next(); ev == nil || ev.Type != eventThresholdRecovered || ev.Threshold != 60 {
+		t.Fatalf("host override event = %+v", ev)
+	}
+}
+
+func TestCheckThresholds_PerType(t *testing.T) {
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{"s1": {}}},
+		events: newEventHub(),
+	}
+	events, unsubscribe := h.events.subscribe()
+	defer unsubscribe()
+
+	fans := func(value float64, health string) *idrac.SensorData {
+		return &idrac.SensorData{Fans: []idrac.SensorReading{
+			{Name: "FAN 1", RawName: "FAN 1", Value: value, Unit: "RPM", MinCritical: 720, Health: health},
+		}}
+	}
+	next := func() *sensorEvent {
+		select {
+		case ev := <-events:
+			return &ev
+		default:
+			return nil
+		}
+	}
+
+	// Fans breach on the low side: a fast fan is fine, a stopped one is not.
+	h.checkThresholds("s1", fans(9000, idrac.SeverityNormal))
+	if ev := next(); ev != nil {
+		t.Fatalf("event for a fast fan: %+v", ev)
+	}
+	h.checkThresholds("s1", fans(0, idrac.SeverityCritical))
+	if ev := next(); ev == nil || ev.Type != eventThresholdBreach || ev.Threshold != 720 {
+		t.Fatalf("stopped fan event = %+v", ev)
+	}
+
+	// An unreadable fan neither recovers nor re-breaches.
+	h.checkThresholds("s1", fans(0, idrac.SensorUnknown))
+	if ev := next(); ev != nil {
+		t.Fatalf("event for an unknown reading: %+v", ev)
+	}
+	h.checkThresholds("s1", fans(1440, idrac.SeverityNormal))
+	if ev := next(); ev == nil || ev.Type != eventThresholdRecovered {
+		t.Fatalf("recovery event = %+v", ev)
+	}
+
+	// A configured threshold replaces the low bound for fans.
+	h.config.SensorThresholds = map[string]float64{"FAN 1": 1500}
+	h.checkThresholds("s1", fans(1440, idrac.SeverityNormal))
+	if ev := next(); ev == nil || ev.Type != eventThresholdBreach || ev.Threshold != 1500 {
+		t.Fatalf("override event = %+v", ev)
+	}
+}
+
+func TestEventsStream(t *testing.T) {
+	h := &Handlers{config: &Config{Hosts: map[string]*HostConfig{"s1": {}, "s2": {}}}}
+
+	rec := httptest.NewRecorder()
+	h.Events(rec, httptest.NewRequest(http.MethodGet, "/api/events", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without refresher: status %d, want 503", rec.Code)
+	}
+
+	h.events = newEventHub()
+	srv := httptest.NewServer(http.HandlerFunc(h.Events))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?host=s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	// Wait for the subscription before publishing.
+	deadline := time.Now().Add(time.Second)
+	for {
+		h.events.mu.Lock()
+		n := len(h.events.subs)
+		h.events.mu.Unlock()
+		if n == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stream never subscribed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	h.checkThresholds("s2", tempReading(48))
+	h.checkThresholds("s1", tempReading(48))
+
+	sc := bufio.NewScanner(resp.Body)
+	var lines []string
+	for sc.Scan() && sc.Text() != "" {
+		lines = append(lines, sc.Text())
+	}
+	if len(lines) != 3 || lines[0] != "id: 2" || lines[1] != "event: threshold_breach" {
+		t.Fatalf("event = %q, want only the s1 breach", lines)
+	}
+	var ev sensorEvent
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &ev); err != nil {
+		t.Fatal(err)
+	}
+	if ev.Host != "s1" || *ev.Value != 48 {
+		t.Errorf("event data = %+v", ev)
+	}
+}
+
+func TestEventsStream_EndsWithRouter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	srv := httptest.NewServer(NewRouterContext(ctx, &Config{
+		Hosts:           map[string]*HostConfig{"s1": {}},
+		RefreshInterval: time.Hour,
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/api/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("event stream still open after the router's context ended")
+	}
+}
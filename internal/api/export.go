@@ -0,0 +1,156 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"gopkg.in/yaml.v3"
+)
+
+// maxImportSize bounds the body accepted by ImportConfig.
+const maxImportSize = 1 << 20
+
+// exportConfig returns the current hosts in config file form. Passwords are
+// blanked unless withCredentials is set.
+func (h *Handlers) exportConfig(withCredentials bool) *FileConfig {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+
+	fc := &FileConfig{Hosts: []FileHost{}}
+	for _, id := range h.config.hostIDs() {
+		hc := *h.config.Hosts[id]
+		if !withCredentials {
+			hc.Password = ""
+			hc.Credentials = redactCredentials(hc.Credentials)
+		}
+		fc.Hosts = append(fc.Hosts, FileHost{ID: id, HostConfig: hc})
+	}
+	return fc
+}
+
+// redactCredentials returns a copy of creds with passwords blanked.
+func redactCredentials(creds []idrac.Credential) []idrac.Credential {
+	if creds == nil {
+		return nil
+	}
+	out := make([]idrac.Credential, len(creds))
+	for i, cr := range creds {
+		out[i] = idrac.Credential{Label: cr.Label, Username: cr.Username}
+	}
+	return out
+}
+
+// restoreCredentials fills blank fallback passwords from the current
+// credential with the same label and username, reporting false if one has
+// no match.
+func restoreCredentials(creds, current []idrac.Credential) bool {
+	for i := range creds {
+		if creds[i].Password != "" {
+			continue
+		}
+		found := false
+		for _, cur := range current {
+			if cur.Label == creds[i].Label && cur.Username == creds[i].Username {
+				creds[i].Password = cur.Password
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ExportConfig returns the host map for backup or migration, as JSON or,
+// with format=yaml, as a file usable with --config. Passwords are redacted
+// unless credentials=true, which is only honored when an API key or Basic
+// login protects the server.
+func (h *Handlers) ExportConfig(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	withCredentials := q.Get("credentials") == "true"
+	if withCredentials && !h.config.authRequired() {
+		writeError(w, http.StatusForbidden, "exporting credentials requires an API key or Basic login to be configured")
+		return
+	}
+
+	fc := h.exportConfig(withCredentials)
+	switch q.Get("format") {
+	case "", "json":
+		writeJSON(w, http.StatusOK, fc)
+	case "yaml":
+		data, err := yaml.Marshal(fc)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(data) //nolint:errcheck
+	default:
+		writeError(w, http.StatusBadRequest, "format must be json or yaml")
+	}
+}
+
+// ImportConfig adds and updates hosts from an export (JSON, or YAML with a
+// YAML content type). Hosts with a blank password keep their current one,
+// so a redacted export of this manager can be re-imported. With
+// replace=true, hosts missing from the import are removed.
+func (h *Handlers) ImportConfig(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxImportSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading request body: "+err.Error())
+		return
+	}
+
+	// Unknown fields are rejected, as in the config file, so a misspelled
+	// key is reported rather than silently dropped.
+	var fc FileConfig
+	if strings.Contains(r.Header.Get("Content-Type"), "yaml") {
+		dec := yaml.NewDecoder(bytes.NewReader(body))
+		dec.KnownFields(true)
+		err = dec.Decode(&fc)
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&fc)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid config: "+err.Error())
+		return
+	}
+
+	if err := fc.validate(func(id string) bool { _, ok := h.hostConfig(id); return ok }); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid config: "+err.Error())
+		return
+	}
+
+	next := fc.HostMap()
+	for id, hc := range next {
+		current, ok := h.hostConfig(id)
+		if !ok {
+			if hc.Password == "" {
+				writeError(w, http.StatusConflict, fmt.Sprintf("host %q was removed during import", id))
+				return
+			}
+			current = &HostConfig{}
+		}
+		if hc.Password == "" {
+			hc.Password = current.Password
+		}
+		if !restoreCredentials(hc.Credentials, current.Credentials) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid config: host %q has a fallback credential without a password", id))
+			return
+		}
+	}
+
+	res := h.applyHosts(next, r.URL.Query().Get("replace") == "true")
+	log.Printf("Config imported: added=%v removed=%v changed=%v", res.Added, res.Removed, res.Changed)
+	writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func newExportHandlers(apiKey string) *Handlers {
+	return &Handlers{
+		config: &Config{
+			APIKey: apiKey,
+			Hosts: map[string]*HostConfig{
+				"r710": {Name: "R710", Host: "10.0.0.1", Username: "root", Password: "calvin", Tags: []string{"prod"},
+					Credentials: []idrac.Credential{{Label: "rotated", Username: "root", Password: "s3cret"}}},
+				"r610": {Name: "R610", Host: "10.0.0.2", Username: "root", Password: "hunter2"},
+			},
+		},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+}
+
+func TestExportConfig(t *testing.T) {
+	h := newExportHandlers("")
+
+	w := httptest.NewRecorder()
+	h.ExportConfig(w, httptest.NewRequest("GET", "/api/config/export", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if body := w.Body.String(); strings.Contains(body, "calvin") || strings.Contains(body, "s3cret") || !strings.Contains(body, `"id":"r710"`) {
+		t.Errorf("export = %s, want hosts without passwords", body)
+	}
+
+	w = httptest.NewRecorder()
+	h.ExportConfig(w, httptest.NewRequest("GET", "/api/config/export?credentials=true", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("credentials without API key: status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	// A YAML export with credentials is a loadable --config file.
+	h = newExportHandlers("secret")
+	w = httptest.NewRecorder()
+	h.ExportConfig(w, httptest.NewRequest("GET", "/api/config/export?format=yaml&credentials=true", nil))
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, w.Body.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile(export) error = %v\n%s", err, w.Body.String())
+	}
+	if hc := fc.HostMap()["r710"]; hc == nil || hc.Password != "calvin" || !hc.hasTag("prod") {
+		t.Errorf("exported r710 = %+v, want full settings", hc)
+	}
+}
+
+func TestImportConfig(t *testing.T) {
+	src := newExportHandlers("")
+	w := httptest.NewRecorder()
+	src.ExportConfig(w, httptest.NewRequest("GET", "/api/config/export", nil))
+	redacted := w.Body.String()
+
+	// Re-importing a redacted export keeps current passwords.
+	h := newExportHandlers("")
+	w = httptest.NewRecorder()
+	h.ImportConfig(w, httptest.NewRequest("POST", "/api/config/import", strings.NewReader(redacted)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+	if hc, _ := h.hostConfig("r710"); hc.Password != "calvin" || hc.Credentials[0].Password != "s3cret" {
+		t.Errorf("r710 = %+v, want passwords kept", hc)
+	}
+
+	// Unknown keys are rejected rather than dropped.
+	w = httptest.NewRecorder()
+	h.ImportConfig(w, httptest.NewRequest("POST", "/api/config/import",
+		strings.NewReader(`{"hosts":[{"id":"r710","host":"10.0.0.1","username":"root","pasword":"typo"}]}`)))
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "pasword") {
+		t.Errorf("unknown field: status = %d %s, want 400 naming it", w.Code, w.Body.String())
+	}
+
+	// New hosts need a password.
+	w = httptest.NewRecorder()
+	h.ImportConfig(w, httptest.NewRequest("POST", "/api/config/import",
+		strings.NewReader(`{"hosts":[{"id":"new","host":"10.0.0.3","username":"root"}]}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("new host without password: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	w = httptest.NewRecorder()
+	h.ImportConfig(w, httptest.NewRequest("POST", "/api/config/import?replace=true",
+		strings.NewReader(`{"hosts":[{"id":"new","host":"10.0.0.3","username":"root","password":"x"}]}`)))
+	var res reloadResult
+	json.NewDecoder(w.Body).Decode(&res)
+	if strings.Join(res.Added, ",") != "new" || strings.Join(res.Removed, ",") != "r610,r710" {
+		t.Errorf("replace import = %+v, want added=[new] removed=[r610 r710]", res)
+	}
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// firmwareResetWarning is returned with every started update.
+const firmwareResetWarning = "the iDRAC resets when the update completes and is unreachable for several minutes; " +
+	"the host keeps running, but BIOS and other component updates only apply on its next reboot"
+
+// StartFirmwareUpdate starts a RACADM firmware update from an image URL
+// and returns a job ID to poll. Because a bad image can leave the iDRAC
+// unusable, the caller must pass confirm=true, and only one update per
+// host may be in flight: concurrent starts are serialized per host, and a
+// previous job whose state cannot be read counts as still running.
+func (h *Handlers) StartFirmwareUpdate(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		ImageURL string `json:"imageUrl"`
+		Confirm  bool   `json:"confirm"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := idrac.ValidateFirmwareURL(req.ImageURL); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !req.Confirm {
+		writeError(w, http.StatusBadRequest, "firmware update requires confirm=true: "+firmwareResetWarning)
+		return
+	}
+	setSpanAction(r, "firmware update")
+	if !h.authorize(w, r, "firmware.update", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if _, starting := h.firmwareStarting.LoadOrStore(hostID, struct{}{}); starting {
+		writeError(w, http.StatusConflict, "a firmware update is already being started on "+hostID)
+		return
+	}
+	defer h.firmwareStarting.Delete(hostID)
+
+	if prev, ok := h.firmwareJobs.Load(hostID); ok {
+		// A pending fwupdate just means the iDRAC is idle ("Ready for
+		// firmware update"); a pending queued job is still waiting to run.
+		job, err := admin.FirmwareJobStatus(prev.(string))
+		if err != nil {
+			writeError(w, http.StatusConflict, fmt.Sprintf("cannot confirm firmware update %s has finished: %v", prev, err))
+			return
+		}
+		if job.State == idrac.FirmwareJobRunning || job.State == idrac.FirmwareJobPending && strings.HasPrefix(job.ID, "JID_") {
+			writeError(w, http.StatusConflict, fmt.Sprintf("firmware update %s is still %s", job.ID, job.State))
+			return
+		}
+	}
+
+	log.Printf("audit: starting firmware update on %s from %s", hostID, req.ImageURL)
+	jobID, err := admin.StartFirmwareUpdate(req.ImageURL)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if jobID == "" {
+		jobID = fmt.Sprintf("fwupdate-%d", time.Now().Unix())
+	}
+	h.firmwareJobs.Store(hostID, jobID)
+	log.Printf("audit: firmware update on %s started as %s", hostID, jobID)
+
+	writeJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "started",
+		"jobId":   jobID,
+		"warning": firmwareResetWarning,
+	})
+}
+
+// GetFirmwareJob reports a firmware update's progress. Job queue IDs
+// (JID_...) are looked up directly; fwupdate IDs must be the host's most
+// recent update, since iDRAC6 only reports the status of the current one.
+func (h *Handlers) GetFirmwareJob(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	jobID := chi.URLParam(r, "jobID")
+
+	if !strings.HasPrefix(jobID, "JID_") {
+		if prev, ok := h.firmwareJobs.Load(hostID); !ok || prev.(string) != jobID {
+			writeError(w, http.StatusNotFound, "unknown firmware job: "+jobID)
+			return
+		}
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	job, err := admin.FirmwareJobStatus(jobID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
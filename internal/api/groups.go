@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// groupInfo is the JSON view of a host group.
+type groupInfo struct {
+	Name string `json:"name"`
+	// Hosts are the resolved members: listed hosts that still exist plus
+	// hosts carrying one of the group's tags, in ID order.
+	Hosts []string `json:"hosts"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// group resolves a group's members. ok is false if no such group exists.
+func (h *Handlers) group(name string) (groupInfo, bool) {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+
+	g, ok := h.config.Groups[name]
+	if !ok {
+		return groupInfo{}, false
+	}
+	members := make(map[string]bool)
+	for _, id := range g.Hosts {
+		if _, ok := h.config.Hosts[id]; ok {
+			members[id] = true
+		}
+	}
+	for id, hc := range h.config.Hosts {
+		for _, tag := range g.Tags {
+			if hc.hasTag(tag) {
+				members[id] = true
+			}
+		}
+	}
+
+	info := groupInfo{Name: name, Hosts: make([]string, 0, len(members)), Tags: g.Tags}
+	for id := range members {
+		info.Hosts = append(info.Hosts, id)
+	}
+	sort.Strings(info.Hosts)
+	return info, true
+}
+
+// groupNames returns the configured group names in sorted order.
+func (h *Handlers) groupNames() []string {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+	names := make([]string, 0, len(h.config.Groups))
+	for name := range h.config.Groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ListGroups returns every configured group with its resolved members.
+func (h *Handlers) ListGroups(w http.ResponseWriter, _ *http.Request) {
+	groups := []groupInfo{}
+	for _, name := range h.groupNames() {
+		if g, ok := h.group(name); ok {
+			groups = append(groups, g)
+		}
+	}
+	writeJSON(w, http.StatusOK, groups)
+}
+
+// GetGroup returns one group with its resolved members.
+func (h *Handlers) GetGroup(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "group")
+	g, ok := h.group(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "group not found: "+name)
+		return
+	}
+	writeJSON(w, http.StatusOK, g)
+}
+
+// groupPowerResult is the response body for SetGroupPower.
+type groupPowerResult struct {
+	Group   string                `json:"group"`
+	Action  string                `json:"action"`
+	Results map[string]hostResult `json:"results"`
+	// Skipped lists disabled members, which are left alone.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// SetGroupPower sends a power action to every enabled member of a group
+// concurrently and reports each host's outcome, with the same
+// authorization, no-op, and in-flight checks as a single host's power
+// endpoint; "force": true skips the latter two. shutdown-force and
+// waiting for the new state are single-host only. Up to ?concurrency=
+// hosts are acted on at once.
+func (h *Handlers) SetGroupPower(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "group")
+	g, ok := h.group(name)
+	if !ok {
+		writeError(w, http.StatusNotFound, "group not found: "+name)
+		return
+	}
+	concurrency, err := h.bulkConcurrency(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var req struct {
+		Action string `json:"action"`
+		Force  bool   `json:"force,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if _, ok := idrac.ValidPowerActions[req.Action]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown power action: %q (valid for groups: on, off, restart, reset, nmi, shutdown)", req.Action))
+		return
+	}
+	setSpanAction(r, "group power "+req.Action)
+
+	res := groupPowerResult{Group: name, Action: req.Action}
+	var targets []string
+	for _, id := range g.Hosts {
+		if hc, ok := h.hostConfig(id); ok && hc.Disabled {
+			res.Skipped = append(res.Skipped, id)
+			continue
+		}
+		targets = append(targets, id)
+	}
+
+	log.Printf("audit: group power %s on %s (%s)", req.Action, name, strings.Join(targets, ","))
+	res.Results = forEachHost(r.Context(), targets, concurrency, func(ctx context.Context, hostID string) (interface{}, error) {
+		if err := h.authorizeErr(r, "power."+req.Action, hostID); err != nil {
+			return nil, err
+		}
+		return h.applyPower(ctx, hostID, req.Action, req.Force)
+	})
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// applyPower sends a web API power action to one host through the same
+// checkPower and sendPower steps as SetPower.
+func (h *Handlers) applyPower(ctx context.Context, hostID, action string, force bool) (*powerActionResult, error) {
+	client, err := h.getClient(ctx, hostID)
+	if err != nil {
+		return nil, err
+	}
+	prior, err := h.checkPower(client, hostID, action, force)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.sendPower(client, hostID, action, prior.State); err != nil {
+		return nil, err
+	}
+	return &powerActionResult{Status: "ok", Action: action, PriorState: prior.Status}, nil
+}
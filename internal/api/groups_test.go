@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGroupMembers(t *testing.T) {
+	h := &Handlers{config: &Config{
+		Hosts: map[string]*HostConfig{
+			"a": {Tags: []string{"rack1"}},
+			"b": {Tags: []string{"Rack1"}},
+			"c": {},
+			"d": {},
+		},
+		Groups: map[string]*GroupConfig{
+			"rack1": {Hosts: []string{"c", "a", "gone"}, Tags: []string{"rack1"}},
+		},
+	}}
+
+	g, ok := h.group("rack1")
+	if !ok {
+		t.Fatal("group rack1 not found")
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(g.Hosts, want) {
+		t.Errorf("members = %v, want %v", g.Hosts, want)
+	}
+	if _, ok := h.group("rack2"); ok {
+		t.Error("unknown group should not resolve")
+	}
+}
+
+func TestSetGroupPower(t *testing.T) {
+	on, off := mockIDRAC(t), mockIDRAC(t)
+	off.SetPowerOn(false)
+
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"on":    {Host: on.Addr(), Username: "root", Password: "calvin", Tags: []string{"lab"}},
+			"off":   {Host: off.Addr(), Username: "root", Password: "calvin", Tags: []string{"lab"}},
+			"maint": {Host: "127.0.0.1:1", Username: "root", Password: "calvin", Tags: []string{"lab"}, Disabled: true},
+		},
+		Groups: map[string]*GroupConfig{"lab": {Tags: []string{"lab"}}},
+	}
+	router := NewRouter(cfg)
+
+	req := httptest.NewRequest("POST", "/api/groups/lab/power", strings.NewReader(`{"action":"on"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	var res struct {
+		Results map[string]struct {
+			Error string `json:"error"`
+		} `json:"results"`
+		Skipped []string `json:"skipped"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Results) != 2 || res.Results["off"].Error != "" || !strings.Contains(res.Results["on"].Error, "already on (pass force=true") {
+		t.Errorf("results = %+v, want off powered on and on refused", res.Results)
+	}
+	if !reflect.DeepEqual(res.Skipped, []string{"maint"}) {
+		t.Errorf("skipped = %v, want [maint]", res.Skipped)
+	}
+	if !off.PowerOn() {
+		t.Error("off host was not powered on")
+	}
+
+	for path, want := range map[string]int{
+		"/api/groups/nope/power": http.StatusNotFound,
+		"/api/groups/lab/power":  http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest("POST", path, strings.NewReader(`{"action":"shutdown-force"}`))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d", path, w.Code, want)
+		}
+	}
+}
@@ -1,14 +1,24 @@
 package api
 
 import (
+	"bytes"
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/williamzujkowski/idrac6-manager/internal/idrac"
+	"github.com/williamzujkowski/idrac6-manager/internal/ipmi"
+	"github.com/williamzujkowski/idrac6-manager/internal/version"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
 )
 
 type contextKey string
@@ -17,83 +27,226 @@ const hostConfigKey contextKey = "hostConfig"
 
 // Handlers holds API handler dependencies.
 type Handlers struct {
-	config  *Config
-	clients sync.Map // map[string]*idrac.Client
+	config *Config
+	// hostsMu guards config.Hosts, which AddHost and reloads modify.
+	hostsMu sync.RWMutex
+	pool    *idrac.Pool
 	vmedia  sync.Map // map[string]*idrac.VirtualMedia
+	admin   sync.Map // map[string]*idrac.Admin
+	// licenses caches detected license tiers, which never change at
+	// runtime, and recent detection failures.
+	licenses sync.Map // map[string]idrac.License or licenseFailure
+	ipmi     sync.Map // map[string]*ipmi.Client
+	demo     sync.Map // map[string]*idrac.DemoClient
+	// pending records the last power action per host until its state settles.
+	pending  sync.Map // map[string]pendingPower
+	breakers sync.Map // map[string]*breaker
+	// firmwareJobs records each host's most recent firmware update job ID.
+	firmwareJobs sync.Map // map[string]string
+	// firmwareStarting marks hosts with a firmware update being started,
+	// so concurrent requests cannot both pass the busy check.
+	firmwareStarting sync.Map // map[string]struct{}
+	// intrusion caches each host's latest chassis intrusion reading.
+	intrusion sync.Map // map[string]*intrusionReading
+	// lastGood holds recent successful responses served when a fetch
+	// fails within Config.StaleWindow.
+	lastGood sync.Map // map[lastGoodKey]lastGood
+	stats    *managerStats
+	// refresher polls hosts in the background; nil when disabled.
+	refresher *refresher
+	// events carries sensor threshold events from the refresher to event
+	// stream subscribers; nil when the refresher is disabled.
+	events *eventHub
+	// done is closed when the router's context ends, stopping background
+	// work and open event streams.
+	done <-chan struct{}
 }
 
-// getClient returns or creates an iDRAC client for the given host.
-func (h *Handlers) getClient(hostID string) (*idrac.Client, error) {
-	if cached, ok := h.clients.Load(hostID); ok {
-		return cached.(*idrac.Client), nil
-	}
+// hostConfig returns the configuration for a host ID.
+func (h *Handlers) hostConfig(id string) (*HostConfig, bool) {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+	hc, ok := h.config.Hosts[id]
+	return hc, ok
+}
 
-	hostCfg, ok := h.config.Hosts[hostID]
-	if !ok {
-		return nil, fmt.Errorf("host %q not found", hostID)
-	}
+// hostIDs returns the configured host IDs in sorted order.
+func (h *Handlers) hostIDs() []string {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+	return h.config.hostIDs()
+}
 
-	client := idrac.NewClient(hostCfg.Host, hostCfg.Username, hostCfg.Password)
-	if err := client.Login(); err != nil {
-		return nil, fmt.Errorf("login to %s failed: %w", hostCfg.Host, err)
+// activeHostIDs returns the IDs of hosts that are not disabled, in sorted
+// order. Anything that polls or reports on the fleet should use it.
+func (h *Handlers) activeHostIDs() []string {
+	h.hostsMu.RLock()
+	defer h.hostsMu.RUnlock()
+	ids := h.config.hostIDs()
+	active := ids[:0]
+	for _, id := range ids {
+		if !h.config.Hosts[id].Disabled {
+			active = append(active, id)
+		}
 	}
-
-	h.clients.Store(hostID, client)
-	return client, nil
+	return active
 }
 
-// hostCtx middleware extracts the host ID and validates it exists.
+// hostCtx middleware extracts the host ID and validates it exists and is
+// not disabled.
 func (h *Handlers) hostCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		hostID := chi.URLParam(r, "hostID")
-		hostCfg, ok := h.config.Hosts[hostID]
+		hostCfg, ok := h.hostConfig(hostID)
 		if !ok {
 			writeError(w, http.StatusNotFound, "host not found: "+hostID)
 			return
 		}
+		if hostCfg.Disabled {
+			writeError(w, http.StatusLocked, "host is disabled for maintenance: "+hostID)
+			return
+		}
 
 		ctx := context.WithValue(r.Context(), hostConfigKey, hostCfg)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// Health returns service health status.
+// Health returns service health status and the running version.
 func (h *Handlers) Health(w http.ResponseWriter, _ *http.Request) {
+	v := version.Get()
 	writeJSON(w, http.StatusOK, map[string]string{
 		"status":  "ok",
 		"service": "idrac6-manager",
+		"version": v.Version,
+		"commit":  v.Commit,
+	})
+}
+
+// Version returns the manager's build information.
+func (h *Handlers) Version(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, version.Get())
+}
+
+// uiConfig is the deployment settings the web UI adapts to at runtime.
+type uiConfig struct {
+	// BasePath is the path the manager is mounted under ("" for the root).
+	BasePath string `json:"basePath"`
+	// AuthRequired means API requests need an API key.
+	AuthRequired bool       `json:"authRequired"`
+	Version      string     `json:"version"`
+	Features     uiFeatures `json:"features"`
+}
+
+// uiFeatures are the optional server features enabled in this deployment.
+type uiFeatures struct {
+	ReadOnly     bool `json:"readOnly"`
+	CachedReads  bool `json:"cachedReads"`
+	Events       bool `json:"events"`
+	ConfigReload bool `json:"configReload"`
+	RawData      bool `json:"rawData"`
+}
+
+// UIConfig returns the settings the embedded web UI needs to adapt to this
+// deployment, so one build works under any base path and auth setup. It
+// is served without an API key: the UI needs it to know to ask for one,
+// and it holds nothing secret.
+func (h *Handlers) UIConfig(w http.ResponseWriter, _ *http.Request) {
+	cfg := h.config
+	writeJSON(w, http.StatusOK, uiConfig{
+		BasePath:     normalizeBasePath(cfg.BasePath),
+		AuthRequired: cfg.APIKey != "",
+		Version:      version.Get().Version,
+		Features: uiFeatures{
+			ReadOnly:     cfg.ReadOnly,
+			CachedReads:  h.refresher != nil,
+			Events:       h.events != nil,
+			ConfigReload: cfg.ConfigPath != "",
+			RawData:      cfg.Debug && cfg.authRequired(),
+		},
 	})
 }
 
 // ListHosts returns all configured hosts (without credentials).
-func (h *Handlers) ListHosts(w http.ResponseWriter, _ *http.Request) {
+// Query parameters: "name" (substring, case-insensitive), "host" (prefix),
+// "tag", and "sort" (id, name, or host; default id).
+func (h *Handlers) ListHosts(w http.ResponseWriter, r *http.Request) {
 	type hostInfo struct {
-		ID   string `json:"id"`
-		Name string `json:"name"`
-		Host string `json:"host"`
+		ID       string   `json:"id"`
+		Name     string   `json:"name"`
+		Host     string   `json:"host"`
+		Location string   `json:"location,omitempty"`
+		Tags     []string `json:"tags,omitempty"`
+		Notes    string   `json:"notes,omitempty"`
+		Disabled bool     `json:"disabled,omitempty"`
+	}
+
+	q := r.URL.Query()
+	tag := q.Get("tag")
+	name := strings.ToLower(q.Get("name"))
+	hostPrefix := q.Get("host")
+
+	sortBy := q.Get("sort")
+	if sortBy == "" {
+		sortBy = "id"
+	}
+	if sortBy != "id" && sortBy != "name" && sortBy != "host" {
+		writeError(w, http.StatusBadRequest, "sort must be one of: id, name, host")
+		return
 	}
 
-	var hosts []hostInfo
-	for id, cfg := range h.config.Hosts {
+	hosts := []hostInfo{}
+	for _, id := range h.hostIDs() {
+		cfg, ok := h.hostConfig(id)
+		if !ok {
+			continue // removed by a concurrent reload
+		}
+		if tag != "" && !cfg.hasTag(tag) {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(cfg.Name), name) {
+			continue
+		}
+		if hostPrefix != "" && !strings.HasPrefix(cfg.Host, hostPrefix) {
+			continue
+		}
 		hosts = append(hosts, hostInfo{
-			ID:   id,
-			Name: cfg.Name,
-			Host: cfg.Host,
+			ID:       id,
+			Name:     cfg.Name,
+			Host:     cfg.Host,
+			Location: cfg.Location,
+			Tags:     cfg.Tags,
+			Notes:    cfg.Notes,
+			Disabled: cfg.Disabled,
 		})
 	}
 
+	// Hosts are already in ID order; re-sort stably for other keys.
+	switch sortBy {
+	case "name":
+		sort.SliceStable(hosts, func(i, j int) bool { return hosts[i].Name < hosts[j].Name })
+	case "host":
+		sort.SliceStable(hosts, func(i, j int) bool { return hosts[i].Host < hosts[j].Host })
+	}
+
 	writeJSON(w, http.StatusOK, hosts)
 }
 
 // AddHost adds a new host configuration at runtime.
 func (h *Handlers) AddHost(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		ID       string `json:"id"`
-		Name     string `json:"name"`
-		Host     string `json:"host"`
-		Username string `json:"username"`
-		Password string `json:"password"`
-		SSHPort  int    `json:"sshPort,omitempty"`
+		ID          string             `json:"id"`
+		Name        string             `json:"name"`
+		Host        string             `json:"host"`
+		Username    string             `json:"username"`
+		Password    string             `json:"password"`
+		Credentials []idrac.Credential `json:"credentials,omitempty"`
+		SSHPort     int                `json:"sshPort,omitempty"`
+		LoginForm   *idrac.LoginForm   `json:"loginForm,omitempty"`
+		CABundle    string             `json:"caBundle,omitempty"`
+		Location    string             `json:"location,omitempty"`
+		Tags        []string           `json:"tags,omitempty"`
+		Notes       string             `json:"notes,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -105,42 +258,128 @@ func (h *Handlers) AddHost(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "id, host, username, and password are required")
 		return
 	}
+	if _, err := idrac.ParseHost(req.Host); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	h.config.Hosts[req.ID] = &HostConfig{
-		Name:     req.Name,
-		Host:     req.Host,
-		Username: req.Username,
-		Password: req.Password,
-		SSHPort:  req.SSHPort,
+	hc := &HostConfig{
+		Name:        req.Name,
+		Host:        req.Host,
+		Username:    req.Username,
+		Password:    req.Password,
+		Credentials: req.Credentials,
+		SSHPort:     req.SSHPort,
+		LoginForm:   req.LoginForm,
+		CABundle:    req.CABundle,
+		Location:    req.Location,
+		Tags:        req.Tags,
+		Notes:       req.Notes,
 	}
 
+	h.hostsMu.Lock()
+	h.config.Hosts[req.ID] = hc
+	h.hostsMu.Unlock()
+
 	writeJSON(w, http.StatusCreated, map[string]string{"status": "added", "id": req.ID})
 }
 
-// GetPower returns the current power state.
+// GetPower returns the current power state. When the web API reports an
+// indeterminate state, it is resolved over IPMI; "source" says which was used.
+// With cached=true the latest background refresh is returned instead, with
+// an Age header, if there is one. Within --stale-window, a failed read is
+// answered with the last good one.
 func (h *Handlers) GetPower(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
-	client, err := h.getClient(hostID)
+	if h.refresher != nil && r.URL.Query().Get("cached") == "true" {
+		if reading, at, ok := h.refresher.cachedPower(hostID); ok {
+			setAge(w, at)
+			writeJSON(w, http.StatusOK, reading)
+			return
+		}
+	}
+	client, err := h.getClient(r.Context(), hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		if !h.serveStale(w, hostID, stalePower, err) {
+			handleError(w, err)
+		}
 		return
 	}
 
 	status, err := client.GetPowerState()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		if !h.serveStale(w, hostID, stalePower, err) {
+			handleError(w, err)
+		}
 		return
 	}
 
-	writeJSON(w, http.StatusOK, status)
+	reading := h.resolvePower(hostID, status)
+	h.remember(hostID, stalePower, reading)
+	writeJSON(w, http.StatusOK, reading)
+}
+
+// GetPowerDetail returns the power state from the web API with the input
+// power, peak, potential draw, and cap RACADM reports in cfgServerPower,
+// omitting what the firmware lacks.
+func (h *Handlers) GetPowerDetail(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	client, err := h.getClient(r.Context(), hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	detail, err := admin.GetPowerDetail()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	status, err := client.GetPowerState()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	detail.PowerStatus = *status
+
+	writeJSON(w, http.StatusOK, detail)
+}
+
+// resolvePower resolves an indeterminate web API power state over IPMI.
+func (h *Handlers) resolvePower(hostID string, status *idrac.PowerStatus) *powerReading {
+	var fallback ipmiPowerReader
+	if status.State == idrac.PowerInvalid {
+		if ipmiClient, err := h.getIPMI(hostID); err == nil {
+			fallback = ipmiClient
+		}
+	}
+	reading, err := resolvePowerState(status, fallback)
+	if err != nil {
+		log.Printf("IPMI power fallback for %s failed: %v", hostID, err)
+	}
+	return reading
 }
 
-// SetPower executes a power action.
+// SetPower executes a power action and reports the power state before it.
+// With "wait": true the handler also polls until the state settles and
+// includes the resulting newState. Actions that would be a no-op (on while
+// on) or that race an earlier action still settling get 409 Conflict
+// unless "force": true is set, since iDRAC6 ignores them or fails vaguely.
 func (h *Handlers) SetPower(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
 
 	var req struct {
 		Action string `json:"action"`
+		Wait   bool   `json:"wait,omitempty"`
+		Force  bool   `json:"force,omitempty"`
+		// GraceSeconds is how long shutdown-force waits before forcing
+		// the host off.
+		GraceSeconds int `json:"graceSeconds,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid request body")
@@ -148,102 +387,421 @@ func (h *Handlers) SetPower(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if req.Action == "" {
-		writeError(w, http.StatusBadRequest, "action is required (on, off, restart, reset, nmi, shutdown)")
+		writeError(w, http.StatusBadRequest, "action is required (on, off, restart, reset, nmi, shutdown, shutdown-force)")
+		return
+	}
+	if _, ok := idrac.ValidPowerActions[req.Action]; !ok && req.Action != actionShutdownForce {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown power action: %q", req.Action))
+		return
+	}
+	grace := defaultShutdownGrace
+	if req.GraceSeconds != 0 {
+		grace = time.Duration(req.GraceSeconds) * time.Second
+		if req.Action != actionShutdownForce || grace < 0 || grace > maxShutdownGrace {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("graceSeconds applies to shutdown-force and must be 1-%d", int(maxShutdownGrace.Seconds())))
+			return
+		}
+	}
+	setSpanAction(r, "power "+req.Action)
+	if !h.authorize(w, r, "power."+req.Action, hostID) {
 		return
 	}
 
-	client, err := h.getClient(hostID)
+	client, err := h.getClient(r.Context(), hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
-	if err := client.SetPowerByName(req.Action); err != nil {
-		writeError(w, http.StatusBadRequest, err.Error())
+	prior, err := h.checkPower(client, hostID, req.Action, req.Force)
+	if err != nil {
+		handleError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "action": req.Action})
+	result := powerActionResult{
+		Status:     "ok",
+		Action:     req.Action,
+		PriorState: prior.Status,
+	}
+
+	if req.Action == actionShutdownForce {
+		h.pending.Store(hostID, pendingPower{action: req.Action, want: idrac.PowerOff, at: time.Now()})
+		path, after, err := shutdownForce(r.Context(), client, hostID, grace)
+		if err != nil {
+			handleError(w, err)
+			return
+		}
+		result.Path = path
+		result.NewState = after.Status
+		writeJSON(w, http.StatusOK, result)
+		return
+	}
+
+	if err := h.sendPower(client, hostID, req.Action, prior.State); err != nil {
+		powerActionError(w, err)
+		return
+	}
+
+	if req.Wait {
+		after, err := waitForPowerState(r.Context(), client, req.Action, prior.State)
+		if err != nil {
+			writeError(w, http.StatusGatewayTimeout, err.Error())
+			return
+		}
+		result.NewState = after.Status
+	}
+
+	writeJSON(w, http.StatusOK, result)
+}
+
+// GetPowerStats returns peak and trailing min/max/average power consumption.
+func (h *Handlers) GetPowerStats(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	stats, err := admin.GetPowerStats()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// ResetPowerStats clears the peak power consumption counter.
+func (h *Handlers) ResetPowerStats(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	if !h.authorize(w, r, "power.stats.reset", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := admin.ResetPowerStats(); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "reset"})
 }
 
-// GetSensors returns all sensor readings.
+// GetSensors returns all sensor readings. Within --stale-window, a
+// failed read is answered with the last good one (see serveStale).
 func (h *Handlers) GetSensors(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
-	client, err := h.getClient(hostID)
+	if h.refresher != nil && r.URL.Query().Get("cached") == "true" {
+		if sensors, at, ok := h.refresher.cachedSensors(hostID); ok {
+			h.renameSensors(hostID, sensors)
+			setAge(w, at)
+			writeJSON(w, http.StatusOK, sensors)
+			return
+		}
+	}
+	client, err := h.getClient(r.Context(), hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		if !h.serveStale(w, hostID, staleSensors, err) {
+			handleError(w, err)
+		}
 		return
 	}
 
 	sensors, err := client.GetSensors()
+	if err == nil {
+		err = sensorsUnavailable(sensors)
+	}
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		if !h.serveStale(w, hostID, staleSensors, err) {
+			handleError(w, err)
+		}
 		return
 	}
 
+	h.renameSensors(hostID, sensors)
+	h.remember(hostID, staleSensors, sensors)
 	writeJSON(w, http.StatusOK, sensors)
 }
 
-// GetSystemInfo returns system identification info.
+// GetSystemInfo returns system identification info, or within
+// --stale-window the last good copy if the iDRAC is unreachable.
 func (h *Handlers) GetSystemInfo(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
-	client, err := h.getClient(hostID)
+	client, err := h.getClient(r.Context(), hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		if !h.serveStale(w, hostID, staleSysInfo, err) {
+			handleError(w, err)
+		}
 		return
 	}
 
 	info, err := client.GetSystemInfo()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		if !h.serveStale(w, hostID, staleSysInfo, err) {
+			handleError(w, err)
+		}
 		return
 	}
 
+	h.remember(hostID, staleSysInfo, info)
 	writeJSON(w, http.StatusOK, info)
 }
 
-// GetSEL returns the System Event Log.
+// GetExtendedSystemInfo returns the web API's system info merged with
+// RACADM getsysinfo (iDRAC network settings, firmware build, etc.). If only
+// one source answers, its data is returned with a warning naming the other.
+func (h *Handlers) GetExtendedSystemInfo(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var web *idrac.SystemInfo
+	client, webErr := h.getClient(r.Context(), hostID)
+	if webErr == nil {
+		web, webErr = client.GetSystemInfo()
+	}
+
+	var rac *idrac.RACSysInfo
+	admin, racErr := h.requestAdmin(r, hostID)
+	if racErr == nil {
+		rac, racErr = admin.GetSysInfo()
+	}
+
+	if webErr != nil && racErr != nil {
+		handleError(w, webErr)
+		return
+	}
+
+	resp := struct {
+		*idrac.ExtendedSystemInfo
+		Warning string `json:"warning,omitempty"`
+	}{ExtendedSystemInfo: idrac.MergeSystemInfo(web, rac)}
+	if webErr != nil {
+		resp.Warning = "web API unavailable: " + webErr.Error()
+	} else if racErr != nil {
+		resp.Warning = "RACADM unavailable: " + racErr.Error()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetSEL returns the System Event Log, oldest entry first, as a page.
+// "since" (record ID) and "last" (entry count) fetch only the newest
+// entries via RACADM, which is much cheaper than transferring the whole
+// SEL for every poll. Otherwise "severity" filters the log and "offset"
+// and "limit" select a window of it, with the limit held to
+// MaxSELEntries. Without a window the newest MaxSELEntries entries are
+// returned, with truncated set if older ones were left out. A SEL larger
+// than SELStreamThreshold is read via RACADM instead of the web API: a
+// window by range reads, and a full read by streaming the newest
+// MaxSELEntries (or, uncapped, every entry) in chunks.
 func (h *Handlers) GetSEL(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
-	client, err := h.getClient(hostID)
+	q := r.URL.Query()
+
+	if q.Has("since") || q.Has("last") {
+		for _, p := range []string{"severity", "offset", "limit"} {
+			if q.Has(p) {
+				writeError(w, http.StatusBadRequest, p+" cannot be combined with since or last")
+				return
+			}
+		}
+		h.getSELIncremental(w, r, hostID)
+		return
+	}
+
+	severity, err := parseSeverityFilter(q.Get("severity"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	offset, limit, err := pageParams(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	windowed := q.Has("offset") || q.Has("limit")
+	maxEntries := h.maxSELEntries()
+	if windowed && maxEntries > 0 && (limit == 0 || limit > maxEntries) {
+		limit = maxEntries
+	}
+
+	if h.config.SELStreamThreshold > 0 && severity == nil {
+		if admin, total, ok := h.largeSEL(r, hostID); ok {
+			if !windowed {
+				skip := 0
+				if maxEntries > 0 && total > maxEntries {
+					skip = total - maxEntries
+				}
+				writeSELStream(w, admin, skip, idrac.SELChunkSize)
+				return
+			}
+			if limit == 0 {
+				limit = total
+			}
+			page, err := selRangePage(admin, total, offset, limit)
+			if err != nil {
+				handleError(w, err)
+				return
+			}
+			writeJSON(w, http.StatusOK, page)
+			return
+		}
+	}
+
+	client, err := h.getClient(r.Context(), hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sel, err := client.GetSEL()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if windowed {
+		writeJSON(w, http.StatusOK, newPage(filterSEL(sel.Entries, severity), offset, limit))
+		return
+	}
+	writeJSON(w, http.StatusOK, capSEL(sel.Entries, severity, maxEntries))
+}
+
+// GetSELSummary returns SEL entry counts by normalized severity (normal,
+// warning, critical) and the most recent critical entry, so dashboards can
+// poll log health without transferring the log.
+func (h *Handlers) GetSELSummary(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getClient(r.Context(), hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
 	sel, err := client.GetSEL()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, sel.Summarize())
+}
+
+// getSELIncremental serves a partial SEL read via RACADM: the entries
+// after a record ID ("since") or the newest N ("last").
+func (h *Handlers) getSELIncremental(w http.ResponseWriter, r *http.Request, hostID string) {
+	q := r.URL.Query()
+	since, last := q.Get("since"), q.Get("last")
+	if since != "" && last != "" {
+		writeError(w, http.StatusBadRequest, "since and last are mutually exclusive")
+		return
+	}
+
+	admin, err := h.getAdmin(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var entries []idrac.SELEntry
+	var total int
+	if last == "" {
+		n := 0
+		if since != "" {
+			var convErr error
+			if n, convErr = strconv.Atoi(since); convErr != nil || n < 0 {
+				writeError(w, http.StatusBadRequest, "since must be a non-negative record ID")
+				return
+			}
+		}
+		entries, total, err = admin.GetSELSince(n)
+	} else {
+		n, convErr := strconv.Atoi(last)
+		if convErr != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, "last must be a positive integer")
+			return
+		}
+		entries, total, err = admin.GetSELLast(n)
+	}
+	if err != nil {
+		handleError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, sel)
+	writeJSON(w, http.StatusOK, selTail(entries, total))
 }
 
-// ClearSEL clears the System Event Log.
+// ClearSEL clears the System Event Log. Clearing is irreversible, so the
+// caller must pass confirm=true (query or JSON body), and the current
+// entries are written to the audit log first. If they cannot be read the
+// log is left untouched.
 func (h *Handlers) ClearSEL(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
-	client, err := h.getClient(hostID)
+
+	confirmed := r.URL.Query().Get("confirm") == "true"
+	if !confirmed && r.ContentLength != 0 {
+		var req struct {
+			Confirm bool `json:"confirm"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+		confirmed = req.Confirm
+	}
+	if !confirmed {
+		writeError(w, http.StatusBadRequest, "clearing the SEL is irreversible; pass confirm=true")
+		return
+	}
+	if !h.authorize(w, r, "sel.clear", hostID) {
+		return
+	}
+
+	client, err := h.getClient(r.Context(), hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sel, err := client.GetSEL()
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, fmt.Errorf("reading SEL before clear: %w", err))
 		return
 	}
+	auditSEL(hostID, sel.Entries)
 
 	if err := client.ClearSEL(); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, map[string]string{"status": "cleared"})
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "cleared", "cleared": len(sel.Entries)})
+}
+
+// auditSEL writes SEL entries to the log so a cleared SEL is not lost.
+func auditSEL(hostID string, entries []idrac.SELEntry) {
+	log.Printf("audit: clearing %d SEL entries on %s", len(entries), hostID)
+	for _, e := range entries {
+		line, _ := json.Marshal(e)
+		log.Printf("audit: sel %s %s", hostID, line)
+	}
 }
 
 // getVMedia returns or creates a VirtualMedia manager for the given host.
 func (h *Handlers) getVMedia(hostID string) (*idrac.VirtualMedia, error) {
+	if h.config.Demo {
+		return nil, idrac.ErrDemo
+	}
 	if cached, ok := h.vmedia.Load(hostID); ok {
 		return cached.(*idrac.VirtualMedia), nil
 	}
 
-	hostCfg, ok := h.config.Hosts[hostID]
+	hostCfg, ok := h.hostConfig(hostID)
 	if !ok {
 		return nil, fmt.Errorf("host %q not found", hostID)
 	}
@@ -253,9 +811,10 @@ func (h *Handlers) getVMedia(hostID string) (*idrac.VirtualMedia, error) {
 		sshPort = 22
 	}
 
-	vm := idrac.NewVirtualMedia(hostCfg.Host, sshPort, hostCfg.Username, hostCfg.Password)
-	h.vmedia.Store(hostID, vm)
-	return vm, nil
+	// LoadOrStore so concurrent first requests share one VirtualMedia and
+	// with it the lock that serializes mounts.
+	vm, _ := h.vmedia.LoadOrStore(hostID, idrac.NewVirtualMedia(idrac.HostName(hostCfg.Host), sshPort, hostCfg.Username, hostCfg.Password, h.config.racadmOptions(hostCfg)...))
+	return vm.(*idrac.VirtualMedia), nil
 }
 
 // GetVirtualMedia returns the current virtual media mount status.
@@ -263,20 +822,21 @@ func (h *Handlers) GetVirtualMedia(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
 	vm, err := h.getVMedia(hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
-	status, err := vm.GetStatus()
+	status, err := vm.GetStatusContext(r.Context())
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, status)
 }
 
-// MountVirtualMedia mounts an ISO/IMG via RACADM.
+// MountVirtualMedia mounts an ISO/IMG via RACADM. It answers 409 while
+// another mount or unmount on the host is in progress.
 func (h *Handlers) MountVirtualMedia(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
 
@@ -291,44 +851,864 @@ func (h *Handlers) MountVirtualMedia(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "url is required")
 		return
 	}
+	if !h.authorize(w, r, "virtualmedia.mount", hostID) {
+		return
+	}
 
 	vm, err := h.getVMedia(hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
-	if err := vm.Mount(req.URL); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	if err := vm.MountContext(r.Context(), req.URL); err != nil {
+		handleError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]string{"status": "mounted", "url": req.URL})
 }
 
-// UnmountVirtualMedia unmounts the current virtual media.
+// UnmountVirtualMedia unmounts the current virtual media, or answers 409
+// while a mount or unmount on the host is in progress.
 func (h *Handlers) UnmountVirtualMedia(w http.ResponseWriter, r *http.Request) {
 	hostID := chi.URLParam(r, "hostID")
+	if !h.authorize(w, r, "virtualmedia.unmount", hostID) {
+		return
+	}
 	vm, err := h.getVMedia(hostID)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+		handleError(w, err)
 		return
 	}
 
-	if err := vm.Unmount(); err != nil {
-		writeError(w, http.StatusInternalServerError, err.Error())
+	if err := vm.UnmountContext(r.Context()); err != nil {
+		handleError(w, err)
 		return
 	}
 
 	writeJSON(w, http.StatusOK, map[string]string{"status": "unmounted"})
 }
 
-func writeJSON(w http.ResponseWriter, status int, v interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(v) //nolint:errcheck
+// loginCredential returns the username and password for RACADM and IPMI.
+// With fallback credentials configured, this is whichever one the web
+// client last logged in with, if it is connected; otherwise the primary.
+func (h *Handlers) loginCredential(hostID string, hc *HostConfig) (string, string) {
+	if len(hc.Credentials) > 0 {
+		if client, ok := h.pool.Lookup(hostID); ok {
+			cred := client.ActiveCredential()
+			return cred.Username, cred.Password
+		}
+	}
+	return hc.Username, hc.Password
 }
 
-func writeError(w http.ResponseWriter, status int, message string) {
-	writeJSON(w, status, map[string]string{"error": message})
+// getAdmin returns or creates a RACADM-backed Admin for the given host.
+func (h *Handlers) getAdmin(hostID string) (*idrac.Admin, error) {
+	if h.config.Demo {
+		return nil, idrac.ErrDemo
+	}
+	if cached, ok := h.admin.Load(hostID); ok {
+		return cached.(*idrac.Admin), nil
+	}
+
+	hostCfg, ok := h.hostConfig(hostID)
+	if !ok {
+		return nil, fmt.Errorf("host %q not found", hostID)
+	}
+
+	username, password := h.loginCredential(hostID, hostCfg)
+	admin := idrac.NewAdmin(idrac.HostName(hostCfg.Host), hostCfg.SSHPort, username, password, h.config.racadmOptions(hostCfg)...)
+	h.admin.Store(hostID, admin)
+	return admin, nil
+}
+
+// requestAdmin is getAdmin bound to r's context, so RACADM commands are
+// cancelled, and their SSH sessions closed, when the client goes away.
+func (h *Handlers) requestAdmin(r *http.Request, hostID string) (*idrac.Admin, error) {
+	admin, err := h.getAdmin(hostID)
+	if err != nil {
+		return nil, err
+	}
+	return admin.WithContext(r.Context()), nil
+}
+
+// licenseRetryAfter is how long a failed license detection is cached, so
+// an unreachable SSH service costs one attempt per interval rather than
+// one per request.
+const licenseRetryAfter = time.Minute
+
+// licenseFailure is a cached failed license detection.
+type licenseFailure struct {
+	err error
+	at  time.Time
+}
+
+// getLicense returns the host's license tier, detecting it via RACADM on
+// first use. A failed detection reports LicenseUnknown and is cached for
+// licenseRetryAfter before it is tried again.
+func (h *Handlers) getLicense(hostID string) (idrac.License, error) {
+	if cached, ok := h.licenses.Load(hostID); ok {
+		switch v := cached.(type) {
+		case idrac.License:
+			return v, nil
+		case licenseFailure:
+			if time.Since(v.at) < licenseRetryAfter {
+				return idrac.LicenseUnknown, v.err
+			}
+		}
+	}
+
+	admin, err := h.getAdmin(hostID)
+	if err != nil {
+		h.licenses.Store(hostID, licenseFailure{err: err, at: time.Now()})
+		return idrac.LicenseUnknown, err
+	}
+
+	license, err := admin.DetectLicense()
+	if err != nil {
+		h.licenses.Store(hostID, licenseFailure{err: err, at: time.Now()})
+		return idrac.LicenseUnknown, err
+	}
+	h.licenses.Store(hostID, license)
+	return license, nil
+}
+
+// requireEnterprise rejects requests for Enterprise-only features on
+// iDRAC6 Express hosts. If detection fails the request proceeds, so a
+// flaky SSH connection never blocks an otherwise working feature.
+func (h *Handlers) requireEnterprise(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hostID := chi.URLParam(r, "hostID")
+		license, err := h.getLicense(hostID)
+		if err != nil {
+			log.Printf("License detection for %s failed: %v", hostID, err)
+		}
+		if license == idrac.LicenseExpress {
+			handleError(w, idrac.ErrRequiresEnterprise)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// GetCapabilities returns the host's detected license and feature set.
+func (h *Handlers) GetCapabilities(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	license, err := h.getLicense(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, license.Capabilities())
+}
+
+// ListSessions returns a page of the active iDRAC sessions ("offset" and
+// "limit" query parameters).
+func (h *Handlers) ListSessions(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	offset, limit, err := pageParams(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sessions, err := admin.ListSessions()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newPage(sessions, offset, limit))
+}
+
+// GetConsoleSessions reports active virtual console sessions, so users can
+// see whether someone is already on the KVM before connecting.
+func (h *Handlers) GetConsoleSessions(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	sessions, err := admin.ConsoleSessions()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"count":    len(sessions),
+		"inUse":    len(sessions) > 0,
+		"sessions": sessions,
+	})
+}
+
+// KillSession closes an active iDRAC session.
+func (h *Handlers) KillSession(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	sessionID := chi.URLParam(r, "sessionID")
+	if _, err := strconv.Atoi(sessionID); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid session id: "+sessionID)
+		return
+	}
+
+	if !h.authorize(w, r, "session.kill", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := admin.KillSession(sessionID); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "closed", "id": sessionID})
+}
+
+// GetIDRACNetwork returns the iDRAC's own network port configuration:
+// dedicated or shared-LOM mode, failover, link, speed, and VLAN, with
+// warnings for settings that cause intermittent management access.
+func (h *Handlers) GetIDRACNetwork(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	nic, err := admin.GetNICConfig()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, nic)
+}
+
+// GetIDRACAlerts returns the iDRAC's SNMP trap and email alert
+// destinations and whether alerting is enabled, with warnings for
+// settings under which no alert is delivered.
+func (h *Handlers) GetIDRACAlerts(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	alerts, err := admin.GetAlertConfig()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, alerts)
+}
+
+// GetIDRACName returns the iDRAC's own DNS name.
+func (h *Handlers) GetIDRACName(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	name, err := admin.GetIDRACName()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"name": name})
+}
+
+// SetIDRACName sets the iDRAC's own DNS name.
+func (h *Handlers) SetIDRACName(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := idrac.ValidateIDRACName(req.Name); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	setSpanAction(r, "idrac name")
+	if !h.authorize(w, r, "idrac.name", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := admin.SetIDRACName(req.Name); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "name": req.Name})
+}
+
+// GetAssetTag returns the server's asset tag.
+func (h *Handlers) GetAssetTag(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	tag, err := admin.GetAssetTag()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"assetTag": tag})
+}
+
+// SetAssetTag sets the server's asset tag.
+func (h *Handlers) SetAssetTag(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		AssetTag string `json:"assetTag"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := idrac.ValidateAssetTag(req.AssetTag); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	setSpanAction(r, "asset tag")
+	if !h.authorize(w, r, "assettag.set", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := admin.SetAssetTag(req.AssetTag); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "assetTag": req.AssetTag})
+}
+
+// GetLCD returns the front-panel LCD's user message and whether it is
+// displayed.
+func (h *Handlers) GetLCD(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	lcd, err := admin.GetLCDMessage()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lcd)
+}
+
+// SetLCD shows a message on the front-panel LCD, e.g. to label a server
+// under maintenance at the rack.
+func (h *Handlers) SetLCD(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := idrac.ValidateLCDMessage(req.Message); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	setSpanAction(r, "lcd message")
+	if !h.authorize(w, r, "lcd.set", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := admin.SetLCDMessage(req.Message); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"status": "ok", "message": req.Message, "displayed": true})
+}
+
+// ChangePassword changes an iDRAC account's password via RACADM. The
+// caller must supply the account's current password. When the manager
+// logs in with the account, as its primary login or a fallback
+// credential, every stored password for it is updated and cached clients
+// are evicted so the next request logs in with the new one. Passwords are
+// never logged or echoed.
+func (h *Handlers) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	hc, ok := h.hostConfig(hostID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+
+	var req struct {
+		Username        string `json:"username"`
+		CurrentPassword string `json:"currentPassword"`
+		NewPassword     string `json:"newPassword"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Username == "" {
+		req.Username = hc.Username
+	}
+	if req.CurrentPassword == "" {
+		writeError(w, http.StatusBadRequest, "currentPassword is required")
+		return
+	}
+	if err := idrac.ValidatePassword(req.NewPassword); err != nil {
+		writeError(w, http.StatusBadRequest, "newPassword: "+err.Error())
+		return
+	}
+	setSpanAction(r, "change password")
+	if !h.authorize(w, r, "idrac.password", hostID) {
+		return
+	}
+
+	managed := len(managedPasswords(hc, req.Username)) > 0
+	if !h.verifyPassword(r.Context(), hc, req.Username, req.CurrentPassword) {
+		writeError(w, http.StatusForbidden, "current password is incorrect")
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	index, err := admin.UserIndex(req.Username)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if err := admin.SetUserPassword(index, req.NewPassword); err != nil {
+		handleError(w, err)
+		return
+	}
+	log.Printf("Changed password of iDRAC user %s on %s", req.Username, hostID)
+
+	resp := map[string]interface{}{"status": "changed", "username": req.Username, "configUpdated": managed}
+	if managed {
+		h.hostsMu.Lock()
+		h.config.Hosts[hostID] = hc.withPassword(req.Username, req.NewPassword)
+		h.hostsMu.Unlock()
+		h.evictHost(hostID)
+
+		if h.config.ConfigPath != "" {
+			resp["warning"] = "the config file still has the old password; update it before the next reload"
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// verifyPassword checks an account's current password: against the stored
+// passwords for an account the manager logs in with, otherwise by a test
+// login.
+func (h *Handlers) verifyPassword(ctx context.Context, hc *HostConfig, username, password string) bool {
+	if stored := managedPasswords(hc, username); len(stored) > 0 {
+		match := 0
+		for _, p := range stored {
+			match |= subtle.ConstantTimeCompare([]byte(password), []byte(p))
+		}
+		return match == 1
+	}
+
+	if h.config.Demo {
+		return false
+	}
+	opts, err := h.config.clientOptions(&HostConfig{CABundle: hc.CABundle, LoginForm: hc.LoginForm})
+	if err != nil {
+		return false
+	}
+	client := idrac.NewClient(hc.Host, username, password, opts...)
+	if err := client.LoginContext(ctx); err != nil {
+		return false
+	}
+	client.Logout() //nolint:errcheck
+	return true
+}
+
+// managedPasswords returns the passwords stored for username on a host:
+// the primary login's and those of fallback credentials with that
+// username. It is empty for an account the manager does not log in with.
+func managedPasswords(hc *HostConfig, username string) []string {
+	var stored []string
+	if username == hc.Username {
+		stored = append(stored, hc.Password)
+	}
+	for _, cr := range hc.Credentials {
+		if cr.Username == username {
+			stored = append(stored, cr.Password)
+		}
+	}
+	return stored
+}
+
+// withPassword returns a copy of hc with the primary login and every
+// fallback credential for username set to password.
+func (hc *HostConfig) withPassword(username, password string) *HostConfig {
+	updated := *hc
+	if updated.Username == username {
+		updated.Password = password
+	}
+	updated.Credentials = slices.Clone(hc.Credentials)
+	for i := range updated.Credentials {
+		if updated.Credentials[i].Username == username {
+			updated.Credentials[i].Password = password
+		}
+	}
+	return &updated
+}
+
+// getIPMI returns or creates an IPMI client for the given host.
+func (h *Handlers) getIPMI(hostID string) (*ipmi.Client, error) {
+	if h.config.Demo {
+		return nil, idrac.ErrDemo
+	}
+	if cached, ok := h.ipmi.Load(hostID); ok {
+		return cached.(*ipmi.Client), nil
+	}
+
+	hostCfg, ok := h.hostConfig(hostID)
+	if !ok {
+		return nil, fmt.Errorf("host %q not found", hostID)
+	}
+
+	var opts []ipmi.Option
+	if h.config.IPMIPersistent {
+		opts = append(opts, ipmi.WithPersistentSession())
+	}
+	if h.config.SourceAddress != nil {
+		opts = append(opts, ipmi.WithSourceAddress(h.config.SourceAddress))
+	}
+
+	username, password := h.loginCredential(hostID, hostCfg)
+	client := ipmi.NewClient(idrac.HostName(hostCfg.Host), hostCfg.IPMIPort, username, password, opts...)
+	if actual, loaded := h.ipmi.LoadOrStore(hostID, client); loaded {
+		return actual.(*ipmi.Client), nil
+	}
+	return client, nil
+}
+
+// GetIPMIPower returns the chassis power state as reported over IPMI.
+func (h *Handlers) GetIPMIPower(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	on, err := client.GetPowerStatus()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	status := "off"
+	if on {
+		status = "on"
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"on": on, "status": status})
+}
+
+// SetIPMIPower executes a chassis power action over IPMI. "shutdown" is a
+// graceful ACPI soft-off.
+func (h *Handlers) SetIPMIPower(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		Action string `json:"action"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if _, ok := ipmi.PowerActions[req.Action]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown IPMI power action: %q", req.Action))
+		return
+	}
+	setSpanAction(r, "ipmi-power "+req.Action)
+	if !h.authorize(w, r, "ipmi.power."+req.Action, hostID) {
+		return
+	}
+
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := client.SetPowerByName(req.Action); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok", "action": req.Action})
+}
+
+// GetBootOverride returns the current boot device override and whether it is persistent.
+func (h *Handlers) GetBootOverride(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	override, err := client.GetBootOverride()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, override)
+}
+
+// SetBootOnce sets a one-time boot device override. The iDRAC clears the
+// override after the next boot; there is deliberately no persistent variant
+// here so PXE cannot accidentally become the permanent boot device.
+func (h *Handlers) SetBootOnce(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		Device string `json:"device"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if _, ok := ipmi.BootDevices[req.Device]; !ok {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown boot device: %q", req.Device))
+		return
+	}
+	setSpanAction(r, "boot-once "+req.Device)
+	if !h.authorize(w, r, "boot.once", hostID) {
+		return
+	}
+
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	override, err := client.SetBootOnce(req.Device)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, override)
+}
+
+// GetBootOrder returns the first boot device the iDRAC puts ahead of the
+// BIOS boot sequence, via RACADM. iDRAC6 cannot read the sequence itself.
+func (h *Handlers) GetBootOrder(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	boot, err := admin.GetFirstBootDevice()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, boot)
+}
+
+// SetBootOrder sets the first boot device via RACADM, persistently unless
+// bootOnce is set. It applies at the next boot.
+func (h *Handlers) SetBootOrder(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		Device   string `json:"device"`
+		BootOnce bool   `json:"bootOnce"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	device, err := idrac.ValidateFirstBootDevice(req.Device)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	setSpanAction(r, "first boot device "+device)
+	if !h.authorize(w, r, "bootorder.set", hostID) {
+		return
+	}
+
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	log.Printf("audit: setting first boot device on %s to %s (once=%t)", hostID, device, req.BootOnce)
+	if err := admin.SetFirstBootDevice(device, req.BootOnce); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	boot, err := admin.GetFirstBootDevice()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, boot)
+}
+
+// RawData passes get=<keys> straight through to the iDRAC data API and
+// returns the unparsed body, for probing undocumented keys. It is only
+// routed with --debug and, like RawSet, requires an API key or Basic login
+// to be configured.
+func (h *Handlers) RawData(w http.ResponseWriter, r *http.Request) {
+	if !h.config.authRequired() {
+		writeError(w, http.StatusForbidden, "raw data access requires an API key or Basic login to be configured")
+		return
+	}
+
+	hostID := chi.URLParam(r, "hostID")
+	get := r.URL.Query().Get("get")
+	if get == "" {
+		writeError(w, http.StatusBadRequest, "get is required")
+		return
+	}
+
+	client, err := h.getClient(r.Context(), hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	body, err := client.GetContext(r.Context(), strings.Split(get, ",")...)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeRaw(w, body)
+}
+
+// RawSet passes {"set": "<param>"} straight through to the iDRAC data
+// API's set= and returns the unparsed body. Because it can change anything
+// the web UI can, it is a POST, so --read-only rejects it, and it is
+// audit-logged.
+func (h *Handlers) RawSet(w http.ResponseWriter, r *http.Request) {
+	if !h.config.authRequired() {
+		writeError(w, http.StatusForbidden, "raw data access requires an API key or Basic login to be configured")
+		return
+	}
+
+	hostID := chi.URLParam(r, "hostID")
+	var req struct {
+		Set string `json:"set"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.Set == "" {
+		writeError(w, http.StatusBadRequest, "set is required")
+		return
+	}
+
+	setSpanAction(r, "raw set")
+	if !h.authorize(w, r, "raw.set", hostID) {
+		return
+	}
+
+	client, err := h.getClient(r.Context(), hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	log.Printf("audit: raw set on %s: %s (%s)", hostID, req.Set, r.RemoteAddr)
+	body, err := client.SetContext(r.Context(), req.Set)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	writeRaw(w, body)
+}
+
+// writeRaw writes an unparsed iDRAC response. Bodies that look like
+// markup are served as XML (iDRAC6 omits the XML declaration, so
+// http.DetectContentType alone reports text/plain).
+func writeRaw(w http.ResponseWriter, body []byte) {
+	contentType := http.DetectContentType(body)
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("<")) && strings.HasPrefix(contentType, "text/plain") {
+		contentType = "text/xml; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body) //nolint:errcheck
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v) //nolint:errcheck
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	body := map[string]string{"error": message}
+	if id := w.Header().Get(requestIDHeader); id != "" {
+		body["requestId"] = id
+	}
+	writeJSON(w, status, body)
 }
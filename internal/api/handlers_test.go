@@ -1,13 +1,21 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/internal/version"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
 )
 
 func TestHealthEndpoint(t *testing.T) {
@@ -27,6 +35,60 @@ func TestHealthEndpoint(t *testing.T) {
 	if body["status"] != "ok" {
 		t.Errorf("status = %q, want ok", body["status"])
 	}
+	if body["version"] == "" || body["commit"] == "" {
+		t.Errorf("health = %v, want version and commit", body)
+	}
+}
+
+func TestVersionEndpoint(t *testing.T) {
+	defer func(v string) { version.Version = v }(version.Version)
+	version.Version = "v1.2.3"
+
+	router := NewRouter(&Config{Hosts: map[string]*HostConfig{}})
+	req := httptest.NewRequest("GET", "/api/version", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var info version.Info
+	if err := json.NewDecoder(w.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != "v1.2.3" || info.Commit == "" || info.Date == "" || !strings.HasPrefix(info.GoVersion, "go") {
+		t.Errorf("version = %+v", info)
+	}
+}
+
+func TestUIConfig(t *testing.T) {
+	router := NewRouter(&Config{
+		Hosts:    map[string]*HostConfig{},
+		APIKey:   "secret",
+		BasePath: "idrac/",
+		ReadOnly: true,
+	})
+
+	// Served without the API key, under the base path.
+	req := httptest.NewRequest("GET", "/idrac/api/ui-config", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body)
+	}
+
+	var cfg uiConfig
+	if err := json.NewDecoder(w.Body).Decode(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.BasePath != "/idrac" || !cfg.AuthRequired || !cfg.Features.ReadOnly || cfg.Features.Events || cfg.Version == "" {
+		t.Errorf("ui-config = %+v", cfg)
+	}
+
+	// The rest of /api still requires the key.
+	req = httptest.NewRequest("GET", "/idrac/api/hosts", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("/api/hosts without key: status = %d, want 401", w.Code)
+	}
 }
 
 func TestListHosts(t *testing.T) {
@@ -137,6 +199,48 @@ func TestAPIKeyAuth(t *testing.T) {
 	}
 }
 
+func TestBasicAuth(t *testing.T) {
+	get := func(cfg *Config, setAuth func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/health", nil)
+		if setAuth != nil {
+			setAuth(req)
+		}
+		w := httptest.NewRecorder()
+		NewRouter(cfg).ServeHTTP(w, req)
+		return w
+	}
+	basic := func(user, pass string) func(*http.Request) {
+		return func(r *http.Request) { r.SetBasicAuth(user, pass) }
+	}
+
+	cfg := &Config{Hosts: map[string]*HostConfig{}, BasicAuth: BasicAuth{Username: "ops", Password: "pw"}}
+	w := get(cfg, nil)
+	if w.Code != http.StatusUnauthorized || !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), `Basic realm="idrac6-manager"`) {
+		t.Errorf("no credentials: status %d, challenge %q; want 401 with a Basic challenge", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+	if w := get(cfg, basic("ops", "pw")); w.Code != http.StatusOK {
+		t.Errorf("valid Basic login: status = %d", w.Code)
+	}
+	if w := get(cfg, basic("ops", "wrong")); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong password: status = %d", w.Code)
+	}
+
+	// The API key as the Basic password, alongside the usual headers.
+	cfg = &Config{Hosts: map[string]*HostConfig{}, APIKey: "secret-key", BasicAuth: BasicAuth{APIKey: true}}
+	if w := get(cfg, basic("anyone", "secret-key")); w.Code != http.StatusOK {
+		t.Errorf("API key as Basic password: status = %d", w.Code)
+	}
+	if w := get(cfg, func(r *http.Request) { r.Header.Set("X-API-Key", "secret-key") }); w.Code != http.StatusOK {
+		t.Errorf("X-API-Key with Basic enabled: status = %d", w.Code)
+	}
+
+	// Without Basic enabled, a Basic login is refused and not challenged.
+	cfg = &Config{Hosts: map[string]*HostConfig{}, APIKey: "secret-key"}
+	if w := get(cfg, basic("anyone", "secret-key")); w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != "" {
+		t.Errorf("Basic without basic auth: status %d, challenge %q; want 401, none", w.Code, w.Header().Get("WWW-Authenticate"))
+	}
+}
+
 func TestCORSHeaders(t *testing.T) {
 	cfg := &Config{Hosts: map[string]*HostConfig{}}
 	router := NewRouter(cfg)
@@ -157,7 +261,8 @@ func TestHostCtxMiddleware(t *testing.T) {
 	h := &Handlers{
 		config: &Config{
 			Hosts: map[string]*HostConfig{
-				"exists": {Name: "Test", Host: "10.0.0.1"},
+				"exists":   {Name: "Test", Host: "10.0.0.1"},
+				"disabled": {Name: "Maintenance", Host: "10.0.0.2", Disabled: true},
 			},
 		},
 	}
@@ -185,4 +290,689 @@ func TestHostCtxMiddleware(t *testing.T) {
 	if w.Code != http.StatusNotFound {
 		t.Errorf("missing host: status = %d, want %d", w.Code, http.StatusNotFound)
 	}
+
+	// Test disabled host
+	req = httptest.NewRequest("GET", "/hosts/disabled/test", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusLocked {
+		t.Errorf("disabled host: status = %d, want %d", w.Code, http.StatusLocked)
+	}
+}
+
+func TestStatsEndpoint(t *testing.T) {
+	cfg := &Config{Hosts: map[string]*HostConfig{}}
+	router := NewRouter(cfg)
+
+	// Generate a tracked request against an unknown host
+	req := httptest.NewRequest("GET", "/api/hosts/missing/power", nil)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest("GET", "/api/stats", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var body struct {
+		CachedClients int `json:"cachedClients"`
+		Logins        int `json:"logins"`
+		Hosts         []struct {
+			ID string `json:"id"`
+		} `json:"hosts"`
+	}
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.CachedClients != 0 {
+		t.Errorf("cachedClients = %d, want 0", body.CachedClients)
+	}
+	if len(body.Hosts) != 0 {
+		t.Errorf("got %d host stats, want 0 (hostCtx rejects unknown hosts first)", len(body.Hosts))
+	}
+}
+
+func TestSetBootOnce_InvalidDevice(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	req := httptest.NewRequest("POST", "/api/hosts/server1/boot/once", strings.NewReader(`{"device":"floppy-disk"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSetBootOrder_Validation(t *testing.T) {
+	router := NewRouter(&Config{Hosts: map[string]*HostConfig{
+		"s1": {Host: "10.0.0.1", Username: "root", Password: "calvin"},
+	}})
+
+	for _, body := range []string{`{"device":"NIC.Embedded.1-1"}`, `{"devices":["HDD"]}`, `{`} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/hosts/s1/bootorder", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, w.Code)
+		}
+	}
+}
+
+func TestJSONRecoverer(t *testing.T) {
+	panicky := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	})
+
+	for _, debug := range []bool{false, true} {
+		handler := jsonRecoverer(debug)(panicky)
+		req := httptest.NewRequest("GET", "/api/anything", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("debug=%v: status = %d, want %d", debug, w.Code, http.StatusInternalServerError)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+			t.Errorf("debug=%v: Content-Type = %q, want application/json", debug, ct)
+		}
+
+		var body map[string]string
+		json.NewDecoder(w.Body).Decode(&body)
+		if body["code"] != "internal_error" {
+			t.Errorf("debug=%v: code = %q, want internal_error", debug, body["code"])
+		}
+		if _, ok := body["stack"]; ok != debug {
+			t.Errorf("debug=%v: stack present = %v, want %v", debug, ok, debug)
+		}
+	}
+}
+
+func TestBasePath(t *testing.T) {
+	cfg := &Config{
+		Hosts:    map[string]*HostConfig{},
+		BasePath: "/idrac/",
+		WebFS:    fstest.MapFS{"index.html": {Data: []byte("<html>ui</html>")}},
+	}
+	router := NewRouter(cfg)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/idrac/api/health", http.StatusOK},
+		{"/idrac/", http.StatusOK},
+		{"/idrac", http.StatusMovedPermanently},
+		{"/api/health", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("GET %s: status = %d, want %d", tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+func TestListHosts_TagFilter(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"db1":  {Name: "DB 1", Host: "10.0.0.1", Location: "rack-3 U12", Tags: []string{"prod", "db"}, Notes: "primary"},
+			"web1": {Name: "Web 1", Host: "10.0.0.2", Tags: []string{"prod"}},
+			"lab1": {Name: "Lab 1", Host: "10.0.0.3", Tags: []string{"lab"}},
+		},
+	}
+	router := NewRouter(cfg)
+
+	req := httptest.NewRequest("GET", "/api/hosts?tag=db", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var hosts []struct {
+		ID       string   `json:"id"`
+		Location string   `json:"location"`
+		Tags     []string `json:"tags"`
+		Notes    string   `json:"notes"`
+	}
+	json.NewDecoder(w.Body).Decode(&hosts)
+	if len(hosts) != 1 {
+		t.Fatalf("got %d hosts, want 1", len(hosts))
+	}
+	if hosts[0].ID != "db1" || hosts[0].Location != "rack-3 U12" || hosts[0].Notes != "primary" {
+		t.Errorf("unexpected host metadata: %+v", hosts[0])
+	}
+
+	req = httptest.NewRequest("GET", "/api/hosts?tag=prod", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	json.NewDecoder(w.Body).Decode(&hosts)
+	if len(hosts) != 2 {
+		t.Errorf("tag=prod: got %d hosts, want 2", len(hosts))
+	}
+}
+
+func TestListHosts_FilterAndSort(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"a": {Name: "Zeta DB", Host: "10.0.1.5"},
+			"b": {Name: "Alpha DB", Host: "10.0.0.9"},
+			"c": {Name: "Web", Host: "10.0.0.1"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	tests := []struct {
+		query   string
+		wantIDs []string
+	}{
+		{"", []string{"a", "b", "c"}},
+		{"?sort=name", []string{"b", "c", "a"}},
+		{"?sort=host", []string{"c", "b", "a"}},
+		{"?name=db", []string{"a", "b"}},
+		{"?host=10.0.0.&sort=name", []string{"b", "c"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/api/hosts"+tt.query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var hosts []map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&hosts)
+		var ids []string
+		for _, h := range hosts {
+			ids = append(ids, h["id"].(string))
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+			t.Errorf("query %q: ids = %v, want %v", tt.query, ids, tt.wantIDs)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/hosts?sort=bogus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("sort=bogus: status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestListHosts_StableOrder(t *testing.T) {
+	cfg := &Config{Hosts: map[string]*HostConfig{}}
+	for i := 0; i < 20; i++ {
+		id := fmt.Sprintf("host%02d", i)
+		cfg.Hosts[id] = &HostConfig{Name: id, Host: "10.0.0.1"}
+	}
+	router := NewRouter(cfg)
+
+	var first string
+	for i := 0; i < 10; i++ {
+		req := httptest.NewRequest("GET", "/api/hosts", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if i == 0 {
+			first = w.Body.String()
+			if !strings.Contains(first, `"id":"host00"`) || strings.Index(first, "host00") > strings.Index(first, "host19") {
+				t.Fatalf("hosts not sorted by ID: %s", first)
+			}
+			continue
+		}
+		if w.Body.String() != first {
+			t.Fatalf("call %d returned a different ordering", i)
+		}
+	}
+}
+
+func TestKillSession_InvalidID(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	req := httptest.NewRequest("DELETE", "/api/hosts/server1/sessions/abc", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestGetSEL_IncrementalParamValidation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, query := range []string{"?since=-1", "?since=abc", "?last=0", "?since=1&last=5", "?limit=-1", "?offset=x", "?limit=10&last=5", "?offset=5&since=3", "?severity=bogus", "?last=5&severity=critical"} {
+		req := httptest.NewRequest("GET", "/api/hosts/server1/sel"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", query, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestGetLicense_CachesFailures(t *testing.T) {
+	h := &Handlers{config: &Config{Hosts: map[string]*HostConfig{}}}
+
+	_, first := h.getLicense("s1")
+	if first == nil {
+		t.Fatal("getLicense() for an unknown host error = nil")
+	}
+	// Within the retry interval the failure is answered from the cache,
+	// even though detection would now get further.
+	h.config.Hosts["s1"] = &HostConfig{Host: "127.0.0.1", SSHPort: 1}
+	if license, err := h.getLicense("s1"); license != idrac.LicenseUnknown || err != first {
+		t.Errorf("getLicense() = %v, %v; want the cached failure %v", license, err, first)
+	}
+
+	// Once it expires, detection is tried again.
+	h.licenses.Store("s1", licenseFailure{err: first, at: time.Now().Add(-licenseRetryAfter)})
+	if _, err := h.getLicense("s1"); err == nil || err == first {
+		t.Errorf("getLicense() after the retry interval error = %v, want a fresh attempt", err)
+	}
+}
+
+func TestRequireEnterprise(t *testing.T) {
+	h := &Handlers{
+		config: &Config{
+			Hosts: map[string]*HostConfig{
+				"express":    {Name: "Express", Host: "10.0.0.1"},
+				"enterprise": {Name: "Enterprise", Host: "10.0.0.2"},
+			},
+		},
+	}
+	h.licenses.Store("express", idrac.LicenseExpress)
+	h.licenses.Store("enterprise", idrac.LicenseEnterprise)
+
+	r := chi.NewRouter()
+	r.Route("/hosts/{hostID}", func(r chi.Router) {
+		r.Use(h.requireEnterprise)
+		r.Get("/virtualmedia", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		r.Get("/capabilities", h.GetCapabilities)
+	})
+
+	req := httptest.NewRequest("GET", "/hosts/express/virtualmedia", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("express: status = %d, want %d", w.Code, http.StatusNotImplemented)
+	}
+	if !strings.Contains(w.Body.String(), "requires iDRAC Enterprise") {
+		t.Errorf("express: body = %s, want a requires iDRAC Enterprise error", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/hosts/enterprise/virtualmedia", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("enterprise: status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	req = httptest.NewRequest("GET", "/hosts/enterprise/capabilities", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var caps idrac.Capabilities
+	json.NewDecoder(w.Body).Decode(&caps)
+	if caps.License != idrac.LicenseEnterprise || !caps.VirtualMedia {
+		t.Errorf("capabilities = %+v, want enterprise with virtual media", caps)
+	}
+}
+
+func TestClearSEL_RequiresConfirm(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	tests := []struct {
+		name  string
+		query string
+		body  string
+	}{
+		{name: "no confirmation"},
+		{name: "confirm false", query: "?confirm=false"},
+		{name: "body confirm false", body: `{"confirm":false}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/api/hosts/server1/sel"+tt.query, strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(w.Body.String(), "confirm=true") {
+				t.Errorf("body = %s, want confirmation hint", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestSetIPMIPower_InvalidAction(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	req := httptest.NewRequest("POST", "/api/hosts/server1/ipmi/power", strings.NewReader(`{"action":"hibernate"}`))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSetPowerPolicy_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, tt := range []struct {
+		body string
+		want int
+	}{
+		{`not json`, http.StatusBadRequest},
+		{`{"policy":"sometimes"}`, http.StatusBadRequest},
+		{`{"policy":"always-on","powerOnDelay":60}`, http.StatusNotImplemented},
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/power/policy", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("POST %s: status = %d, want %d", tt.body, w.Code, tt.want)
+		}
+		if strings.Contains(tt.body, "sometimes") && !strings.Contains(w.Body.String(), "always-off, always-on, last-state") {
+			t.Errorf("POST %s: body = %s, want the ipmi package's policy list", tt.body, w.Body.String())
+		}
+	}
+}
+
+func TestSetIDRACName_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, body := range []string{`not json`, `{}`, `{"name":"-bad"}`, `{"name":"r710.lab.local"}`} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/idrac/name", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestChangePassword_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "calvin"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for body, want := range map[string]int{
+		`not json`:                   http.StatusBadRequest,
+		`{"newPassword":"N3wPass!"}`: http.StatusBadRequest,
+		`{"currentPassword":"calvin","newPassword":"has space"}`:     http.StatusBadRequest,
+		`{"currentPassword":"calvin","newPassword":""}`:              http.StatusBadRequest,
+		`{"currentPassword":"wrong","newPassword":"N3wPass!"}`:       http.StatusForbidden,
+		`{"username":"root","currentPassword":"","newPassword":"x"}`: http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/idrac/password", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, want)
+		}
+		if strings.Contains(w.Body.String(), "calvin") || strings.Contains(w.Body.String(), "N3wPass") {
+			t.Errorf("%s: response leaks a password: %s", body, w.Body.String())
+		}
+	}
+	if cfg.Hosts["server1"].Password != "calvin" {
+		t.Error("a rejected change must not modify the stored password")
+	}
+}
+
+func TestChangePassword_FallbackCredential(t *testing.T) {
+	hc := &HostConfig{
+		Host: "10.0.0.1", Username: "root", Password: "calvin",
+		Credentials: []idrac.Credential{{Label: "ops", Username: "ops", Password: "0ps-old"}},
+	}
+	h := &Handlers{config: &Config{}}
+
+	if !h.verifyPassword(context.Background(), hc, "ops", "0ps-old") {
+		t.Error("the stored fallback password was not accepted")
+	}
+	if h.verifyPassword(context.Background(), hc, "ops", "calvin") {
+		t.Error("another login's password was accepted for the fallback account")
+	}
+
+	updated := hc.withPassword("ops", "0ps-new")
+	if updated.Credentials[0].Password != "0ps-new" || updated.Password != "calvin" {
+		t.Errorf("updated = %+v, want only the ops credential changed", updated)
+	}
+	if hc.Credentials[0].Password != "0ps-old" {
+		t.Error("withPassword modified the original credentials")
+	}
+	if len(managedPasswords(hc, "guest")) != 0 {
+		t.Error("an account the manager does not use counted as managed")
+	}
+}
+
+func TestSetAssetTag_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, body := range []string{`not json`, `{}`, `{"assetTag":"TOO-LONG-TAG"}`, `{"assetTag":"two words"}`} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/assettag", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestSetLCD_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, body := range []string{`not json`, `{}`, `{"message":"` + strings.Repeat("x", 63) + `"}`, `{"message":"say \"hi\""}`} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/lcd", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestRegenerateCertificate_Confirm(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, tt := range []struct{ query, body string }{
+		{"", ""},
+		{"?confirm=true", ""},
+		{"", `{"confirm":"server2"}`},
+		{"", `not json`},
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/certificate/regenerate"+tt.query, strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q %q: status = %d, want %d", tt.query, tt.body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestFirmwareUpdate_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, body := range []string{
+		`not json`,
+		`{"imageUrl":"tftp://10.0.0.5/firmimg.d6"}`,
+		`{"imageUrl":"file:///firmimg.d6","confirm":true}`,
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/firmware/update", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+
+	req := httptest.NewRequest("GET", "/api/hosts/server1/firmware/jobs/fwupdate-1", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown job: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestReadOnlyMode(t *testing.T) {
+	cfg := &Config{
+		Hosts:    map[string]*HostConfig{"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"}},
+		ReadOnly: true,
+	}
+	router := NewRouter(cfg)
+
+	for _, tc := range []struct{ method, path string }{
+		{"POST", "/api/hosts/server1/power"},
+		{"DELETE", "/api/hosts/server1/sel"},
+		{"POST", "/api/hosts/server1/virtualmedia"},
+		{"DELETE", "/api/hosts/server1/virtualmedia"},
+		{"POST", "/api/hosts"},
+		{"POST", "/api/reload"},
+	} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(`{"action":"off"}`)))
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s %s = %d, want 403", tc.method, tc.path, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/hosts", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("GET /api/hosts = %d, want 200", w.Code)
+	}
+}
+
+func TestRequestIDInErrors(t *testing.T) {
+	cfg := &Config{Hosts: map[string]*HostConfig{}}
+	router := NewRouter(cfg)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/hosts/missing/power", nil))
+
+	id := w.Header().Get("X-Request-ID")
+	if id == "" {
+		t.Fatal("X-Request-ID header missing")
+	}
+	var body map[string]string
+	json.NewDecoder(w.Body).Decode(&body)
+	if body["requestId"] != id {
+		t.Errorf("requestId = %q, want %q", body["requestId"], id)
+	}
+}
+
+func TestJSONLogger(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	defer slog.SetDefault(prev)
+
+	router := NewRouter(&Config{Hosts: map[string]*HostConfig{}, LogJSON: true})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/health", nil))
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log output %q is not one JSON line: %v", buf.String(), err)
+	}
+	if line["request_id"] != w.Header().Get("X-Request-ID") || line["path"] != "/api/health" || line["status"] != float64(200) {
+		t.Errorf("log line = %v", line)
+	}
+}
+
+func TestDemoMode(t *testing.T) {
+	cfg := &Config{
+		Demo: true,
+		Hosts: map[string]*HostConfig{
+			"r710-a": {Name: "Demo R710", Host: "192.0.2.1", Username: "root", Password: "calvin"},
+		},
+	}
+	router := NewRouter(cfg)
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, path := range []string{"/sensors", "/info", "/sel", "/snapshot"} {
+		if w := do("GET", "/api/hosts/r710-a"+path, ""); w.Code != http.StatusOK {
+			t.Errorf("GET %s: status = %d, want 200: %s", path, w.Code, w.Body.String())
+		}
+	}
+
+	if w := do("POST", "/api/hosts/r710-a/power", `{"action":"off"}`); w.Code != http.StatusOK {
+		t.Fatalf("POST power off: status = %d: %s", w.Code, w.Body.String())
+	}
+	var power idrac.PowerStatus
+	json.NewDecoder(do("GET", "/api/hosts/r710-a/power", "").Body).Decode(&power)
+	if power.State != idrac.PowerOff {
+		t.Errorf("power after off = %+v, want off kept by the demo client", power)
+	}
+
+	for _, path := range []string{"/ipmi/power", "/power/policy", "/power/stats", "/power/detail"} {
+		w := do("GET", "/api/hosts/r710-a"+path, "")
+		var body apiError
+		json.NewDecoder(w.Body).Decode(&body)
+		if w.Code != http.StatusNotImplemented || body.Code != idrac.CodeDemo {
+			t.Errorf("GET %s: status = %d code %q, want 501 %s", path, w.Code, body.Code, idrac.CodeDemo)
+		}
+	}
 }
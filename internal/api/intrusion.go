@@ -0,0 +1,81 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// intrusionReading is a host's latest chassis intrusion reading.
+type intrusionReading struct {
+	idrac.Intrusion
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// recordIntrusion caches a host's intrusion reading for /api/status and,
+// when the chassis is newly open, publishes an intrusion event.
+func (h *Handlers) recordIntrusion(hostID string, in *idrac.Intrusion) {
+	reading := &intrusionReading{Intrusion: *in, CheckedAt: time.Now()}
+	prev, _ := h.intrusion.Swap(hostID, reading)
+	if in.State != idrac.IntrusionOpen {
+		return
+	}
+	if p, ok := prev.(*intrusionReading); ok && p.State == idrac.IntrusionOpen {
+		return
+	}
+	log.Printf("chassis intrusion detected on %s (%s)", hostID, in.Sensor)
+	if h.events == nil {
+		return
+	}
+	h.events.mu.Lock()
+	defer h.events.mu.Unlock()
+	h.events.publish(sensorEvent{
+		Type:     eventIntrusionDetected,
+		Severity: idrac.SeverityCritical,
+		Host:     hostID,
+		Sensor:   in.Sensor,
+		State:    string(in.State),
+		Time:     reading.CheckedAt,
+	})
+}
+
+// cachedIntrusion returns a host's latest intrusion reading, if any.
+func (h *Handlers) cachedIntrusion(hostID string) *intrusionReading {
+	if v, ok := h.intrusion.Load(hostID); ok {
+		return v.(*intrusionReading)
+	}
+	return nil
+}
+
+// GetIntrusion reads the chassis intrusion sensor over RACADM. lastChanged
+// is the timestamp of the newest intrusion entry in the SEL, omitted if
+// the SEL has none or cannot be read.
+func (h *Handlers) GetIntrusion(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	in, err := admin.GetIntrusion()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if client, err := h.getClient(r.Context(), hostID); err == nil {
+		if sel, err := client.GetSEL(); err == nil {
+			if e, ok := sel.LastIntrusionEvent(); ok {
+				in.LastChanged = e.Timestamp
+			}
+		}
+	}
+
+	h.recordIntrusion(hostID, in)
+	writeJSON(w, http.StatusOK, in)
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/internal/ipmi"
+)
+
+// GetWatchdog returns the BMC watchdog timer state.
+func (h *Handlers) GetWatchdog(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	wd, err := client.GetWatchdog()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wd)
+}
+
+// SetWatchdog configures the watchdog timer. Configuring stops the timer;
+// with "start": true it is started immediately, after which the host must
+// keep resetting it or the BMC performs the expiry action.
+func (h *Handlers) SetWatchdog(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		ipmi.WatchdogConfig
+		Start bool `json:"start,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if err := req.WatchdogConfig.Validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	setSpanAction(r, "watchdog "+req.Action)
+	if !h.authorize(w, r, "ipmi.watchdog.set", hostID) {
+		return
+	}
+
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := client.SetWatchdog(req.WatchdogConfig); err != nil {
+		handleError(w, err)
+		return
+	}
+	if req.Start {
+		if err := client.ResetWatchdog(); err != nil {
+			handleError(w, err)
+			return
+		}
+	}
+
+	wd, err := client.GetWatchdog()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, wd)
+}
+
+// ResetWatchdog starts or restarts ("pets") the watchdog countdown.
+func (h *Handlers) ResetWatchdog(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	if !h.authorize(w, r, "ipmi.watchdog.reset", hostID) {
+		return
+	}
+
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	if err := client.ResetWatchdog(); err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"status": "reset"})
+}
+
+// GetLANConfig returns the BMC's network configuration for inventory and
+// drift detection.
+func (h *Handlers) GetLANConfig(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	cfg, err := client.GetLANConfig()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cfg)
+}
+
+// GetIPMIUsers returns the BMC user table (IDs, names, enabled state, and
+// privilege levels) for access audits. IPMI never exposes passwords.
+func (h *Handlers) GetIPMIUsers(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	users, err := client.GetUsers()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+// ipmiSnapshot is the combined IPMI view of a host.
+type ipmiSnapshot struct {
+	PowerOn      bool               `json:"powerOn"`
+	BootOverride *ipmi.BootOverride `json:"bootOverride"`
+	Watchdog     *ipmi.Watchdog     `json:"watchdog"`
+	LAN          *ipmi.LANConfig    `json:"lan"`
+	SEL          []ipmi.SELEntry    `json:"sel"`
+	Timestamp    time.Time          `json:"timestamp"`
+}
+
+// GetIPMISnapshot returns power, boot override, watchdog, LAN
+// configuration, and the SEL in one response, read over a single IPMI
+// session instead of one handshake per command.
+func (h *Handlers) GetIPMISnapshot(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	var snap ipmiSnapshot
+	err = client.WithConnection(func(cl *ipmi.Conn) error {
+		var err error
+		if snap.PowerOn, err = cl.GetPowerStatus(); err != nil {
+			return err
+		}
+		if snap.BootOverride, err = cl.GetBootOverride(); err != nil {
+			return err
+		}
+		if snap.Watchdog, err = cl.GetWatchdog(); err != nil {
+			return err
+		}
+		if snap.LAN, err = cl.GetLANConfig(); err != nil {
+			return err
+		}
+		snap.SEL, err = cl.GetSEL()
+		return err
+	})
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	if snap.SEL == nil {
+		snap.SEL = []ipmi.SELEntry{}
+	}
+	snap.Timestamp = time.Now().UTC()
+
+	writeJSON(w, http.StatusOK, snap)
+}
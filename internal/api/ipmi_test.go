@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSetWatchdog_Validation(t *testing.T) {
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+	}
+	router := NewRouter(cfg)
+
+	for _, body := range []string{
+		`not json`,
+		`{"action":"explode","timeoutSeconds":60}`,
+		`{"action":"reset","timeoutSeconds":0}`,
+		`{"action":"reset","timeoutSeconds":30,"pretimeoutSeconds":60}`,
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/server1/ipmi/watchdog", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
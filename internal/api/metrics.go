@@ -0,0 +1,218 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// metricLabel is one Prometheus label.
+type metricLabel struct {
+	name, value string
+}
+
+// metricWriter writes the Prometheus text exposition format.
+type metricWriter struct {
+	buf bytes.Buffer
+}
+
+// family starts a metric family with its HELP and TYPE lines.
+func (m *metricWriter) family(name, typ, help string) {
+	fmt.Fprintf(&m.buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+func (m *metricWriter) sample(name string, labels []metricLabel, v float64) {
+	m.buf.WriteString(name)
+	if len(labels) > 0 {
+		m.buf.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				m.buf.WriteByte(',')
+			}
+			fmt.Fprintf(&m.buf, `%s="%s"`, l.name, labelValueEscaper.Replace(l.value))
+		}
+		m.buf.WriteByte('}')
+	}
+	m.buf.WriteByte(' ')
+	m.buf.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+	m.buf.WriteByte('\n')
+}
+
+// labelValueEscaper applies the exposition format's label value escapes.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// metricLabelName turns a label key into a valid Prometheus label name.
+func metricLabelName(key string) string {
+	var b strings.Builder
+	for i, r := range strings.ToLower(strings.TrimSpace(key)) {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_', r >= '0' && r <= '9' && i > 0:
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// reservedMetricLabels are the label names the manager sets itself on
+// host and sensor series; metadata keys with these names are not exported
+// so they cannot produce duplicate labels.
+var reservedMetricLabels = map[string]bool{"host": true, "type": true, "sensor": true, "unit": true}
+
+// metricLabels returns the host's labels for keys in allow, in allowlist
+// order. "location" is the host's location; any other key is read from a
+// "key=value" or "key:value" tag (e.g. "rack=r12"). Keys the host does not
+// set, reserved names, and repeats are omitted. Only allowlisted keys are
+// exported so free-form tags cannot blow up series cardinality.
+func (hc *HostConfig) metricLabels(allow []string) []metricLabel {
+	var out []metricLabel
+	seen := make(map[string]bool)
+	for _, key := range allow {
+		name := metricLabelName(key)
+		if name == "" || reservedMetricLabels[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+		value := ""
+		if name == "location" {
+			value = hc.Location
+		} else {
+			for _, tag := range hc.Tags {
+				k, v, ok := strings.Cut(tag, "=")
+				if !ok {
+					k, v, ok = strings.Cut(tag, ":")
+				}
+				if ok && metricLabelName(k) == name {
+					value = strings.TrimSpace(v)
+					break
+				}
+			}
+		}
+		if value != "" {
+			out = append(out, metricLabel{name, value})
+		}
+	}
+	return out
+}
+
+// sensorSeriesNames returns the "sensor" label for each reading. A name
+// that repeats within the group gets a "#2", "#3", ... suffix so each
+// reading is its own series rather than a duplicate the scrape rejects.
+func sensorSeriesNames(readings []idrac.SensorReading) []string {
+	count := make(map[string]int, len(readings))
+	for _, s := range readings {
+		count[s.Name]++
+	}
+	seen := make(map[string]int, len(readings))
+	names := make([]string, len(readings))
+	for i, s := range readings {
+		names[i] = s.Name
+		if count[s.Name] > 1 {
+			seen[s.Name]++
+			names[i] = fmt.Sprintf("%s #%d", s.Name, seen[s.Name])
+		}
+	}
+	return names
+}
+
+// Metrics serves manager and per-host metrics in the Prometheus text
+// format. Every host series carries a "host" label plus the host's
+// allowlisted metadata labels (Config.MetricLabels). Power and sensor
+// series come from the background refresher and are only present when it
+// is enabled.
+func (h *Handlers) Metrics(w http.ResponseWriter, _ *http.Request) {
+	var m metricWriter
+	totals := h.pool.Stats()
+
+	m.family("idrac_manager_uptime_seconds", "gauge", "Seconds since the manager started.")
+	m.sample("idrac_manager_uptime_seconds", nil, time.Since(h.stats.started).Seconds())
+	m.family("idrac_manager_logins_total", "counter", "iDRAC logins attempted by cached clients.")
+	m.sample("idrac_manager_logins_total", nil, float64(totals.Logins))
+	m.family("idrac_manager_login_failures_total", "counter", "Failed iDRAC logins by cached clients.")
+	m.sample("idrac_manager_login_failures_total", nil, float64(totals.LoginFailures))
+
+	ids := h.activeHostIDs()
+	labels := make(map[string][]metricLabel, len(ids))
+	for _, id := range ids {
+		hc, ok := h.hostConfig(id)
+		if !ok {
+			continue
+		}
+		labels[id] = append([]metricLabel{{"host", id}}, hc.metricLabels(h.config.MetricLabels)...)
+	}
+
+	latency := h.stats.latencies()
+	m.family("idrac_host_requests_total", "counter", "API requests for the host.")
+	for _, id := range ids {
+		if hl, ok := latency[id]; ok && labels[id] != nil {
+			m.sample("idrac_host_requests_total", labels[id], float64(hl.requests))
+		}
+	}
+	m.family("idrac_host_request_errors_total", "counter", "API requests for the host that failed with a 5xx status.")
+	for _, id := range ids {
+		if hl, ok := latency[id]; ok && labels[id] != nil {
+			m.sample("idrac_host_request_errors_total", labels[id], float64(hl.errors))
+		}
+	}
+	m.family("idrac_host_request_duration_seconds_total", "counter", "Total time spent serving API requests for the host.")
+	for _, id := range ids {
+		if hl, ok := latency[id]; ok && labels[id] != nil {
+			m.sample("idrac_host_request_duration_seconds_total", labels[id], hl.total.Seconds())
+		}
+	}
+	queues := h.pool.LoginQueueStats()
+	m.family("idrac_host_login_queue_waits_total", "counter", "Requests that queued behind another login to the host.")
+	for _, id := range ids {
+		if q, ok := queues[id]; ok && labels[id] != nil {
+			m.sample("idrac_host_login_queue_waits_total", labels[id], float64(q.Waits))
+		}
+	}
+	m.family("idrac_host_login_queue_wait_seconds_total", "counter", "Total time requests spent queued behind another login to the host.")
+	for _, id := range ids {
+		if q, ok := queues[id]; ok && labels[id] != nil {
+			m.sample("idrac_host_login_queue_wait_seconds_total", labels[id], q.WaitTime.Seconds())
+		}
+	}
+
+	if h.refresher != nil {
+		m.family("idrac_host_power_on", "gauge", "Whether the host is powered on (1) or off (0), from the last background refresh.")
+		for _, id := range ids {
+			if p, _, ok := h.refresher.cachedPower(id); ok && labels[id] != nil && p.State != idrac.PowerInvalid {
+				m.sample("idrac_host_power_on", labels[id], float64(p.State))
+			}
+		}
+		m.family("idrac_sensor_value", "gauge", "Sensor reading from the last background refresh, in the sensor's unit.")
+		for _, id := range ids {
+			data, _, ok := h.refresher.cachedSensors(id)
+			if !ok || labels[id] == nil {
+				continue
+			}
+			h.renameSensors(id, data)
+			for _, group := range []struct {
+				typ      string
+				readings []idrac.SensorReading
+			}{
+				{"temperature", data.Temperatures},
+				{"fan", data.Fans},
+				{"voltage", data.Voltages},
+			} {
+				names := sensorSeriesNames(group.readings)
+				for i, s := range group.readings {
+					l := append(append([]metricLabel(nil), labels[id]...),
+						metricLabel{"type", group.typ}, metricLabel{"sensor", names[i]}, metricLabel{"unit", s.Unit})
+					m.sample("idrac_sensor_value", l, s.Value)
+				}
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(m.buf.Bytes()) //nolint:errcheck
+}
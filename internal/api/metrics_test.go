@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestMetricLabels(t *testing.T) {
+	hc := &HostConfig{Location: "DC1 \"east\"", Tags: []string{"homelab", "rack=r12", "Env:prod", "owner=bob"}}
+	hc.Tags = append(hc.Tags, "type=compute", "unit=u4", "sensor=x")
+	got := hc.metricLabels([]string{"rack", "env", "location", "missing", "host", "type", "unit", "sensor", "Rack"})
+	want := []metricLabel{{"rack", "r12"}, {"env", "prod"}, {"location", `DC1 "east"`}}
+	if len(got) != len(want) {
+		t.Fatalf("metricLabels() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("label %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSensorSeriesNames(t *testing.T) {
+	got := sensorSeriesNames([]idrac.SensorReading{{Name: "PS Voltage"}, {Name: "CPU1 Temp"}, {Name: "PS Voltage"}})
+	want := []string{"PS Voltage #1", "CPU1 Temp", "PS Voltage #2"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("sensorSeriesNames() = %q, want %q", got, want)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+	h := &Handlers{
+		config: &Config{
+			Hosts: map[string]*HostConfig{
+				"s1": {Host: addr, Username: "root", Password: "calvin", Location: "DC1 \"east\"", Tags: []string{"rack=r12", "owner=bob"}},
+			},
+			MetricLabels: []string{"rack", "location"},
+		},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+	h.stats.record("s1", 250*time.Millisecond, true)
+	h.refresher = newRefresher(h, time.Minute, 1)
+	h.refresher.due(time.Now())
+	h.refresher.refresh("s1")
+
+	w := httptest.NewRecorder()
+	h.Metrics(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("status = %d, content type %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	body := w.Body.String()
+
+	labels := `host="s1",rack="r12",location="DC1 \"east\""`
+	for _, want := range []string{
+		"# TYPE idrac_host_requests_total counter",
+		"idrac_host_requests_total{" + labels + "} 1\n",
+		"idrac_host_request_errors_total{" + labels + "} 1\n",
+		"idrac_host_request_duration_seconds_total{" + labels + "} 0.25\n",
+		"idrac_host_power_on{" + labels + "} 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "owner") {
+		t.Error("tag not in the allowlist was exported")
+	}
+}
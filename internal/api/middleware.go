@@ -1,16 +1,67 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
+// requestIDHeader is the response header carrying the request ID.
+const requestIDHeader = "X-Request-ID"
+
+// echoRequestID returns the ID assigned by middleware.RequestID in the
+// X-Request-ID response header, where writeError and handleError also
+// pick it up for error bodies, so a failed call can be matched to the
+// server's log lines.
+func echoRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if id := middleware.GetReqID(r.Context()); id != "" {
+			w.Header().Set(requestIDHeader, id)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// jsonLogger logs one JSON line per request through slog, in place of
+// middleware.Logger's text format.
+func jsonLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		start := time.Now()
+		defer func() {
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			slog.Info("request",
+				"request_id", middleware.GetReqID(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", status,
+				"bytes", ww.BytesWritten(),
+				"duration_ms", time.Since(start).Milliseconds(),
+				"remote", r.RemoteAddr,
+			)
+		}()
+		next.ServeHTTP(ww, r)
+	})
+}
+
 // corsMiddleware adds CORS headers for local development.
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key")
+		w.Header().Set("Access-Control-Expose-Headers", requestIDHeader)
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -21,8 +72,42 @@ func corsMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// apiKeyAuth validates the X-API-Key header.
-func apiKeyAuth(key string) func(http.Handler) http.Handler {
+// BasicAuth configures HTTP Basic authentication, for proxies and tools
+// that speak it more easily than the X-API-Key or Bearer headers.
+// Username and Password, when both set, are an accepted login; with
+// APIKey set, any username with the API key as the password is too.
+type BasicAuth struct {
+	Username string `yaml:"username,omitempty"`
+	Password string `yaml:"password,omitempty"`
+	APIKey   bool   `yaml:"api_key,omitempty"`
+}
+
+// basicRealm is the realm named in Basic auth challenges.
+const basicRealm = "idrac6-manager"
+
+// enabled reports whether any Basic login is accepted, given the API key.
+func (b BasicAuth) enabled(apiKey string) bool {
+	return b.Username != "" && b.Password != "" || b.APIKey && apiKey != ""
+}
+
+// allows reports whether user and pass are an accepted Basic login.
+func (b BasicAuth) allows(user, pass, apiKey string) bool {
+	if b.Username != "" && b.Password != "" && secretEqual(user, b.Username) && secretEqual(pass, b.Password) {
+		return true
+	}
+	return b.APIKey && apiKey != "" && secretEqual(pass, apiKey)
+}
+
+// secretEqual compares credentials in constant time.
+func secretEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// apiKeyAuth validates the X-API-Key header, an Authorization Bearer
+// token, or, if basic is enabled, HTTP Basic credentials, and records the
+// caller's identity for authorization hooks. With Basic enabled a 401
+// carries a WWW-Authenticate challenge so browsers prompt for a login.
+func apiKeyAuth(key string, basic BasicAuth) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			apiKey := r.Header.Get("X-API-Key")
@@ -34,11 +119,70 @@ func apiKeyAuth(key string) func(http.Handler) http.Handler {
 				}
 			}
 
-			if apiKey != key {
-				http.Error(w, `{"error":"unauthorized"}`, http.StatusUnauthorized)
+			identity := ""
+			switch user, pass, ok := r.BasicAuth(); {
+			case key != "" && apiKey == key:
+				identity = IdentityAPIKey
+			case ok && basic.enabled(key) && basic.allows(user, pass, key):
+				identity = IdentityBasic
+			}
+			if identity == "" {
+				if basic.enabled(key) {
+					w.Header().Set("WWW-Authenticate", `Basic realm="`+basicRealm+`", charset="UTF-8"`)
+				}
+				writeError(w, http.StatusUnauthorized, "unauthorized")
 				return
 			}
 
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityKey, identity)))
+		})
+	}
+}
+
+// readOnly rejects any request that could change state.
+func readOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			writeError(w, http.StatusForbidden, "server is in read-only mode")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// jsonRecoverer recovers from panics and writes a JSON error body consistent
+// with the rest of the API. The stack trace is always logged with the request
+// ID, but only included in the response when debug is enabled.
+func jsonRecoverer(debugMode bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					// Let net/http handle aborted responses as usual.
+					panic(rec)
+				}
+
+				reqID := middleware.GetReqID(r.Context())
+				stack := debug.Stack()
+				log.Printf("panic [%s] %s %s: %v\n%s", reqID, r.Method, r.URL.Path, rec, stack)
+
+				body := map[string]string{
+					"code":      "internal_error",
+					"message":   "internal server error",
+					"error":     "internal server error",
+					"requestId": reqID,
+				}
+				if debugMode {
+					body["panic"] = fmt.Sprint(rec)
+					body["stack"] = string(stack)
+				}
+				writeJSON(w, http.StatusInternalServerError, body)
+			}()
+
 			next.ServeHTTP(w, r)
 		})
 	}
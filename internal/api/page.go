@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Page is the envelope every paginated list endpoint returns. Items is
+// the window of Total items starting at Offset; Limit is the page size
+// asked for (0 means no limit) and HasMore is set when items follow the
+// window.
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total"`
+	Offset  int  `json:"offset"`
+	Limit   int  `json:"limit"`
+	HasMore bool `json:"hasMore"`
+}
+
+// newPage returns the window of at most limit items starting at offset;
+// a limit of 0 takes the rest. An offset past the end gives an empty page.
+func newPage[T any](items []T, offset, limit int) *Page[T] {
+	offset = min(max(offset, 0), len(items))
+	end := len(items)
+	if limit > 0 {
+		end = min(offset+limit, end)
+	}
+	window := items[offset:end]
+	if window == nil {
+		window = []T{}
+	}
+	return &Page[T]{Items: window, Total: len(items), Offset: offset, Limit: limit, HasMore: end < len(items)}
+}
+
+// pageParams reads the "offset" and "limit" query parameters, both
+// defaulting to 0. The error is a 400 apiError.
+func pageParams(r *http.Request) (offset, limit int, err error) {
+	q := r.URL.Query()
+	for _, p := range []struct {
+		name string
+		dst  *int
+	}{{"offset", &offset}, {"limit", &limit}} {
+		s := q.Get(p.name)
+		if s == "" {
+			continue
+		}
+		n, convErr := strconv.Atoi(s)
+		if convErr != nil || n < 0 {
+			return 0, 0, &apiError{Status: http.StatusBadRequest, Code: "bad_request", Message: p.name + " must be a non-negative integer"}
+		}
+		*p.dst = n
+	}
+	return offset, limit, nil
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	for _, tt := range []struct {
+		offset, limit int
+		want          []int
+		hasMore       bool
+	}{
+		{0, 0, []int{1, 2, 3, 4, 5}, false},
+		{0, 2, []int{1, 2}, true},
+		{3, 2, []int{4, 5}, false},
+		{4, 10, []int{5}, false},
+		{9, 2, []int{}, false},
+	} {
+		p := newPage(items, tt.offset, tt.limit)
+		if len(p.Items) != len(tt.want) || p.Total != 5 || p.HasMore != tt.hasMore || p.Limit != tt.limit {
+			t.Errorf("newPage(offset %d, limit %d) = %+v, want items %v, hasMore %v", tt.offset, tt.limit, p, tt.want, tt.hasMore)
+			continue
+		}
+		for i := range tt.want {
+			if p.Items[i] != tt.want[i] {
+				t.Errorf("newPage(offset %d, limit %d) items = %v, want %v", tt.offset, tt.limit, p.Items, tt.want)
+				break
+			}
+		}
+	}
+	if p := newPage[int](nil, 0, 0); p.Items == nil {
+		t.Error("empty page should encode items as []")
+	}
+}
+
+func TestPageParams(t *testing.T) {
+	offset, limit, err := pageParams(httptest.NewRequest("GET", "/x?offset=10&limit=5", nil))
+	if err != nil || offset != 10 || limit != 5 {
+		t.Errorf("pageParams() = %d, %d, %v; want 10, 5", offset, limit, err)
+	}
+	for _, q := range []string{"?offset=-1", "?limit=x"} {
+		var aerr *apiError
+		if _, _, err := pageParams(httptest.NewRequest("GET", "/x"+q, nil)); !errors.As(err, &aerr) || aerr.Status != 400 {
+			t.Errorf("pageParams(%s) error = %v, want a 400", q, err)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// Polling parameters for power actions with "wait" (variables for tests).
+var (
+	// powerWaitTimeout bounds how long a "wait" power action polls.
+	powerWaitTimeout = 60 * time.Second
+	// powerPollInterval is the delay between power state polls.
+	powerPollInterval = 2 * time.Second
+)
+
+// powerActionResult is the response body for a power action.
+type powerActionResult struct {
+	Status     string `json:"status"`
+	Action     string `json:"action"`
+	PriorState string `json:"priorState"`
+	NewState   string `json:"newState,omitempty"`
+	// Path is how a shutdown-force ended: "graceful" or "forced".
+	Path string `json:"path,omitempty"`
+}
+
+// expectedPowerState returns the state a power action should settle in.
+// Actions like restart or nmi have no distinct end state.
+func expectedPowerState(action string) (idrac.PowerState, bool) {
+	switch action {
+	case "on":
+		return idrac.PowerOn, true
+	case "off", "shutdown", actionShutdownForce:
+		return idrac.PowerOff, true
+	default:
+		return idrac.PowerInvalid, false
+	}
+}
+
+// powerStateReader is the subset of idrac.Client used for polling.
+type powerStateReader interface {
+	GetPowerState() (*idrac.PowerStatus, error)
+}
+
+// waitForPowerState polls until the power state reaches the action's
+// expected end state. For actions without one, it returns the first
+// reading taken after one poll interval. It stops early when ctx ends.
+func waitForPowerState(ctx context.Context, client powerStateReader, action string, prior idrac.PowerState) (*idrac.PowerStatus, error) {
+	want, ok := expectedPowerState(action)
+	if !ok || want == prior {
+		if err := sleepCtx(ctx, powerPollInterval); err != nil {
+			return nil, err
+		}
+		return client.GetPowerState()
+	}
+
+	deadline := time.Now().Add(powerWaitTimeout)
+	for {
+		if err := sleepCtx(ctx, powerPollInterval); err != nil {
+			return nil, fmt.Errorf("waiting for power %s: %w", want, err)
+		}
+		status, err := client.GetPowerState()
+		if err == nil && status.State == want {
+			return status, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out after %s waiting for power %s", powerWaitTimeout, want)
+		}
+	}
+}
+
+// sleepCtx waits for d or until ctx ends, returning ctx's error.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// powerActionError writes a failed power action. Transport, upstream,
+// and timeout failures keep their classified status; anything else is
+// the iDRAC rejecting the action, which stays a 400.
+func powerActionError(w http.ResponseWriter, err error) {
+	var se statusError
+	if errors.As(err, &se) || errors.Is(err, context.DeadlineExceeded) {
+		handleError(w, err)
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// actionShutdownForce is a power action the API composes from two iDRAC
+// actions: a graceful shutdown, then a hard power-off if the OS has not
+// turned the host off within the grace period.
+const actionShutdownForce = "shutdown-force"
+
+// Grace periods for shutdown-force (a variable for tests).
+var defaultShutdownGrace = 2 * time.Minute
+
+// maxShutdownGrace bounds graceSeconds. shutdown-force answers
+// synchronously, so the grace period plus the forced power-off must fit
+// in one HTTP request.
+const maxShutdownGrace = 5 * time.Minute
+
+// Ways a shutdown-force can end, reported as powerActionResult.Path.
+const (
+	shutdownGraceful = "graceful"
+	shutdownForced   = "forced"
+)
+
+// powerController is the subset of idrac.Client used by shutdownForce.
+type powerController interface {
+	powerStateReader
+	SetPower(action idrac.PowerAction) error
+}
+
+// shutdownForce requests a graceful shutdown and polls for up to grace for
+// the host to turn off; if it is still on, it powers the host off hard.
+// It returns the path taken and the final power state. If ctx ends during
+// the grace period, the host is left to finish (or ignore) the graceful
+// shutdown rather than being forced off; if it ends after the forced
+// power-off, polling for the off state stops.
+func shutdownForce(ctx context.Context, client powerController, hostID string, grace time.Duration) (string, *idrac.PowerStatus, error) {
+	if err := client.SetPower(idrac.ActionGracefulShut); err != nil {
+		return "", nil, err
+	}
+
+	deadline := time.Now().Add(grace)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return "", nil, fmt.Errorf("waiting for graceful shutdown: %w", ctx.Err())
+		case <-time.After(powerPollInterval):
+		}
+		status, err := client.GetPowerState()
+		if err == nil && status.State == idrac.PowerOff {
+			return shutdownGraceful, status, nil
+		}
+	}
+
+	log.Printf("%s did not shut down within %s; forcing power off", hostID, grace)
+	if err := client.SetPower(idrac.ActionPowerOff); err != nil {
+		return shutdownForced, nil, fmt.Errorf("forcing power off: %w", err)
+	}
+	status, err := waitForPowerState(ctx, client, "off", idrac.PowerOn)
+	if err != nil {
+		return shutdownForced, nil, &apiError{Status: http.StatusGatewayTimeout, Code: "timeout", Message: err.Error()}
+	}
+	return shutdownForced, status, nil
+}
+
+// Power state sources reported by GetPower.
+const (
+	powerSourceWeb  = "web"
+	powerSourceIPMI = "ipmi"
+)
+
+// powerReading is the response body for GetPower: the power status plus
+// which interface it came from.
+type powerReading struct {
+	*idrac.PowerStatus
+	Source string `json:"source"`
+}
+
+// ipmiPowerReader is the subset of ipmi.Client used to resolve an
+// indeterminate web API power state.
+type ipmiPowerReader interface {
+	GetPowerStatus() (bool, error)
+}
+
+// resolvePowerState falls back to IPMI when the web API reports an
+// indeterminate state, which some firmware does intermittently by returning
+// an empty pwState. If IPMI fails too, the web API reading is kept.
+func resolvePowerState(status *idrac.PowerStatus, fallback ipmiPowerReader) (*powerReading, error) {
+	if status.State != idrac.PowerInvalid || fallback == nil {
+		return &powerReading{PowerStatus: status, Source: powerSourceWeb}, nil
+	}
+
+	on, err := fallback.GetPowerStatus()
+	if err != nil {
+		return &powerReading{PowerStatus: status, Source: powerSourceWeb}, err
+	}
+
+	state := idrac.PowerOff
+	if on {
+		state = idrac.PowerOn
+	}
+	return &powerReading{
+		PowerStatus: &idrac.PowerStatus{State: state, Status: state.String()},
+		Source:      powerSourceIPMI,
+	}, nil
+}
+
+// powerSettleWindow is how long after a power action the host is considered
+// transitioning until it reaches the action's end state.
+var powerSettleWindow = 2 * time.Minute
+
+// pendingPower is a power action whose end state has not been observed yet.
+type pendingPower struct {
+	action string
+	want   idrac.PowerState
+	at     time.Time
+}
+
+// pendingPower returns the host's unsettled power action, if any, given its
+// current state. Settled or expired entries are dropped.
+func (h *Handlers) pendingPower(hostID string, current idrac.PowerState) *pendingPower {
+	v, ok := h.pending.Load(hostID)
+	if !ok {
+		return nil
+	}
+	p := v.(pendingPower)
+	if current == p.want || time.Since(p.at) > powerSettleWindow {
+		h.pending.CompareAndDelete(hostID, v)
+		return nil
+	}
+	return &p
+}
+
+// checkPower reads a host's power state before an action. Unless force is
+// set, an action that would be a no-op (on while on) or that races an
+// earlier one still settling is refused with a 409, since iDRAC6 ignores
+// them or fails vaguely. Single-host and group power actions share it.
+func (h *Handlers) checkPower(client idrac.HostClient, hostID, action string, force bool) (*idrac.PowerStatus, error) {
+	prior, err := client.GetPowerState()
+	if err != nil {
+		return nil, err
+	}
+	if !force {
+		current := h.resolvePower(hostID, prior).State
+		if msg := powerConflict(action, current, h.pendingPower(hostID, current)); msg != "" {
+			return nil, &apiError{Status: http.StatusConflict, Code: "conflict", Message: msg + " (pass force=true to send anyway)"}
+		}
+	}
+	return prior, nil
+}
+
+// sendPower sends a power action checked by checkPower and, if it changes
+// the host's state, records it as pending until the host settles.
+func (h *Handlers) sendPower(client idrac.HostClient, hostID, action string, prior idrac.PowerState) error {
+	if err := client.SetPowerByName(action); err != nil {
+		return err
+	}
+	if want, ok := expectedPowerState(action); ok && want != prior {
+		h.pending.Store(hostID, pendingPower{action: action, want: want, at: time.Now()})
+	}
+	return nil
+}
+
+// powerConflict returns why action should not be sent to a host in state
+// current, or "" if it is safe to send.
+func powerConflict(action string, current idrac.PowerState, pending *pendingPower) string {
+	if pending != nil {
+		return fmt.Sprintf("server is transitioning: %s requested %s ago", pending.action, time.Since(pending.at).Round(time.Second))
+	}
+	if current == idrac.PowerInvalid {
+		return "server is transitioning: power state is indeterminate"
+	}
+
+	switch action {
+	case "on":
+		if current == idrac.PowerOn {
+			return "server is already on"
+		}
+	case "off", "shutdown", actionShutdownForce:
+		if current == idrac.PowerOff {
+			return "server is already off"
+		}
+	case "restart", "reset", "nmi":
+		if current == idrac.PowerOff {
+			return fmt.Sprintf("server is off; %s requires it to be on", action)
+		}
+	}
+	return ""
+}
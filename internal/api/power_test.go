@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// fakePower returns a scripted sequence of power states.
+type fakePower struct {
+	states []idrac.PowerState
+	calls  int
+}
+
+func (f *fakePower) GetPowerState() (*idrac.PowerStatus, error) {
+	s := f.states[len(f.states)-1]
+	if f.calls < len(f.states) {
+		s = f.states[f.calls]
+	}
+	f.calls++
+	return &idrac.PowerStatus{State: s, Status: s.String()}, nil
+}
+
+func shortPowerPolling(t *testing.T) {
+	t.Helper()
+	oldTimeout, oldInterval := powerWaitTimeout, powerPollInterval
+	powerWaitTimeout, powerPollInterval = 50*time.Millisecond, time.Millisecond
+	t.Cleanup(func() { powerWaitTimeout, powerPollInterval = oldTimeout, oldInterval })
+}
+
+func TestWaitForPowerState(t *testing.T) {
+	shortPowerPolling(t)
+
+	fake := &fakePower{states: []idrac.PowerState{idrac.PowerOn, idrac.PowerOn, idrac.PowerOff}}
+	status, err := waitForPowerState(context.Background(), fake, "shutdown", idrac.PowerOn)
+	if err != nil {
+		t.Fatalf("waitForPowerState() error = %v", err)
+	}
+	if status.State != idrac.PowerOff {
+		t.Errorf("State = %v, want off", status.State)
+	}
+
+	stuck := &fakePower{states: []idrac.PowerState{idrac.PowerOn}}
+	if _, err := waitForPowerState(context.Background(), stuck, "off", idrac.PowerOn); err == nil {
+		t.Error("waitForPowerState() should time out when the state never changes")
+	}
+}
+
+// fakePowerControl is a fakePower that records power actions; a hard
+// power-off always takes effect.
+type fakePowerControl struct {
+	fakePower
+	actions []idrac.PowerAction
+}
+
+func (f *fakePowerControl) SetPower(action idrac.PowerAction) error {
+	f.actions = append(f.actions, action)
+	if action == idrac.ActionPowerOff {
+		f.states, f.calls = []idrac.PowerState{idrac.PowerOff}, 0
+	}
+	return nil
+}
+
+func TestShutdownForce(t *testing.T) {
+	shortPowerPolling(t)
+
+	graceful := &fakePowerControl{fakePower: fakePower{states: []idrac.PowerState{idrac.PowerOn, idrac.PowerOff}}}
+	path, status, err := shutdownForce(context.Background(), graceful, "s1", time.Second)
+	if err != nil || path != shutdownGraceful || status.State != idrac.PowerOff {
+		t.Fatalf("shutdownForce() = %q, %v, %v; want graceful off", path, status, err)
+	}
+	if len(graceful.actions) != 1 || graceful.actions[0] != idrac.ActionGracefulShut {
+		t.Errorf("actions = %v, want only a graceful shutdown", graceful.actions)
+	}
+
+	// The OS ignores the shutdown until the hard power-off.
+	ignored := &fakePowerControl{fakePower: fakePower{states: []idrac.PowerState{idrac.PowerOn}}}
+	path, _, err = shutdownForce(context.Background(), ignored, "s1", 20*time.Millisecond)
+	if err != nil || path != shutdownForced {
+		t.Fatalf("shutdownForce() = %q, %v; want forced", path, err)
+	}
+	if len(ignored.actions) != 2 || ignored.actions[1] != idrac.ActionPowerOff {
+		t.Errorf("actions = %v, want graceful shutdown then power off", ignored.actions)
+	}
+
+	// An abandoned request does not force the host off.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stuck := &fakePowerControl{fakePower: fakePower{states: []idrac.PowerState{idrac.PowerOn}}}
+	if _, _, err := shutdownForce(ctx, stuck, "s1", time.Second); !errors.Is(err, context.Canceled) || len(stuck.actions) != 1 {
+		t.Errorf("shutdownForce() after cancel = %v with actions %v", err, stuck.actions)
+	}
+
+	// A request that ends while the forced power-off settles stops polling.
+	powerWaitTimeout = time.Minute
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	unresponsive := &fakeStuckPower{fakePowerControl{fakePower: fakePower{states: []idrac.PowerState{idrac.PowerOn}}}}
+	start := time.Now()
+	if path, _, err := shutdownForce(ctx, unresponsive, "s1", 10*time.Millisecond); path != shutdownForced || err == nil || time.Since(start) > time.Second {
+		t.Errorf("shutdownForce() with an abandoned forced wait = %q, %v after %v", path, err, time.Since(start))
+	}
+}
+
+// fakeStuckPower records power actions but never changes state.
+type fakeStuckPower struct {
+	fakePowerControl
+}
+
+func (f *fakeStuckPower) SetPower(action idrac.PowerAction) error {
+	f.actions = append(f.actions, action)
+	return nil
+}
+
+func TestSetPower_PriorState(t *testing.T) {
+	shortPowerPolling(t)
+	server := mockIDRAC(t)
+
+	cfg := &Config{Hosts: map[string]*HostConfig{
+		"s1": {Host: server.Addr(), Username: "root", Password: "calvin"},
+	}}
+	router := NewRouter(cfg)
+
+	for _, tt := range []struct {
+		body         string
+		wantNewState string
+	}{
+		{`{"action":"restart"}`, ""},
+		{`{"action":"restart","wait":true}`, "on"},
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/s1/power", strings.NewReader(tt.body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d: %s", tt.body, w.Code, http.StatusOK, w.Body.String())
+		}
+		var result powerActionResult
+		json.NewDecoder(w.Body).Decode(&result)
+		if result.PriorState != "on" {
+			t.Errorf("%s: priorState = %q, want on", tt.body, result.PriorState)
+		}
+		if result.NewState != tt.wantNewState {
+			t.Errorf("%s: newState = %q, want %q", tt.body, result.NewState, tt.wantNewState)
+		}
+	}
+}
+
+// fakeIPMIPower reports a fixed IPMI chassis power state.
+type fakeIPMIPower struct {
+	on    bool
+	err   error
+	calls int
+}
+
+func (f *fakeIPMIPower) GetPowerStatus() (bool, error) {
+	f.calls++
+	return f.on, f.err
+}
+
+func TestResolvePowerState(t *testing.T) {
+	unknown := &idrac.PowerStatus{State: idrac.PowerInvalid, Status: "unknown"}
+	on := &idrac.PowerStatus{State: idrac.PowerOn, Status: "on"}
+
+	ipmiOn := &fakeIPMIPower{on: true}
+	got, err := resolvePowerState(on, ipmiOn)
+	if err != nil || got.Status != "on" || got.Source != powerSourceWeb || ipmiOn.calls != 0 {
+		t.Errorf("determinate state: got %+v (%v), ipmi calls %d; want web reading without IPMI", got, err, ipmiOn.calls)
+	}
+
+	got, err = resolvePowerState(unknown, ipmiOn)
+	if err != nil || got.State != idrac.PowerOn || got.Source != powerSourceIPMI {
+		t.Errorf("indeterminate state: got %+v (%v), want on from ipmi", got, err)
+	}
+
+	got, err = resolvePowerState(unknown, &fakeIPMIPower{err: errors.New("IPMI connect: timeout")})
+	if err == nil || got.Status != "unknown" || got.Source != powerSourceWeb {
+		t.Errorf("IPMI failure: got %+v (%v), want unknown from web and an error", got, err)
+	}
+
+	got, _ = resolvePowerState(unknown, nil)
+	if got.Status != "unknown" || got.Source != powerSourceWeb {
+		t.Errorf("no IPMI: got %+v, want unknown from web", got)
+	}
+
+	body, _ := json.Marshal(got)
+	if !strings.Contains(string(body), `"status":"unknown"`) || !strings.Contains(string(body), `"source":"web"`) {
+		t.Errorf("JSON = %s, want flattened status and source", body)
+	}
+}
+
+func TestPowerConflict(t *testing.T) {
+	pending := &pendingPower{action: "off", want: idrac.PowerOff, at: time.Now()}
+	tests := []struct {
+		action  string
+		current idrac.PowerState
+		pending *pendingPower
+		want    string
+	}{
+		{"on", idrac.PowerOn, nil, "server is already on"},
+		{"on", idrac.PowerOff, nil, ""},
+		{"shutdown", idrac.PowerOff, nil, "server is already off"},
+		{"restart", idrac.PowerOff, nil, "server is off; restart requires it to be on"},
+		{"restart", idrac.PowerOn, nil, ""},
+		{"off", idrac.PowerInvalid, nil, "server is transitioning: power state is indeterminate"},
+		{"on", idrac.PowerOn, pending, "server is transitioning: off requested 0s ago"},
+	}
+	for _, tt := range tests {
+		if got := powerConflict(tt.action, tt.current, tt.pending); got != tt.want {
+			t.Errorf("powerConflict(%s, %s) = %q, want %q", tt.action, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestPendingPower_Settles(t *testing.T) {
+	h := &Handlers{}
+	h.pending.Store("s1", pendingPower{action: "off", want: idrac.PowerOff, at: time.Now()})
+
+	if p := h.pendingPower("s1", idrac.PowerOn); p == nil {
+		t.Fatal("action should be pending while the state is unchanged")
+	}
+	if p := h.pendingPower("s1", idrac.PowerOff); p != nil {
+		t.Error("action should settle once the end state is reached")
+	}
+	if _, ok := h.pending.Load("s1"); ok {
+		t.Error("settled action should be dropped")
+	}
+}
+
+func TestSetPower_Conflict(t *testing.T) {
+	server := mockIDRAC(t) // always reports on
+
+	cfg := &Config{Hosts: map[string]*HostConfig{
+		"s1": {Host: server.Addr(), Username: "root", Password: "calvin"},
+	}}
+	router := NewRouter(cfg)
+
+	for body, want := range map[string]int{
+		`{"action":"on"}`:              http.StatusConflict,
+		`{"action":"on","force":true}`: http.StatusOK,
+	} {
+		req := httptest.NewRequest("POST", "/api/hosts/s1/power", strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("%s: status = %d, want %d: %s", body, w.Code, want, w.Body.String())
+		}
+	}
+}
+
+func TestWaitForPowerState_Canceled(t *testing.T) {
+	shortPowerPolling(t)
+	powerWaitTimeout = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	stuck := &fakePower{states: []idrac.PowerState{idrac.PowerOn}}
+	start := time.Now()
+	if _, err := waitForPowerState(ctx, stuck, "off", idrac.PowerOn); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waitForPowerState() error = %v, want the context's", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("waitForPowerState() kept polling after its context ended")
+	}
+}
+
+func TestPowerActionError(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want int
+	}{
+		{errors.New("setting power state: rejected"), http.StatusBadRequest},
+		{&idrac.Error{Status: http.StatusBadGateway, Code: idrac.CodeUnreachable, Err: errors.New("dial")}, http.StatusBadGateway},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+	} {
+		w := httptest.NewRecorder()
+		powerActionError(w, tt.err)
+		if w.Code != tt.want {
+			t.Errorf("powerActionError(%v) status = %d, want %d", tt.err, w.Code, tt.want)
+		}
+	}
+}
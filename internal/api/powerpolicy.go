@@ -0,0 +1,72 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// powerOnDelayUnsupported explains why a power-on delay cannot be set.
+const powerOnDelayUnsupported = "the power-on delay is the BIOS \"AC Power Recovery Delay\" setting, which iDRAC6 cannot read or change; set it in BIOS setup"
+
+// GetPowerPolicy returns what the host does when AC power returns after a
+// loss (always-off, last-state, or always-on) and which of those the
+// chassis supports, over IPMI.
+func (h *Handlers) GetPowerPolicy(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	policy, err := client.GetPowerPolicy()
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, policy)
+}
+
+// SetPowerPolicy sets the power restore policy over IPMI. The ipmi
+// package rejects unknown policies and ones the chassis does not support
+// with 400. A power-on delay is answered
+// with 501: iDRAC6 has no interface to it.
+func (h *Handlers) SetPowerPolicy(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+
+	var req struct {
+		Policy       string          `json:"policy"`
+		PowerOnDelay json.RawMessage `json:"powerOnDelay,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+	if req.PowerOnDelay != nil {
+		writeError(w, http.StatusNotImplemented, powerOnDelayUnsupported)
+		return
+	}
+	setSpanAction(r, "power-policy "+req.Policy)
+	if !h.authorize(w, r, "power.policy."+req.Policy, hostID) {
+		return
+	}
+
+	client, err := h.getIPMI(hostID)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+
+	policy, err := client.SetPowerPolicy(req.Policy)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	log.Printf("audit: set power restore policy on %s to %s (%s)", hostID, req.Policy, r.RemoteAddr)
+
+	writeJSON(w, http.StatusOK, policy)
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"log"
+	"maps"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// refreshTick is how often the refresher checks for due hosts (variable
+// for tests).
+var refreshTick = time.Second
+
+// defaultRefreshConcurrency caps simultaneous background refreshes when
+// Config.RefreshConcurrency is zero.
+const defaultRefreshConcurrency = 4
+
+// refreshState is one host's background refresh schedule and its latest
+// readings.
+type refreshState struct {
+	next     time.Time
+	last     time.Time
+	duration time.Duration
+	err      string
+	running  bool
+	power    *powerReading
+	sensors  *idrac.SensorData
+}
+
+// refresher polls power and sensors for every host in the background so
+// "?cached=true" reads are instant. Each host is first polled at a random
+// offset within the interval and then every interval ± 10%, so a fleet's
+// polls spread out instead of firing on the same tick, and at most
+// cap(sem) refreshes run at once.
+type refresher struct {
+	h        *Handlers
+	interval time.Duration
+	jitter   time.Duration
+	sem      chan struct{}
+	rand     func() float64 // [0, 1); replaced in tests
+
+	mu    sync.Mutex
+	hosts map[string]*refreshState
+}
+
+func newRefresher(h *Handlers, interval time.Duration, concurrency int) *refresher {
+	if concurrency <= 0 {
+		concurrency = defaultRefreshConcurrency
+	}
+	return &refresher{
+		h:        h,
+		interval: interval,
+		jitter:   interval / 10,
+		sem:      make(chan struct{}, concurrency),
+		rand:     rand.Float64,
+		hosts:    make(map[string]*refreshState),
+	}
+}
+
+// nextRun returns the time of the poll after one at now.
+func (rf *refresher) nextRun(now time.Time) time.Time {
+	offset := time.Duration((rf.rand()*2 - 1) * float64(rf.jitter))
+	return now.Add(rf.interval + offset)
+}
+
+// due syncs the schedule with the configured hosts and returns the hosts
+// whose poll is due, marking them running. New hosts start at a random
+// offset within the interval; removed and disabled hosts are dropped.
+func (rf *refresher) due(now time.Time) []string {
+	ids := rf.h.activeHostIDs()
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	current := make(map[string]bool, len(ids))
+	var out []string
+	for _, id := range ids {
+		current[id] = true
+		st, ok := rf.hosts[id]
+		if !ok {
+			st = &refreshState{next: now.Add(time.Duration(rf.rand() * float64(rf.interval)))}
+			rf.hosts[id] = st
+		}
+		if !st.running && !now.Before(st.next) {
+			st.running = true
+			out = append(out, id)
+		}
+	}
+	for id := range rf.hosts {
+		if !current[id] {
+			delete(rf.hosts, id)
+		}
+	}
+	return out
+}
+
+// run dispatches due refreshes every refreshTick until stop is closed.
+func (rf *refresher) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(refreshTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			for _, id := range rf.due(now) {
+				go rf.refresh(id)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// refresh polls one host once a concurrency slot is free and records the
+// readings. The next poll is scheduled from when this one finished.
+func (rf *refresher) refresh(id string) {
+	rf.sem <- struct{}{}
+	defer func() { <-rf.sem }()
+
+	start := time.Now()
+	power, sensors, err := rf.poll(id)
+	finished := time.Now()
+	if err == nil {
+		rf.h.checkThresholds(id, rf.h.displaySensors(id, sensors))
+	}
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	st, ok := rf.hosts[id]
+	if !ok {
+		return // removed while polling
+	}
+	st.running = false
+	st.last = finished
+	st.duration = finished.Sub(start)
+	st.next = rf.nextRun(finished)
+	st.err = ""
+	if err != nil {
+		st.err = err.Error()
+		log.Printf("background refresh of %s failed: %v", id, err)
+		return
+	}
+	st.power, st.sensors = power, sensors
+}
+
+func (rf *refresher) poll(id string) (*powerReading, *idrac.SensorData, error) {
+	client, err := rf.h.getClient(context.Background(), id)
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := client.GetPowerState()
+	if err != nil {
+		return nil, nil, err
+	}
+	sensors, err := client.GetSensors()
+	if err != nil {
+		return nil, nil, err
+	}
+	return rf.h.resolvePower(id, status), sensors, nil
+}
+
+// cachedPower returns the latest background power reading and its time.
+func (rf *refresher) cachedPower(id string) (*powerReading, time.Time, bool) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	st, ok := rf.hosts[id]
+	if !ok || st.power == nil {
+		return nil, time.Time{}, false
+	}
+	return st.power, st.last, true
+}
+
+// cachedSensors returns a copy of the latest background sensor reading and
+// its time. It is a copy because callers rename sensors in place.
+func (rf *refresher) cachedSensors(id string) (*idrac.SensorData, time.Time, bool) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	st, ok := rf.hosts[id]
+	if !ok || st.sensors == nil {
+		return nil, time.Time{}, false
+	}
+	return cloneSensors(st.sensors), st.last, true
+}
+
+func cloneSensors(data *idrac.SensorData) *idrac.SensorData {
+	return &idrac.SensorData{
+		Temperatures: slices.Clone(data.Temperatures),
+		Fans:         slices.Clone(data.Fans),
+		Voltages:     slices.Clone(data.Voltages),
+		Errors:       maps.Clone(data.Errors),
+	}
+}
+
+// displaySensors returns a renamed copy of a host's raw readings.
+func (h *Handlers) displaySensors(hostID string, data *idrac.SensorData) *idrac.SensorData {
+	out := cloneSensors(data)
+	h.renameSensors(hostID, out)
+	return out
+}
+
+// refreshHostSchedule is the JSON view of a host's refresh state.
+type refreshHostSchedule struct {
+	ID             string     `json:"id"`
+	NextRun        time.Time  `json:"nextRun"`
+	LastRun        *time.Time `json:"lastRun,omitempty"`
+	LastDurationMs float64    `json:"lastDurationMs,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	Running        bool       `json:"running,omitempty"`
+}
+
+// refreshSchedule is the effective background refresh schedule reported
+// by /api/stats.
+type refreshSchedule struct {
+	IntervalSeconds float64               `json:"intervalSeconds"`
+	JitterSeconds   float64               `json:"jitterSeconds"`
+	Concurrency     int                   `json:"concurrency"`
+	InFlight        int                   `json:"inFlight"`
+	Hosts           []refreshHostSchedule `json:"hosts"`
+}
+
+func (rf *refresher) schedule() refreshSchedule {
+	ids := rf.h.activeHostIDs()
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+
+	out := refreshSchedule{
+		IntervalSeconds: rf.interval.Seconds(),
+		JitterSeconds:   rf.jitter.Seconds(),
+		Concurrency:     cap(rf.sem),
+		InFlight:        len(rf.sem),
+		Hosts:           []refreshHostSchedule{},
+	}
+	for _, id := range ids {
+		st, ok := rf.hosts[id]
+		if !ok {
+			continue
+		}
+		hs := refreshHostSchedule{ID: id, NextRun: st.next, LastError: st.err, Running: st.running}
+		if !st.last.IsZero() {
+			last := st.last
+			hs.LastRun = &last
+			hs.LastDurationMs = float64(st.duration) / float64(time.Millisecond)
+		}
+		out.Hosts = append(out.Hosts, hs)
+	}
+	return out
+}
+
+// setAge sets the Age header for a cached reading taken at t.
+func setAge(w http.ResponseWriter, t time.Time) {
+	w.Header().Set("Age", strconv.Itoa(int(time.Since(t).Seconds())))
+}
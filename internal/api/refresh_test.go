@@ -0,0 +1,84 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestRefresherSchedule(t *testing.T) {
+	h := &Handlers{config: &Config{Hosts: map[string]*HostConfig{
+		"a": {Host: "10.0.0.1"},
+		"b": {Host: "10.0.0.2"},
+	}}}
+	rf := newRefresher(h, 10*time.Second, 0)
+	rf.rand = func() float64 { return 0.5 }
+
+	now := time.Now()
+	if due := rf.due(now); len(due) != 0 {
+		t.Fatalf("due(now) = %v, want none before the initial offset", due)
+	}
+	if due := rf.due(now.Add(5 * time.Second)); len(due) != 2 {
+		t.Fatalf("due(+5s) = %v, want both hosts at the 50%% offset", due)
+	}
+	if due := rf.due(now.Add(time.Minute)); len(due) != 0 {
+		t.Errorf("due() = %v, running hosts should not be dispatched twice", due)
+	}
+
+	rf.rand = func() float64 { return 0 }
+	if got := rf.nextRun(now).Sub(now); got != 9*time.Second {
+		t.Errorf("nextRun at rand=0 = %v, want interval-jitter (9s)", got)
+	}
+
+	h.config.Hosts = map[string]*HostConfig{"a": {Host: "10.0.0.1"}}
+	rf.due(now)
+	if sched := rf.schedule(); len(sched.Hosts) != 1 || sched.Hosts[0].ID != "a" || sched.Concurrency != defaultRefreshConcurrency {
+		t.Errorf("schedule() = %+v, want only host a with default concurrency", sched)
+	}
+
+	h.config.Hosts["a"].Disabled = true
+	if due := rf.due(now.Add(time.Hour)); len(due) != 0 || len(rf.schedule().Hosts) != 0 {
+		t.Errorf("due() = %v, disabled hosts should not be polled", due)
+	}
+}
+
+func TestRefresherRefresh(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{
+			"s1": {Host: addr, Username: "root", Password: "calvin"},
+		}},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+	rf := newRefresher(h, time.Minute, 1)
+	rf.due(time.Now())
+
+	// With the only slot taken, the refresh waits for it.
+	rf.sem <- struct{}{}
+	done := make(chan struct{})
+	go func() {
+		rf.refresh("s1")
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("refresh ran past the concurrency cap")
+	case <-time.After(50 * time.Millisecond):
+	}
+	<-rf.sem
+	<-done
+
+	reading, at, ok := rf.cachedPower("s1")
+	if !ok || reading.State != idrac.PowerOn || at.IsZero() {
+		t.Fatalf("cachedPower() = %+v, %v, %v; want on", reading, at, ok)
+	}
+	if _, _, ok := rf.cachedSensors("s1"); !ok {
+		t.Error("cachedSensors() should have a reading")
+	}
+	sched := rf.schedule()
+	if len(sched.Hosts) != 1 || sched.Hosts[0].LastRun == nil || !sched.Hosts[0].NextRun.After(*sched.Hosts[0].LastRun) {
+		t.Errorf("schedule() = %+v, want a last run and a later next run", sched)
+	}
+}
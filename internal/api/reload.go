@@ -0,0 +1,139 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"syscall"
+
+	"github.com/williamzujkowski/idrac6-manager/internal/ipmi"
+)
+
+// reloadResult reports how a config reload changed the host map.
+type reloadResult struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Changed   []string `json:"changed"`
+	Unchanged int      `json:"unchanged"`
+}
+
+// reload re-reads the config file and applies its host map and groups.
+// The file is the source of truth: hosts added at runtime via POST
+// /api/hosts are removed if absent from it. Removed and changed hosts
+// have their cached clients evicted so the next request uses the new
+// settings.
+func (h *Handlers) reload() (*reloadResult, error) {
+	fc, err := LoadConfigFile(h.config.ConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	res := h.applyHosts(fc.HostMap(), true)
+	h.hostsMu.Lock()
+	h.config.Groups = fc.Groups
+	h.hostsMu.Unlock()
+	return res, nil
+}
+
+// applyHosts adds and updates hosts from next, evicting cached clients of
+// changed hosts. With replace, hosts absent from next are removed too.
+// Eviction logs out over the network, so it runs after hostsMu is
+// released.
+func (h *Handlers) applyHosts(next map[string]*HostConfig, replace bool) *reloadResult {
+	res := &reloadResult{Added: []string{}, Removed: []string{}, Changed: []string{}}
+
+	h.hostsMu.Lock()
+	for _, id := range h.config.hostIDs() {
+		if _, ok := next[id]; !ok && replace {
+			delete(h.config.Hosts, id)
+			res.Removed = append(res.Removed, id)
+		}
+	}
+
+	nextIDs := (&Config{Hosts: next}).hostIDs()
+	for _, id := range nextIDs {
+		hc := next[id]
+		old, ok := h.config.Hosts[id]
+		switch {
+		case !ok:
+			res.Added = append(res.Added, id)
+		case !reflect.DeepEqual(old, hc):
+			res.Changed = append(res.Changed, id)
+		default:
+			res.Unchanged++
+			continue
+		}
+		h.config.Hosts[id] = hc
+	}
+	h.hostsMu.Unlock()
+
+	for _, id := range slices.Concat(res.Removed, res.Changed) {
+		h.evictHost(id)
+	}
+	return res
+}
+
+// evictHost logs out and drops every cached client for a host.
+func (h *Handlers) evictHost(id string) {
+	h.pool.Evict(id)
+	h.forgetHost(id)
+}
+
+// abandonHost is evictHost without logging out of the web session, for an
+// iDRAC that is about to reset and drop its sessions itself.
+func (h *Handlers) abandonHost(id string) {
+	h.pool.Forget(id)
+	h.forgetHost(id)
+}
+
+// forgetHost drops a host's cached state other than its web client.
+func (h *Handlers) forgetHost(id string) {
+	h.vmedia.Delete(id)
+	h.admin.Delete(id)
+	h.licenses.Delete(id)
+	h.breakers.Delete(id)
+	h.forgetLastGood(id)
+	if v, ok := h.ipmi.LoadAndDelete(id); ok {
+		v.(*ipmi.Client).Close()
+	}
+}
+
+// Reload re-reads the config file and reports which hosts changed.
+func (h *Handlers) Reload(w http.ResponseWriter, _ *http.Request) {
+	if h.config.ConfigPath == "" {
+		writeError(w, http.StatusConflict, "no config file to reload (start with --config)")
+		return
+	}
+
+	res, err := h.reload()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	log.Printf("Config reloaded: added=%v removed=%v changed=%v", res.Added, res.Removed, res.Changed)
+	writeJSON(w, http.StatusOK, res)
+}
+
+// reloadOnSIGHUP reloads the config file each time the process gets
+// SIGHUP, until stop is closed.
+func (h *Handlers) reloadOnSIGHUP(stop <-chan struct{}) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+	for {
+		select {
+		case <-sig:
+		case <-stop:
+			return
+		}
+		res, err := h.reload()
+		if err != nil {
+			log.Printf("Config reload failed, keeping current config: %v", err)
+			continue
+		}
+		log.Printf("Config reloaded (SIGHUP): added=%v removed=%v changed=%v", res.Added, res.Removed, res.Changed)
+	}
+}
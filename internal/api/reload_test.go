@@ -0,0 +1,190 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+hosts:
+  - id: r710
+    name: R710
+    host: 10.0.0.1
+    username: root
+    password: calvin
+    tags: [prod]
+    login_form:
+      user_field: username
+    action_timeout: 2m
+    racadm_timeout: 90s
+api_key: secret
+`)
+
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	hosts := fc.HostMap()
+	hc, ok := hosts["r710"]
+	if !ok || hc.Host != "10.0.0.1" || !hc.hasTag("prod") {
+		t.Fatalf("hosts = %+v, want r710 at 10.0.0.1 tagged prod", hosts)
+	}
+	if hc.LoginForm == nil || hc.LoginForm.UserField != "username" {
+		t.Errorf("LoginForm = %+v, want user_field username", hc.LoginForm)
+	}
+	if hc.ActionTimeout != 2*time.Minute || hc.RACADMTimeout != 90*time.Second || hc.QueryTimeout != 0 {
+		t.Errorf("timeouts = %v/%v/%v, want query unset, action 2m, racadm 90s", hc.QueryTimeout, hc.ActionTimeout, hc.RACADMTimeout)
+	}
+	if fc.APIKey != "secret" {
+		t.Errorf("APIKey = %q, want secret", fc.APIKey)
+	}
+}
+
+func TestLoadConfigFile_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown key":  "hosts:\n  - id: a\n    hots: 10.0.0.1\n",
+		"missing id":   "hosts:\n  - host: 10.0.0.1\n    username: root\n    password: x\n",
+		"duplicate id": "hosts:\n  - {id: a, host: h, username: u, password: p}\n  - {id: a, host: h, username: u, password: p}\n",
+		"no password":  "hosts:\n  - {id: a, host: h, username: u}\n",
+		"empty group":  "hosts:\n  - {id: a, host: h, username: u, password: p}\ngroups:\n  rack1: {}\n",
+		"group host":   "hosts:\n  - {id: a, host: h, username: u, password: p}\ngroups:\n  rack1: {hosts: [b]}\n",
+		"host scheme":  "hosts:\n  - {id: a, host: 'ftp://h', username: u, password: p}\n",
+		"timeout":      "hosts:\n  - {id: a, host: h, username: u, password: p, query_timeout: -5s}\n",
+		"basic auth":   "hosts:\n  - {id: a, host: h, username: u, password: p}\nbasic_auth: {username: ops}\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			writeConfig(t, path, content)
+			if _, err := LoadConfigFile(path); err == nil {
+				t.Error("LoadConfigFile() should fail")
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+hosts:
+  - {id: keep, host: 10.0.0.1, username: root, password: calvin}
+  - {id: rotate, host: 10.0.0.2, username: root, password: new-password}
+  - {id: fresh, host: 10.0.0.3, username: root, password: calvin}
+`)
+
+	cfg := &Config{
+		ConfigPath: path,
+		Hosts: map[string]*HostConfig{
+			"keep":   {Host: "10.0.0.1", Username: "root", Password: "calvin"},
+			"rotate": {Host: "10.0.0.2", Username: "root", Password: "old-password"},
+			"gone":   {Host: "10.0.0.9", Username: "root", Password: "calvin"},
+		},
+	}
+	h := &Handlers{config: cfg, pool: idrac.NewPool(), stats: newManagerStats()}
+	h.licenses.Store("gone", idrac.LicenseExpress)
+	h.licenses.Store("rotate", idrac.LicenseExpress)
+
+	w := httptest.NewRecorder()
+	h.Reload(w, httptest.NewRequest("POST", "/api/reload", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+	}
+
+	var res reloadResult
+	json.NewDecoder(w.Body).Decode(&res)
+	if strings.Join(res.Added, ",") != "fresh" || strings.Join(res.Removed, ",") != "gone" ||
+		strings.Join(res.Changed, ",") != "rotate" || res.Unchanged != 1 {
+		t.Errorf("reload result = %+v, want added=[fresh] removed=[gone] changed=[rotate] unchanged=1", res)
+	}
+
+	if _, ok := h.hostConfig("gone"); ok {
+		t.Error("removed host still configured")
+	}
+	if hc, _ := h.hostConfig("rotate"); hc.Password != "new-password" {
+		t.Errorf("rotate password = %q, want new-password", hc.Password)
+	}
+	for _, id := range []string{"gone", "rotate"} {
+		if _, ok := h.licenses.Load(id); ok {
+			t.Errorf("cached state for %s not evicted", id)
+		}
+	}
+}
+
+func TestReload_BadFileKeepsConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "hosts: [this is not valid")
+
+	cfg := &Config{
+		ConfigPath: path,
+		Hosts:      map[string]*HostConfig{"keep": {Host: "10.0.0.1"}},
+	}
+	router := NewRouter(cfg)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/api/reload", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if _, ok := cfg.Hosts["keep"]; !ok {
+		t.Error("a failed reload must not modify the host map")
+	}
+}
+
+func TestReloadOnSIGHUP_Stops(t *testing.T) {
+	h := &Handlers{config: &Config{}, pool: idrac.NewPool(), stats: newManagerStats()}
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		h.reloadOnSIGHUP(stop)
+		close(done)
+	}()
+
+	close(stop)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reloadOnSIGHUP did not return after stop was closed")
+	}
+}
+
+func TestLoadConfigFile_Credentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, `
+hosts:
+  - id: r710
+    host: 10.0.0.1
+    username: root
+    password: calvin
+    credentials:
+      - {label: rotated, username: root, password: s3cret}
+`)
+	fc, err := LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	if creds := fc.HostMap()["r710"].Credentials; len(creds) != 1 || creds[0].Label != "rotated" {
+		t.Errorf("credentials = %+v, want the rotated fallback", creds)
+	}
+
+	writeConfig(t, path, "hosts:\n  - {id: a, host: h, username: u, password: p, credentials: [{username: root}]}\n")
+	if _, err := LoadConfigFile(path); err == nil {
+		t.Error("LoadConfigFile() should reject a credential without a password")
+	}
+}
@@ -2,11 +2,21 @@
 package api
 
 import (
+	"cmp"
+	"context"
+	"crypto/x509"
 	"io/fs"
+	"log"
+	"net"
 	"net/http"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Config holds API server configuration.
@@ -17,6 +27,137 @@ type Config struct {
 	WebFS fs.FS
 	// APIKey is the optional API key for authentication.
 	APIKey string
+	// BasicAuth also accepts HTTP Basic logins on API routes when set up;
+	// see BasicAuth.
+	BasicAuth BasicAuth
+	// BasePath mounts all routes under a subpath (e.g. "/idrac") for
+	// reverse-proxy deployments. Empty means the root.
+	BasePath string
+	// ClientIdleTTL logs out and evicts cached iDRAC sessions unused for
+	// this long, conserving the iDRAC's limited session pool. Zero disables.
+	ClientIdleTTL time.Duration
+	// LoginConcurrency is how many logins to the same host may run at
+	// once; further requests for a host without a session queue in
+	// arrival order and reuse the session the first one establishes.
+	// Different hosts log in in parallel. Zero means 1.
+	LoginConcurrency int
+	// TLSVerify enables iDRAC certificate verification. iDRAC6 ships with
+	// self-signed certificates, so this is off by default.
+	TLSVerify bool
+	// TLSRootCAs is the pool used when TLSVerify is set (nil = system roots).
+	TLSRootCAs *x509.CertPool
+	// SourceAddress is the local address web API, RACADM, and IPMI
+	// connections to iDRACs originate from, for multi-homed management
+	// hosts. Nil leaves the choice to the OS.
+	SourceAddress net.IP
+	// QueryTimeout and ActionTimeout bound iDRAC web API reads and
+	// actions (power, Set); RACADMTimeout bounds each RACADM command over
+	// SSH. Zero keeps the idrac.DefaultTimeouts value, or for RACADM no
+	// limit. Per-host settings take precedence.
+	QueryTimeout  time.Duration
+	ActionTimeout time.Duration
+	RACADMTimeout time.Duration
+	// LogJSON writes one JSON request log line per request through
+	// log/slog, keyed by request_id, instead of chi's text logger. Install
+	// a JSON slog handler with slog.SetDefault so other log lines match.
+	LogJSON bool
+	// ReadOnly rejects every API request other than GET (and HEAD) with
+	// 403, including raw data sets, so the manager cannot change a host or
+	// its own configuration. SIGHUP reloads still apply.
+	ReadOnly bool
+	// Demo answers every host from an idrac.DemoClient instead of its
+	// iDRAC, for UI development and demos. No controller is contacted:
+	// endpoints that need RACADM, IPMI, or a raw web API request answer
+	// 501 demo_unsupported.
+	Demo bool
+	// Authorize, if set, is consulted before power actions, SEL clears,
+	// and virtual media changes; a deny is answered with 403. Nil allows
+	// everything.
+	Authorize Authorizer
+	// Debug logs raw iDRAC request URLs and response bodies, secrets
+	// redacted, and routes the raw data endpoints (which also require an
+	// API key or Basic login).
+	Debug bool
+	// DebugErrors includes panic details and stack traces in error
+	// responses. It is separate from Debug so that triaging a parser bug
+	// does not also expose internals to API clients.
+	DebugErrors bool
+	// ConfigPath is the YAML file hosts were loaded from. When set, the
+	// config can be reloaded via POST /api/reload or SIGHUP.
+	ConfigPath string
+	// IPMIPersistent keeps one IPMI session per host open across requests
+	// instead of connecting per call, reconnecting automatically on failure.
+	IPMIPersistent bool
+	// SlowThreshold is the latency above which /api/status reports a
+	// reachable host as "slow" rather than "up". Zero means 2s.
+	SlowThreshold time.Duration
+	// BreakerThreshold is how many consecutive failed requests to a host
+	// open its circuit breaker, after which requests fail fast with 503
+	// until BreakerCooldown passes and a trial request succeeds. Zero means
+	// 5; negative disables the breaker.
+	BreakerThreshold int
+	// BreakerCooldown is how long an open breaker rejects requests. Zero
+	// means 30s.
+	BreakerCooldown time.Duration
+	// SELStreamThreshold streams full SEL reads from RACADM in chunks once
+	// the log holds more than this many records, rather than loading it in
+	// one web API response; a capped read streams only the newest
+	// MaxSELEntries. Enabling it adds a RACADM record count to every full
+	// read. Zero disables.
+	SELStreamThreshold int
+	// MaxSELEntries caps the entries returned by a full SEL read, keeping
+	// the newest; the page offset counts the entries dropped. Zero means 500;
+	// negative disables the cap.
+	MaxSELEntries int
+	// RefreshInterval polls every host's power and sensors in the
+	// background at this interval (± 10% jitter, first poll at a random
+	// offset) so "?cached=true" reads return immediately. Zero disables.
+	RefreshInterval time.Duration
+	// RefreshConcurrency caps simultaneous background refreshes across all
+	// hosts. Zero means 4.
+	RefreshConcurrency int
+	// BulkConcurrency is how many hosts /api/status, /api/sensors, and
+	// group power query at once. Zero means 8.
+	BulkConcurrency int
+	// MaxBulkConcurrency caps the per-request ?concurrency= override of
+	// BulkConcurrency. Zero means 64.
+	MaxBulkConcurrency int
+	// StaleWindow serves a host's last successful sensors, power, or
+	// system info response, flagged "stale": true, when a fresh fetch fails
+	// with a 5xx and that response is younger than this. Zero disables.
+	StaleWindow time.Duration
+	// SensorNames maps raw iDRAC sensor names to display names for all
+	// hosts. Per-host SensorNames entries take precedence.
+	SensorNames map[string]string
+	// MetricLabels is the allowlist of host metadata keys exported as
+	// labels on /metrics series: "location", or the key of a "key=value"
+	// host tag such as "rack=r12". Unlisted keys are never exported.
+	MetricLabels []string
+	// SensorThresholds maps raw iDRAC sensor names to critical thresholds
+	// for all hosts, overriding the iDRAC-reported ones for threshold
+	// events. Per-host SensorThresholds entries take precedence.
+	SensorThresholds map[string]float64
+	// Webhooks are http or https URLs every threshold and intrusion event
+	// is POSTed to as JSON, as on the event stream. Like the stream, they
+	// need RefreshInterval; they are read at startup only.
+	Webhooks []string
+	// Groups maps group names (e.g. "rack-3") to their members, for
+	// group-scoped listing and power actions.
+	Groups map[string]*GroupConfig
+	// TracerProvider receives request and iDRAC operation spans. Nil uses
+	// the global OpenTelemetry provider, a no-op unless one is installed.
+	TracerProvider trace.TracerProvider
+}
+
+// hostIDs returns the configured host IDs in sorted order. Map iteration
+// order is random, so anything that lists hosts should go through this.
+func (c *Config) hostIDs() []string {
+	ids := make([]string, 0, len(c.Hosts))
+	for id := range c.Hosts {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
 }
 
 // HostConfig holds configuration for a single iDRAC host.
@@ -25,54 +166,308 @@ type HostConfig struct {
 	Host     string `json:"host" yaml:"host"`
 	Username string `json:"username" yaml:"username"`
 	Password string `json:"password" yaml:"password"`
-	SSHPort  int    `json:"sshPort,omitempty" yaml:"ssh_port,omitempty"`
+	// Credentials are fallbacks tried in order when the iDRAC rejects
+	// Username/Password, for fleets with inconsistently rotated passwords.
+	Credentials []idrac.Credential `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+	SSHPort     int                `json:"sshPort,omitempty" yaml:"ssh_port,omitempty"`
+	IPMIPort    int                `json:"ipmiPort,omitempty" yaml:"ipmi_port,omitempty"`
+
+	// Display metadata for fleet inventory.
+	Location string   `json:"location,omitempty" yaml:"location,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Notes    string   `json:"notes,omitempty" yaml:"notes,omitempty"`
+	// CABundle is a PEM file of CAs used to verify this host's certificate.
+	// Setting it enables verification and overrides the global roots.
+	CABundle string `json:"caBundle,omitempty" yaml:"ca_bundle,omitempty"`
+	// SensorNames maps raw sensor names (e.g. "System Board Ambient Temp")
+	// to display names (e.g. "Inlet"), matched case-insensitively.
+	SensorNames map[string]string `json:"sensorNames,omitempty" yaml:"sensor_names,omitempty"`
+	// SensorThresholds maps raw sensor names to critical thresholds that
+	// override the iDRAC-reported ones for threshold events.
+	SensorThresholds map[string]float64 `json:"sensorThresholds,omitempty" yaml:"sensor_thresholds,omitempty"`
+	// LoginForm overrides the login field names/order for OEM firmware quirks.
+	LoginForm *idrac.LoginForm `json:"loginForm,omitempty" yaml:"login_form,omitempty"`
+	// SessionCookie overrides the session cookie name for OEM-rebranded
+	// controllers (default _appwebSessionId_).
+	SessionCookie string `json:"sessionCookie,omitempty" yaml:"session_cookie,omitempty"`
+	// Disabled takes the host out of service for maintenance: it is still
+	// listed and exported, but skipped by status, fleet-wide reads, the
+	// background refresher, and metrics, and its own endpoints answer 423.
+	Disabled bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	// QueryTimeout, ActionTimeout, and RACADMTimeout override the global
+	// timeouts for a slow or distant controller, e.g. "30s".
+	QueryTimeout  time.Duration `json:"queryTimeout,omitempty" yaml:"query_timeout,omitempty"`
+	ActionTimeout time.Duration `json:"actionTimeout,omitempty" yaml:"action_timeout,omitempty"`
+	RACADMTimeout time.Duration `json:"racadmTimeout,omitempty" yaml:"racadm_timeout,omitempty"`
+}
+
+// GroupConfig defines a named host group. Its members are the listed
+// hosts plus every host carrying one of the tags.
+type GroupConfig struct {
+	Hosts []string `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	Tags  []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// authRequired reports whether API routes need an API key or Basic login.
+func (c *Config) authRequired() bool {
+	return c.APIKey != "" || c.BasicAuth.enabled(c.APIKey)
 }
 
-// NewRouter creates the HTTP router with all API routes.
+// hasTag reports whether the host carries the given tag (case-insensitive).
+func (hc *HostConfig) hasTag(tag string) bool {
+	for _, t := range hc.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientOptions returns the idrac.Client options for a host, combining
+// global settings with per-host overrides.
+func (c *Config) clientOptions(hc *HostConfig) ([]idrac.Option, error) {
+	var opts []idrac.Option
+	switch {
+	case hc.CABundle != "":
+		pool, err := idrac.LoadCABundle(hc.CABundle)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, idrac.WithTLSVerify(pool))
+	case c.TLSVerify:
+		opts = append(opts, idrac.WithTLSVerify(c.TLSRootCAs))
+	}
+	if hc.LoginForm != nil {
+		opts = append(opts, idrac.WithLoginForm(*hc.LoginForm))
+	}
+	if hc.SessionCookie != "" {
+		opts = append(opts, idrac.WithSessionCookie(hc.SessionCookie))
+	}
+	if len(hc.Credentials) > 0 {
+		opts = append(opts, idrac.WithCredentials(hc.Credentials...))
+	}
+	if c.SourceAddress != nil {
+		opts = append(opts, idrac.WithSourceAddress(c.SourceAddress))
+	}
+	opts = append(opts, idrac.WithTimeouts(idrac.Timeouts{
+		Query:  cmp.Or(hc.QueryTimeout, c.QueryTimeout),
+		Action: cmp.Or(hc.ActionTimeout, c.ActionTimeout),
+	}))
+	if c.TracerProvider != nil {
+		opts = append(opts, idrac.WithTracerProvider(c.TracerProvider))
+	}
+	if c.Debug {
+		opts = append(opts, idrac.WithMiddleware(idrac.DebugLog(log.Printf)))
+	}
+	return opts, nil
+}
+
+// racadmOptions returns the options for a host's RACADM connections.
+func (c *Config) racadmOptions(hc *HostConfig) []idrac.RACADMOption {
+	var opts []idrac.RACADMOption
+	if c.SourceAddress != nil {
+		opts = append(opts, idrac.WithRACADMSourceAddress(c.SourceAddress))
+	}
+	if d := cmp.Or(hc.RACADMTimeout, c.RACADMTimeout); d > 0 {
+		opts = append(opts, idrac.WithRACADMTimeout(d))
+	}
+	return opts
+}
+
+// NewRouter creates the HTTP router with all API routes. Its background
+// work runs for the life of the process; see NewRouterContext.
 func NewRouter(cfg *Config) http.Handler {
+	return NewRouterContext(context.Background(), cfg)
+}
+
+// NewRouterContext is NewRouter with a context that bounds the router's
+// background work: idle session sweeps, SIGHUP reloads, background
+// refreshes, and open event streams all stop when ctx is done.
+func NewRouterContext(ctx context.Context, cfg *Config) http.Handler {
 	r := chi.NewRouter()
 
-	r.Use(middleware.Logger)
-	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
+	r.Use(echoRequestID)
+	r.Use(tracing(cfg.TracerProvider))
+	if cfg.LogJSON {
+		r.Use(jsonLogger)
+	} else {
+		r.Use(middleware.Logger)
+	}
+	r.Use(jsonRecoverer(cfg.DebugErrors))
 	r.Use(corsMiddleware)
 
-	h := &Handlers{config: cfg}
+	h := &Handlers{config: cfg, pool: idrac.NewPool(idrac.WithLoginLimit(cfg.LoginConcurrency)), stats: newManagerStats(), done: ctx.Done()}
+	if cfg.ClientIdleTTL > 0 {
+		go h.pool.SweepIdle(cfg.ClientIdleTTL, h.done)
+	}
+	if cfg.ConfigPath != "" {
+		go h.reloadOnSIGHUP(h.done)
+	}
+	if cfg.RefreshInterval > 0 {
+		h.refresher = newRefresher(h, cfg.RefreshInterval, cfg.RefreshConcurrency)
+		h.events = newEventHub()
+		if len(cfg.Webhooks) > 0 {
+			h.startWebhooks(cfg.Webhooks, h.done)
+		}
+		go h.refresher.run(h.done)
+	}
+
+	base := normalizeBasePath(cfg.BasePath)
+	if base == "" {
+		h.mount(r, "")
+		return r
+	}
+
+	r.Route(base, func(r chi.Router) {
+		// Redirect "/base" to "/base/" so the UI's relative asset URLs resolve.
+		r.Use(func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.URL.Path == base {
+					http.Redirect(w, req, base+"/", http.StatusMovedPermanently)
+					return
+				}
+				next.ServeHTTP(w, req)
+			})
+		})
+
+		h.mount(r, base)
+	})
+
+	return r
+}
+
+// normalizeBasePath returns the base path with a leading slash and no
+// trailing slash, or "" for the root.
+func normalizeBasePath(p string) string {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return ""
+	}
+	return "/" + p
+}
+
+// mount registers the API and web UI routes. base is the prefix the router
+// is mounted under, stripped before serving static files.
+func (h *Handlers) mount(r chi.Router, base string) {
+	cfg := h.config
+
+	// Outside the /api group so the UI can learn auth is required before
+	// it has a key.
+	r.Get("/api/ui-config", h.UIConfig)
 
 	r.Route("/api", func(r chi.Router) {
-		if cfg.APIKey != "" {
-			r.Use(apiKeyAuth(cfg.APIKey))
+		if cfg.authRequired() {
+			r.Use(apiKeyAuth(cfg.APIKey, cfg.BasicAuth))
+		}
+		if cfg.ReadOnly {
+			r.Use(readOnly)
 		}
 
 		r.Get("/health", h.Health)
+		r.Get("/version", h.Version)
+		r.Get("/stats", h.Stats)
+		r.Get("/status", h.Status)
+		r.Get("/diagnostics/tls", h.DiagnoseTLS)
+		r.Post("/reload", h.Reload)
+		r.Get("/config/export", h.ExportConfig)
+		r.Post("/config/import", h.ImportConfig)
 
 		r.Get("/hosts", h.ListHosts)
 		r.Post("/hosts", h.AddHost)
 
+		r.Get("/sensors", h.GetAllSensors)
+		r.Get("/events", h.Events)
+
+		r.Get("/groups", h.ListGroups)
+		r.Get("/groups/{group}", h.GetGroup)
+		r.Post("/groups/{group}/power", h.SetGroupPower)
+
 		r.Route("/hosts/{hostID}", func(r chi.Router) {
 			r.Use(h.hostCtx)
+			r.Use(h.circuitBreaker)
+			r.Use(h.trackLatency)
 
 			r.Get("/power", h.GetPower)
 			r.Post("/power", h.SetPower)
+			r.Get("/power/detail", h.GetPowerDetail)
+			r.Get("/power/policy", h.GetPowerPolicy)
+			r.Post("/power/policy", h.SetPowerPolicy)
+			r.Get("/power/stats", h.GetPowerStats)
+			r.Post("/power/stats/reset", h.ResetPowerStats)
 
 			r.Get("/sensors", h.GetSensors)
 
 			r.Get("/info", h.GetSystemInfo)
+			r.Get("/info/extended", h.GetExtendedSystemInfo)
+			r.Get("/capabilities", h.GetCapabilities)
+			r.Get("/snapshot", h.GetSnapshot)
+			r.Get("/diagnostics", h.GetDiagBundle)
+			r.Get("/selftest", h.GetSelfTest)
+			r.Get("/assettag", h.GetAssetTag)
+			r.Post("/assettag", h.SetAssetTag)
+			r.Get("/lcd", h.GetLCD)
+			r.Post("/lcd", h.SetLCD)
+
+			r.Get("/sessions", h.ListSessions)
+			r.Delete("/sessions/{sessionID}", h.KillSession)
+			r.Get("/console/sessions", h.GetConsoleSessions)
+
+			r.Get("/idrac/name", h.GetIDRACName)
+			r.Get("/idrac/network", h.GetIDRACNetwork)
+			r.Get("/idrac/alerts", h.GetIDRACAlerts)
+			r.Post("/idrac/name", h.SetIDRACName)
+			r.Post("/idrac/password", h.ChangePassword)
+
+			r.Get("/ipmi/power", h.GetIPMIPower)
+			r.Post("/ipmi/power", h.SetIPMIPower)
+			r.Get("/ipmi/watchdog", h.GetWatchdog)
+			r.Post("/ipmi/watchdog", h.SetWatchdog)
+			r.Post("/ipmi/watchdog/reset", h.ResetWatchdog)
+			r.Get("/ipmi/lan", h.GetLANConfig)
+			r.Get("/ipmi/users", h.GetIPMIUsers)
+			r.Get("/ipmi/snapshot", h.GetIPMISnapshot)
+
+			r.Get("/boot/override", h.GetBootOverride)
+			r.Post("/boot/once", h.SetBootOnce)
+			r.Get("/bootorder", h.GetBootOrder)
+			r.Post("/bootorder", h.SetBootOrder)
+
+			r.Post("/firmware/update", h.StartFirmwareUpdate)
+			r.Get("/firmware/jobs/{jobID}", h.GetFirmwareJob)
+
+			if cfg.Debug {
+				r.Get("/raw", h.RawData)
+				r.Post("/raw", h.RawSet)
+			}
 
 			r.Get("/sel", h.GetSEL)
+			r.Get("/sel/summary", h.GetSELSummary)
+			r.Get("/intrusion", h.GetIntrusion)
+			r.Get("/crashscreen", h.GetCrashScreen)
+			r.Delete("/crashscreen", h.ClearCrashScreen)
+			r.Post("/certificate/regenerate", h.RegenerateCertificate)
 			r.Delete("/sel", h.ClearSEL)
 
-			r.Get("/virtualmedia", h.GetVirtualMedia)
-			r.Post("/virtualmedia", h.MountVirtualMedia)
-			r.Delete("/virtualmedia", h.UnmountVirtualMedia)
+			r.Group(func(r chi.Router) {
+				r.Use(h.requireEnterprise)
+
+				r.Get("/virtualmedia", h.GetVirtualMedia)
+				r.Post("/virtualmedia", h.MountVirtualMedia)
+				r.Delete("/virtualmedia", h.UnmountVirtualMedia)
+			})
 		})
 	})
 
+	if cfg.authRequired() {
+		r.With(apiKeyAuth(cfg.APIKey, cfg.BasicAuth)).Get("/metrics", h.Metrics)
+	} else {
+		r.Get("/metrics", h.Metrics)
+	}
+
 	// Serve web UI
 	if cfg.WebFS != nil {
 		fileServer := http.FileServer(http.FS(cfg.WebFS))
-		r.Handle("/*", fileServer)
+		r.Handle("/*", http.StripPrefix(base, fileServer))
 	}
-
-	return r
 }
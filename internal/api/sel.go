@@ -0,0 +1,177 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// selPage is a page of the SEL. Truncated is set when a full read was
+// capped at MaxSELEntries: the page holds the newest entries, and the
+// older ones it left out sit at offsets below Offset.
+type selPage struct {
+	Page[idrac.SELEntry]
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// selTail returns the page for entries at the end of a log of total
+// records, as RACADM's since and last reads return.
+func selTail(entries []idrac.SELEntry, total int) *Page[idrac.SELEntry] {
+	if entries == nil {
+		entries = []idrac.SELEntry{}
+	}
+	return &Page[idrac.SELEntry]{Items: entries, Total: total, Offset: max(total-len(entries), 0)}
+}
+
+// selRanger is the subset of idrac.Admin used to page the SEL.
+type selRanger interface {
+	GetSELRange(start, count int) ([]idrac.SELEntry, error)
+}
+
+// selRangePage reads the window of at most limit records starting offset
+// records into a SEL of total records via RACADM, oldest first. getsel -s
+// takes a 1-based position in the log rather than a record ID, so RACADM
+// pages are addressed by offset like every other list.
+func selRangePage(s selRanger, total, offset, limit int) (*Page[idrac.SELEntry], error) {
+	page := &Page[idrac.SELEntry]{Items: []idrac.SELEntry{}, Total: total, Offset: offset, Limit: limit}
+	if offset >= total {
+		return page, nil
+	}
+	entries, err := s.GetSELRange(offset+1, limit)
+	if err != nil {
+		return nil, err
+	}
+	if entries != nil {
+		page.Items = entries
+	}
+	page.HasMore = offset+len(entries) < total
+	return page, nil
+}
+
+// defaultMaxSELEntries caps full SEL reads when Config.MaxSELEntries is zero.
+const defaultMaxSELEntries = 500
+
+// maxSELEntries returns the full-read cap, or 0 when capping is disabled.
+func (h *Handlers) maxSELEntries() int {
+	switch n := h.config.MaxSELEntries; {
+	case n == 0:
+		return defaultMaxSELEntries
+	case n < 0:
+		return 0
+	default:
+		return n
+	}
+}
+
+// parseSeverityFilter parses a comma-separated list of normalized
+// severities (normal, warning, critical). An empty list matches all.
+func parseSeverityFilter(s string) (map[string]bool, error) {
+	if s == "" {
+		return nil, nil
+	}
+	want := make(map[string]bool)
+	for _, sev := range strings.Split(s, ",") {
+		sev = strings.ToLower(strings.TrimSpace(sev))
+		switch sev {
+		case idrac.SeverityNormal, idrac.SeverityWarning, idrac.SeverityCritical:
+			want[sev] = true
+		default:
+			return nil, fmt.Errorf("unknown severity %q (want normal, warning or critical)", sev)
+		}
+	}
+	return want, nil
+}
+
+// filterSEL keeps entries whose normalized severity is in want (all when
+// want is nil).
+func filterSEL(entries []idrac.SELEntry, want map[string]bool) []idrac.SELEntry {
+	if want == nil {
+		return entries
+	}
+	filtered := make([]idrac.SELEntry, 0, len(entries))
+	for _, e := range entries {
+		if want[idrac.NormalizeSeverity(e.Severity)] {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// capSEL keeps entries whose normalized severity is in want, then, when
+// more than limit remain, the newest limit of them with Truncated set.
+// Filtering comes first so relevant entries are not crowded out by
+// routine ones.
+func capSEL(entries []idrac.SELEntry, want map[string]bool, limit int) *selPage {
+	filtered := filterSEL(entries, want)
+	if limit <= 0 || len(filtered) <= limit {
+		return &selPage{Page: *newPage(filtered, 0, 0)}
+	}
+	return &selPage{Page: *newPage(filtered, len(filtered)-limit, limit), Truncated: true}
+}
+
+// largeSEL reports whether the host's SEL exceeds SELStreamThreshold,
+// returning the RACADM admin to read it with and its record count. Any
+// RACADM failure falls back to the web API.
+func (h *Handlers) largeSEL(r *http.Request, hostID string) (*idrac.Admin, int, bool) {
+	admin, err := h.requestAdmin(r, hostID)
+	if err != nil {
+		return nil, 0, false
+	}
+	total, err := admin.SELCount()
+	if err != nil {
+		log.Printf("SEL count for %s failed, using web API: %v", hostID, err)
+		return nil, 0, false
+	}
+	return admin, total, total > h.config.SELStreamThreshold
+}
+
+// selStreamer is the subset of idrac.Admin used to stream the SEL.
+type selStreamer interface {
+	StreamSEL(skip, chunk int, fn func([]idrac.SELEntry) error) error
+}
+
+// writeSELStream writes the SEL from skip records in to the end as a
+// selPage-shaped JSON document, encoding each RACADM chunk as it arrives;
+// a nonzero skip is reported as the offset with truncated set. The status
+// is committed before the first chunk, so a failure mid-stream is
+// reported in a trailing "error" field alongside the entries read so far.
+func writeSELStream(w http.ResponseWriter, s selStreamer, skip, chunk int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+
+	w.Write([]byte(`{"items":[`)) //nolint:errcheck
+	count := 0
+	err := s.StreamSEL(skip, chunk, func(entries []idrac.SELEntry) error {
+		for _, e := range entries {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			if count > 0 {
+				data = append([]byte{','}, data...)
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+			count++
+		}
+		rc.Flush() //nolint:errcheck
+		return nil
+	})
+
+	w.Write([]byte(`],"total":` + strconv.Itoa(skip+count) + `,"offset":` + strconv.Itoa(skip) + `,"limit":0,"hasMore":false`)) //nolint:errcheck
+	if skip > 0 {
+		w.Write([]byte(`,"truncated":true`)) //nolint:errcheck
+	}
+	if err != nil {
+		msg, _ := json.Marshal(err.Error())
+		w.Write([]byte(`,"error":` + string(msg))) //nolint:errcheck
+	}
+	w.Write([]byte("}\n")) //nolint:errcheck
+}
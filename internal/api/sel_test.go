@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+// fakeSELStreamer yields records 1..records in chunks, failing after
+// failAfter chunks when set.
+type fakeSELStreamer struct {
+	records   int
+	failAfter int
+}
+
+func (f *fakeSELStreamer) StreamSEL(skip, chunk int, fn func([]idrac.SELEntry) error) error {
+	for start, n := skip+1, 0; start <= f.records; start, n = start+chunk, n+1 {
+		if f.failAfter > 0 && n == f.failAfter {
+			return errors.New("ssh: connection lost")
+		}
+		var entries []idrac.SELEntry
+		for id := start; id < start+chunk && id <= f.records; id++ {
+			entries = append(entries, idrac.SELEntry{ID: fmt.Sprint(id), Severity: "Ok"})
+		}
+		if err := fn(entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestWriteSELStream(t *testing.T) {
+	w := httptest.NewRecorder()
+	// Wrapped as the tracing and stats middleware do, chunks must still
+	// be flushed.
+	writeSELStream(&statusRecorder{ResponseWriter: w}, &fakeSELStreamer{records: 5}, 0, 2)
+	if !w.Flushed {
+		t.Error("chunks were not flushed through the wrapped writer")
+	}
+
+	var page Page[idrac.SELEntry]
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if page.Total != 5 || len(page.Items) != 5 || page.Items[4].ID != "5" {
+		t.Errorf("page = %+v, want records 1..5", page)
+	}
+
+	w = httptest.NewRecorder()
+	writeSELStream(w, &fakeSELStreamer{records: 5, failAfter: 1}, 0, 2)
+	var partial struct {
+		Page[idrac.SELEntry]
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &partial); err != nil {
+		t.Fatalf("invalid JSON after failure %q: %v", w.Body.String(), err)
+	}
+	if partial.Total != 2 || partial.Error == "" {
+		t.Errorf("partial = %+v, want 2 entries and an error", partial)
+	}
+
+	// A capped read streams only the newest entries.
+	w = httptest.NewRecorder()
+	writeSELStream(w, &fakeSELStreamer{records: 5}, 3, 2)
+	var capped selPage
+	if err := json.Unmarshal(w.Body.Bytes(), &capped); err != nil {
+		t.Fatalf("invalid JSON %q: %v", w.Body.String(), err)
+	}
+	if capped.Total != 5 || capped.Offset != 3 || !capped.Truncated || len(capped.Items) != 2 || capped.Items[0].ID != "4" {
+		t.Errorf("capped = %+v, want records 4 and 5 of 5, truncated", capped)
+	}
+}
+
+// fakeSELRanger serves range reads from records 1..records, recording
+// the start of each.
+type fakeSELRanger struct {
+	records int
+	starts  []int
+}
+
+func (f *fakeSELRanger) GetSELRange(start, count int) ([]idrac.SELEntry, error) {
+	f.starts = append(f.starts, start)
+	var entries []idrac.SELEntry
+	for pos := start; pos < start+count && pos <= f.records; pos++ {
+		// Record IDs need not match positions, e.g. after a log wraps.
+		entries = append(entries, idrac.SELEntry{ID: fmt.Sprint(pos + 100)})
+	}
+	return entries, nil
+}
+
+func TestSELRangePage(t *testing.T) {
+	fake := &fakeSELRanger{records: 5}
+	page, err := selRangePage(fake, 5, 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 5 || page.Offset != 2 || !page.HasMore || len(page.Items) != 2 || page.Items[0].ID != "103" {
+		t.Errorf("page = %+v, want positions 3-4 of 5 with more to follow", page)
+	}
+	if fake.starts[0] != 3 {
+		t.Errorf("getsel start = %d, want position 3", fake.starts[0])
+	}
+
+	if page, _ := selRangePage(fake, 5, 4, 2); page.HasMore || len(page.Items) != 1 {
+		t.Errorf("last page = %+v, want one entry and no more", page)
+	}
+	fake.starts = nil
+	if page, _ := selRangePage(fake, 5, 9, 2); page.Items == nil || len(fake.starts) != 0 {
+		t.Errorf("page past the end = %+v after %d reads, want empty items and no read", page, len(fake.starts))
+	}
+}
+
+func TestCapSEL(t *testing.T) {
+	var entries []idrac.SELEntry
+	for i := 1; i <= 10; i++ {
+		sev := "Ok"
+		if i%3 == 0 {
+			sev = "Critical"
+		}
+		entries = append(entries, idrac.SELEntry{ID: fmt.Sprint(i), Severity: sev})
+	}
+
+	got := capSEL(entries, nil, 4)
+	if !got.Truncated || got.Offset != 6 || got.Total != 10 || len(got.Items) != 4 || got.Items[0].ID != "7" {
+		t.Errorf("capSEL(all, 4) = %+v, want the newest 4 of 10, truncated", got)
+	}
+
+	got = capSEL(entries, nil, 0)
+	if got.Truncated || got.Offset != 0 || len(got.Items) != 10 {
+		t.Errorf("capSEL(all, 0) = %+v, want all entries", got)
+	}
+
+	// Filtering first keeps every critical entry despite the cap.
+	want, err := parseSeverityFilter("critical")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = capSEL(entries, want, 4)
+	if got.Truncated || got.Offset != 0 || got.Total != 3 || got.Items[0].ID != "3" {
+		t.Errorf("capSEL(critical, 4) = %+v, want entries 3, 6, 9", got)
+	}
+}
+
+func TestParseSeverityFilter(t *testing.T) {
+	got, err := parseSeverityFilter("Warning, critical")
+	if err != nil || !got["warning"] || !got["critical"] || got["normal"] {
+		t.Errorf("parseSeverityFilter = %v, %v", got, err)
+	}
+	if _, err := parseSeverityFilter("bogus"); err == nil {
+		t.Error("expected error for unknown severity")
+	}
+}
+
+func TestSELTail(t *testing.T) {
+	page := selTail([]idrac.SELEntry{{ID: "41"}, {ID: "42"}}, 42)
+	if page.Total != 42 || page.Offset != 40 || page.HasMore {
+		t.Errorf("page = %+v, want the last 2 of 42 at offset 40", page)
+	}
+	if page := selTail(nil, 42); page.Items == nil || page.Offset != 42 {
+		t.Errorf("empty tail = %+v, want [] at offset 42", page)
+	}
+}
+
+func TestGetSEL_Window(t *testing.T) {
+	var records []string
+	for i := 1; i <= 6; i++ {
+		records = append(records, fmt.Sprintf("%d|11/20/2009 15:49:2%d|2|event %d", i, i, i))
+	}
+	srv := idractest.NewServer(idractest.Options{SEL: records})
+	t.Cleanup(srv.Close)
+	router := NewRouter(&Config{
+		Hosts:         map[string]*HostConfig{"s1": {Host: srv.Addr(), Username: "root", Password: "calvin"}},
+		MaxSELEntries: 4,
+	})
+	get := func(query string) selPage {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/hosts/s1/sel"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d: %s", query, w.Code, w.Body)
+		}
+		var page selPage
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+		return page
+	}
+
+	if page := get(""); !page.Truncated || page.Total != 6 || page.Offset != 2 || page.Items[0].ID != "3" {
+		t.Errorf("capped read = %+v, want the newest 4 of 6, truncated", page)
+	}
+	if page := get("?offset=1&limit=2"); page.Truncated || page.Offset != 1 || !page.HasMore || len(page.Items) != 2 || page.Items[0].ID != "2" {
+		t.Errorf("window = %+v, want entries 2-3 with more to follow", page)
+	}
+	if page := get("?offset=0"); page.Limit != 4 || len(page.Items) != 4 || !page.HasMore {
+		t.Errorf("unbounded window = %+v, want the limit held to the cap", page)
+	}
+}
@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/internal/ipmi"
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// Transports probed by a self-test.
+const (
+	TransportWeb  = "web"
+	TransportIPMI = "ipmi"
+	TransportSSH  = "ssh"
+)
+
+// TransportCheck is the self-test outcome for one way of reaching an
+// iDRAC: whether it answered, whether the credentials were accepted, and
+// the result of a harmless sample read. Hint suggests a fix on failure.
+type TransportCheck struct {
+	Transport     string `json:"transport"`
+	OK            bool   `json:"ok"`
+	Reachable     bool   `json:"reachable"`
+	Authenticated bool   `json:"authenticated"`
+	Sample        string `json:"sample,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Hint          string `json:"hint,omitempty"`
+	DurationMS    int64  `json:"durationMs"`
+}
+
+// SelfTestReport is the result of probing every transport of a host.
+type SelfTestReport struct {
+	Host   string           `json:"host"`
+	OK     bool             `json:"ok"`
+	Checks []TransportCheck `json:"checks"`
+}
+
+// SelfTest probes the web API, IPMI, and RACADM over SSH of each host ID
+// concurrently, with its configured credentials, and reports what works.
+// It uses fresh connections, so it is safe to run before the server
+// starts (the --selftest mode).
+func SelfTest(ctx context.Context, cfg *Config, ids []string) map[string]*SelfTestReport {
+	reports := make(map[string]*SelfTestReport, len(ids))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		hc, ok := cfg.Hosts[id]
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report := selfTest(ctx, cfg, hc, hc.Username, hc.Password)
+			mu.Lock()
+			reports[id] = report
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return reports
+}
+
+// GetSelfTest probes every transport of a host and reports per-transport
+// reachability, authentication, and a sample read, with remediation hints
+// for what failed. It answers 200 whatever the outcome; "ok" says whether
+// all three work.
+func (h *Handlers) GetSelfTest(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	hc, ok := h.hostConfig(hostID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "host not found")
+		return
+	}
+	if h.config.Demo {
+		handleError(w, idrac.ErrDemo)
+		return
+	}
+	username, password := h.loginCredential(hostID, hc)
+	writeJSON(w, http.StatusOK, selfTest(r.Context(), h.config, hc, username, password))
+}
+
+// selfTest runs the three transport probes concurrently.
+func selfTest(ctx context.Context, cfg *Config, hc *HostConfig, username, password string) *SelfTestReport {
+	ctx, cancel := context.WithTimeout(ctx, 2*statusTimeout)
+	defer cancel()
+
+	probes := []func() TransportCheck{
+		func() TransportCheck { return probeWeb(ctx, cfg, hc, username, password) },
+		func() TransportCheck { return probeIPMI(ctx, cfg, hc, username, password) },
+		func() TransportCheck { return probeSSH(ctx, cfg, hc, username, password) },
+	}
+	report := &SelfTestReport{Host: hc.Host, OK: true, Checks: make([]TransportCheck, len(probes))}
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			check := probe()
+			check.DurationMS = time.Since(start).Milliseconds()
+			check.OK = check.Error == ""
+			report.Checks[i] = check
+		}()
+	}
+	wg.Wait()
+
+	for _, check := range report.Checks {
+		report.OK = report.OK && check.OK
+	}
+	return report
+}
+
+// probeWeb pings the web server, logs in, and reads the power state.
+func probeWeb(ctx context.Context, cfg *Config, hc *HostConfig, username, password string) TransportCheck {
+	check := TransportCheck{Transport: TransportWeb}
+	opts, err := cfg.clientOptions(hc)
+	if err != nil {
+		check.Error = err.Error()
+		check.Hint = "fix the host's TLS settings (ca_bundle, --tls-ca)"
+		return check
+	}
+	client := idrac.NewClient(hc.Host, username, password, opts...)
+
+	if _, err := client.Ping(ctx); err != nil {
+		check.Error = err.Error()
+		check.Hint = "check the address and that the iDRAC web server is enabled (iDRAC Settings > Network/Security > Services); GET /api/diagnostics/tls shows whether the TLS handshake works"
+		return check
+	}
+	check.Reachable = true
+
+	if err := client.LoginContext(ctx); err != nil {
+		check.Error = err.Error()
+		var ierr *idrac.Error
+		switch {
+		case errors.As(err, &ierr) && ierr.Code == idrac.CodeAuthFailed:
+			check.Hint = "check the username and password; the account needs the Login to iDRAC privilege"
+		case errors.As(err, &ierr) && ierr.Code == idrac.CodeSessionLimit:
+			check.Hint = "every web session is in use; close one under iDRAC Settings > Sessions or wait for them to time out"
+		default:
+			check.Hint = "the web server answered but login failed; an OEM build may need login_form or session_cookie settings"
+		}
+		return check
+	}
+	check.Authenticated = true
+	defer client.Logout() //nolint:errcheck
+
+	status, err := client.GetPowerState()
+	if err != nil {
+		check.Error = err.Error()
+		check.Hint = "logged in but could not read the power state; the firmware may need an update"
+		return check
+	}
+	check.Sample = "power " + status.State.String()
+	return check
+}
+
+// probeIPMI opens an IPMI-over-LAN session and reads the chassis power.
+// The goipmi errors carry no codes, so a failed RAKP exchange (the
+// session handshake) is taken to mean the BMC answered but refused the
+// credentials.
+func probeIPMI(ctx context.Context, cfg *Config, hc *HostConfig, username, password string) TransportCheck {
+	check := TransportCheck{Transport: TransportIPMI}
+	var opts []ipmi.Option
+	if cfg.SourceAddress != nil {
+		opts = append(opts, ipmi.WithSourceAddress(cfg.SourceAddress))
+	}
+	client := ipmi.NewClient(idrac.HostName(hc.Host), hc.IPMIPort, username, password, opts...)
+
+	on, err := client.GetPowerStatusContext(ctx)
+	if err != nil {
+		check.Error = err.Error()
+		var ierr *ipmi.Error
+		switch {
+		case strings.Contains(strings.ToLower(err.Error()), "rakp"):
+			check.Reachable = true
+			check.Hint = "check the username and password and that the user is enabled for IPMI LAN with Operator or Administrator privilege (iDRAC Settings > Users)"
+		case errors.As(err, &ierr) && ierr.Code == ipmi.CodeCommand:
+			check.Reachable, check.Authenticated = true, true
+			check.Hint = "the session opened but the command failed; the user's IPMI LAN privilege may be too low"
+		default:
+			check.Hint = "IPMI over LAN may be disabled: enable it under iDRAC Settings > Network/Security > Network > IPMI Settings and allow UDP 623"
+		}
+		return check
+	}
+	check.Reachable, check.Authenticated = true, true
+	check.Sample = "power off"
+	if on {
+		check.Sample = "power on"
+	}
+	return check
+}
+
+// probeSSH runs "racadm getsysinfo" over SSH.
+func probeSSH(ctx context.Context, cfg *Config, hc *HostConfig, username, password string) TransportCheck {
+	check := TransportCheck{Transport: TransportSSH}
+	admin := idrac.NewAdmin(idrac.HostName(hc.Host), hc.SSHPort, username, password, cfg.racadmOptions(hc)...).WithContext(ctx)
+
+	info, err := admin.GetSysInfo()
+	if err != nil {
+		check.Error = err.Error()
+		var rerr *racadmssh.Error
+		code := ""
+		if errors.As(err, &rerr) {
+			code = rerr.Code
+		}
+		switch code {
+		case racadmssh.CodeAuthFailed:
+			check.Reachable = true
+			check.Hint = "check the username and password; SSH uses the same iDRAC account as the web UI"
+		case racadmssh.CodeCommand:
+			check.Reachable, check.Authenticated = true, true
+			check.Hint = "logged in but racadm failed; the account may lack the Execute Diagnostic Commands privilege"
+		default:
+			check.Hint = fmt.Sprintf("enable SSH in iDRAC settings (iDRAC Settings > Network/Security > Services) and check ssh_port (using %d)", sshPortOrDefault(hc.SSHPort))
+		}
+		return check
+	}
+	check.Reachable, check.Authenticated = true, true
+	check.Sample = "firmware " + info.FirmwareVersion
+	return check
+}
+
+// sshPortOrDefault is the port RACADM connects to for a configured port.
+func sshPortOrDefault(port int) int {
+	if port == 0 {
+		return 22
+	}
+	return port
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+func TestGetSelfTest(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{
+			// Nothing listens on port 1 for SSH or IPMI.
+			"s1": {Host: addr, Username: "root", Password: "calvin", SSHPort: 1, IPMIPort: 1},
+		}},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+	r := chi.NewRouter()
+	r.Get("/hosts/{hostID}/selftest", h.GetSelfTest)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/s1/selftest", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+	var report SelfTestReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	if report.OK || len(report.Checks) != 3 {
+		t.Fatalf("report = %+v, want three checks, not all ok", report)
+	}
+	checks := make(map[string]TransportCheck)
+	for _, c := range report.Checks {
+		checks[c.Transport] = c
+	}
+	if web := checks[TransportWeb]; !web.OK || !web.Authenticated || web.Sample != "power on" {
+		t.Errorf("web check = %+v", web)
+	}
+	if ssh := checks[TransportSSH]; ssh.OK || ssh.Reachable || !strings.Contains(ssh.Hint, "enable SSH") {
+		t.Errorf("ssh check = %+v", ssh)
+	}
+	if ipmi := checks[TransportIPMI]; ipmi.OK || !strings.Contains(ipmi.Hint, "IPMI over LAN") {
+		t.Errorf("ipmi check = %+v", ipmi)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/nope/selftest", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("unknown host status = %d, want 404", w.Code)
+	}
+}
+
+func TestSelfTest_BadCredentials(t *testing.T) {
+	server := idractest.NewServer(idractest.Options{Username: "root", Password: "calvin", AuthResult: 1})
+	defer server.Close()
+
+	cfg := &Config{Hosts: map[string]*HostConfig{
+		"s1": {Host: server.Addr(), Username: "root", Password: "wrong", SSHPort: 1, IPMIPort: 1},
+	}}
+	web := SelfTest(context.Background(), cfg, []string{"s1", "missing"})["s1"].Checks[0]
+	if web.OK || !web.Reachable || web.Authenticated || !strings.Contains(web.Hint, "username and password") {
+		t.Errorf("web check = %+v, want reachable with an auth hint", web)
+	}
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"strings"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// renameSensors applies the global and per-host sensor rename maps to
+// readings for display. Every reading keeps its iDRAC name in RawName so
+// clients can match on it regardless of renames; unmapped sensors keep
+// their name.
+func (h *Handlers) renameSensors(hostID string, data *idrac.SensorData) {
+	names := make(map[string]string)
+	for raw, name := range h.config.SensorNames {
+		names[strings.ToLower(raw)] = name
+	}
+	if hc, ok := h.hostConfig(hostID); ok {
+		for raw, name := range hc.SensorNames {
+			names[strings.ToLower(raw)] = name
+		}
+	}
+
+	for _, group := range [][]idrac.SensorReading{data.Temperatures, data.Fans, data.Voltages} {
+		for i := range group {
+			group[i].RawName = group[i].Name
+			if name, ok := names[strings.ToLower(group[i].Name)]; ok {
+				group[i].Name = name
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestRenameSensors(t *testing.T) {
+	h := &Handlers{config: &Config{
+		SensorNames: map[string]string{"CPU1 Temp": "CPU 1", "System Board Ambient Temp": "Ambient"},
+		Hosts: map[string]*HostConfig{
+			"r710": {SensorNames: map[string]string{"system board ambient temp": "Inlet"}},
+		},
+	}}
+
+	data := &idrac.SensorData{
+		Temperatures: []idrac.SensorReading{{Name: "System Board Ambient Temp"}, {Name: "CPU1 Temp"}},
+		Fans:         []idrac.SensorReading{{Name: "FAN 1 RPM"}},
+	}
+	h.renameSensors("r710", data)
+
+	want := []struct{ name, raw string }{
+		{"Inlet", "System Board Ambient Temp"}, // per-host beats global, case-insensitive
+		{"CPU 1", "CPU1 Temp"},
+	}
+	for i, w := range want {
+		if got := data.Temperatures[i]; got.Name != w.name || got.RawName != w.raw {
+			t.Errorf("temperature %d = %q (raw %q), want %q (raw %q)", i, got.Name, got.RawName, w.name, w.raw)
+		}
+	}
+	if fan := data.Fans[0]; fan.Name != "FAN 1 RPM" || fan.RawName != "FAN 1 RPM" {
+		t.Errorf("unmapped fan = %q (raw %q), want name unchanged", fan.Name, fan.RawName)
+	}
+}
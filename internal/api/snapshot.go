@@ -0,0 +1,108 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// selSummary is the condensed SEL included in a host snapshot.
+type selSummary struct {
+	*idrac.SELSummary
+	Latest *idrac.SELEntry `json:"latest,omitempty"`
+}
+
+// hostSnapshot is everything the manager knows about a host at one moment.
+// Each section fails independently; a failed section carries its error.
+type hostSnapshot struct {
+	ID           string     `json:"id"`
+	Timestamp    time.Time  `json:"timestamp"`
+	Power        hostResult `json:"power"`
+	Sensors      hostResult `json:"sensors"`
+	Info         hostResult `json:"info"`
+	SEL          hostResult `json:"sel"`
+	VirtualMedia hostResult `json:"virtualMedia"`
+	AssetTag     hostResult `json:"assetTag"`
+}
+
+// GetSnapshot gathers power, sensors, system info, a SEL summary, virtual
+// media status, and the asset tag concurrently. Partial failures are reported per
+// section rather than failing the whole request.
+func (h *Handlers) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+	hostID := chi.URLParam(r, "hostID")
+	snap := &hostSnapshot{ID: hostID, Timestamp: time.Now().UTC()}
+
+	client, clientErr := h.getClient(r.Context(), hostID)
+	webAPI := func(fn func(idrac.HostClient) (interface{}, error)) func() (interface{}, error) {
+		return func() (interface{}, error) {
+			if clientErr != nil {
+				return nil, clientErr
+			}
+			return fn(client)
+		}
+	}
+
+	sections := []struct {
+		dst *hostResult
+		fn  func() (interface{}, error)
+	}{
+		{&snap.Power, webAPI(func(c idrac.HostClient) (interface{}, error) { return c.GetPowerState() })},
+		{&snap.Sensors, webAPI(func(c idrac.HostClient) (interface{}, error) {
+			sensors, err := c.GetSensors()
+			if err != nil {
+				return nil, err
+			}
+			h.renameSensors(hostID, sensors)
+			return sensors, nil
+		})},
+		{&snap.Info, webAPI(func(c idrac.HostClient) (interface{}, error) { return c.GetSystemInfo() })},
+		{&snap.SEL, webAPI(func(c idrac.HostClient) (interface{}, error) {
+			sel, err := c.GetSEL()
+			if err != nil {
+				return nil, err
+			}
+			summary := selSummary{SELSummary: sel.Summarize()}
+			if n := len(sel.Entries); n > 0 {
+				summary.Latest = &sel.Entries[n-1]
+			}
+			return summary, nil
+		})},
+		{&snap.VirtualMedia, func() (interface{}, error) {
+			if license, _ := h.getLicense(hostID); license == idrac.LicenseExpress {
+				return nil, idrac.ErrRequiresEnterprise
+			}
+			vm, err := h.getVMedia(hostID)
+			if err != nil {
+				return nil, err
+			}
+			return vm.GetStatusContext(r.Context())
+		}},
+		{&snap.AssetTag, func() (interface{}, error) {
+			admin, err := h.requestAdmin(r, hostID)
+			if err != nil {
+				return nil, err
+			}
+			return admin.GetAssetTag()
+		}},
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range sections {
+		wg.Add(1)
+		go func(dst *hostResult, fn func() (interface{}, error)) {
+			defer wg.Done()
+			data, err := fn()
+			if err != nil {
+				dst.Error = err.Error()
+				return
+			}
+			dst.Data = data
+		}(s.dst, s.fn)
+	}
+	wg.Wait()
+
+	writeJSON(w, http.StatusOK, snap)
+}
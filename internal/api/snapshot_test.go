@@ -0,0 +1,64 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestGetSnapshot_PartialFailure(t *testing.T) {
+	addr := mockIDRAC(t).Addr()
+
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{
+			// Nothing listens on port 1, so RACADM sections fail fast.
+			"s1": {Host: addr, Username: "root", Password: "calvin", SSHPort: 1},
+		}},
+		pool:  idrac.NewPool(),
+		stats: newManagerStats(),
+	}
+	// An Express host fails the virtual media section without any SSH.
+	h.licenses.Store("s1", idrac.LicenseExpress)
+
+	r := chi.NewRouter()
+	r.Get("/hosts/{hostID}/snapshot", h.GetSnapshot)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/hosts/s1/snapshot", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var snap struct {
+		ID        string `json:"id"`
+		Timestamp string `json:"timestamp"`
+		Power     struct {
+			Data struct {
+				Status string `json:"status"`
+			} `json:"data"`
+			Error string `json:"error"`
+		} `json:"power"`
+		VirtualMedia hostResult `json:"virtualMedia"`
+		AssetTag     hostResult `json:"assetTag"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&snap); err != nil {
+		t.Fatalf("decoding snapshot: %v", err)
+	}
+
+	if snap.ID != "s1" || snap.Timestamp == "" {
+		t.Errorf("id/timestamp = %q/%q, want s1 and a timestamp", snap.ID, snap.Timestamp)
+	}
+	if snap.Power.Error != "" || snap.Power.Data.Status == "" {
+		t.Errorf("power = %+v, want data without error", snap.Power)
+	}
+	if snap.VirtualMedia.Error != idrac.ErrRequiresEnterprise.Error() {
+		t.Errorf("virtualMedia error = %q, want %q", snap.VirtualMedia.Error, idrac.ErrRequiresEnterprise)
+	}
+	if snap.AssetTag.Error == "" {
+		t.Errorf("assetTag = %+v, want an SSH error", snap.AssetTag)
+	}
+}
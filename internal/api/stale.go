@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+// Response kinds kept as last-known-good.
+const (
+	staleSensors = "sensors"
+	stalePower   = "power"
+	staleSysInfo = "sysinfo"
+)
+
+// staleRoutes maps the route patterns of host endpoints that can be
+// answered from the last-known-good cache to their kind.
+var staleRoutes = map[string]string{
+	"/api/hosts/{hostID}/sensors": staleSensors,
+	"/api/hosts/{hostID}/power":   stalePower,
+	"/api/hosts/{hostID}/info":    staleSysInfo,
+}
+
+// staleRoute returns the last-known-good kind of a GET under a host's
+// routes, if it has one. It runs in the host middleware, before chi has
+// matched the final route, so it looks the full pattern up in the router:
+// a sibling like /ipmi/power must not match /power.
+func staleRoute(r *http.Request) (string, bool) {
+	rctx := chi.RouteContext(r.Context())
+	if r.Method != http.MethodGet || rctx == nil || rctx.Routes == nil {
+		return "", false
+	}
+	kind, ok := staleRoutes[rctx.Routes.Find(chi.NewRouteContext(), r.Method, r.URL.Path)]
+	return kind, ok
+}
+
+// sensorsUnavailable returns an error when no sensor type could be read,
+// so a fresh read that found nothing is treated as a failed one.
+func sensorsUnavailable(data *idrac.SensorData) error {
+	if len(data.Errors) == 0 || len(data.Temperatures)+len(data.Fans)+len(data.Voltages) > 0 {
+		return nil
+	}
+	msgs := make([]string, 0, len(data.Errors))
+	for _, msg := range data.Errors {
+		msgs = append(msgs, msg)
+	}
+	sort.Strings(msgs)
+	return &apiError{Status: http.StatusBadGateway, Code: idrac.CodeUpstream, Message: strings.Join(msgs, "; ")}
+}
+
+// staleWarning marks a stale response (RFC 7234 warn-code 110).
+const staleWarning = `110 - "Response is Stale"`
+
+// lastGoodKey identifies one kind of response for one host.
+type lastGoodKey struct {
+	host, kind string
+}
+
+// lastGood is a host's most recent successful response of one kind.
+type lastGood struct {
+	data interface{}
+	at   time.Time
+}
+
+// remember records a successful response for serveStale. It is a no-op
+// unless Config.StaleWindow is set.
+func (h *Handlers) remember(hostID, kind string, data interface{}) {
+	if h.config.StaleWindow <= 0 {
+		return
+	}
+	h.lastGood.Store(lastGoodKey{hostID, kind}, lastGood{data: data, at: time.Now()})
+}
+
+// forgetLastGood drops a host's remembered responses.
+func (h *Handlers) forgetLastGood(hostID string) {
+	for _, kind := range []string{staleSensors, stalePower, staleSysInfo} {
+		h.lastGood.Delete(lastGoodKey{hostID, kind})
+	}
+}
+
+// serveStale answers a failed fetch with the last successful response of
+// the same kind, if it is younger than Config.StaleWindow and the failure
+// looks transient (a 5xx: unreachable, timed out, breaker open). The body
+// is the usual one plus "stale": true, "fetchedAt", and "staleReason",
+// with Age and Warning headers. It reports whether it wrote a response;
+// if not, the caller should report err as usual.
+func (h *Handlers) serveStale(w http.ResponseWriter, hostID, kind string, err error) bool {
+	if h.config.StaleWindow <= 0 || toAPIError(err).Status < http.StatusInternalServerError {
+		return false
+	}
+	v, ok := h.lastGood.Load(lastGoodKey{hostID, kind})
+	if !ok {
+		return false
+	}
+	lg := v.(lastGood)
+	if time.Since(lg.at) > h.config.StaleWindow {
+		return false
+	}
+
+	raw, mErr := json.Marshal(lg.data)
+	var body map[string]interface{}
+	if mErr != nil || json.Unmarshal(raw, &body) != nil {
+		return false
+	}
+	body["stale"] = true
+	body["fetchedAt"] = lg.at.UTC().Format(time.RFC3339)
+	body["staleReason"] = err.Error()
+
+	log.Printf("serving stale %s for %s from %s: %v", kind, hostID, lg.at.Format(time.RFC3339), err)
+	setAge(w, lg.at)
+	w.Header().Set("Warning", staleWarning)
+	writeJSON(w, http.StatusOK, body)
+	return true
+}
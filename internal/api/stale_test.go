@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestStaleSensors(t *testing.T) {
+	server := mockIDRAC(t)
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"s1": {Host: server.Addr(), Username: "root", Password: "calvin"},
+		},
+		StaleWindow:      time.Minute,
+		BreakerThreshold: 2,
+		BreakerCooldown:  time.Minute,
+	}
+	router := NewRouter(cfg)
+
+	getPath := func(path string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/hosts/s1"+path, nil))
+		var body map[string]interface{}
+		json.NewDecoder(w.Body).Decode(&body)
+		return w, body
+	}
+	get := func() (*httptest.ResponseRecorder, map[string]interface{}) {
+		t.Helper()
+		return getPath("/sensors")
+	}
+
+	if w, body := get(); w.Code != http.StatusOK || body["stale"] != nil {
+		t.Fatalf("fresh read: status %d, body %v", w.Code, body)
+	}
+	if w, _ := getPath("/power"); w.Code != http.StatusOK {
+		t.Fatalf("fresh power read: status %d", w.Code)
+	}
+
+	server.Close()
+	// Two stale answers open the breaker; the third is answered while it
+	// is open.
+	for i := 0; i < 3; i++ {
+		w, body := get()
+		if w.Code != http.StatusOK || body["stale"] != true || body["fetchedAt"] == nil || body["temperatures"] == nil {
+			t.Fatalf("read %d while down: status %d, body %v", i, w.Code, body)
+		}
+		if w.Header().Get("Warning") != staleWarning || w.Header().Get("Age") == "" {
+			t.Errorf("read %d: headers %v", i, w.Header())
+		}
+	}
+
+	// A system info read was never remembered, so it fails as usual.
+	if w, _ := getPath("/info"); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("info with breaker open: status %d, want 503", w.Code)
+	}
+	// The remembered power read answers /power but not /ipmi/power, which
+	// shares its last path segment.
+	if w, body := getPath("/power"); w.Code != http.StatusOK || body["stale"] != true {
+		t.Errorf("power with breaker open: status %d, body %v", w.Code, body)
+	}
+	if w, body := getPath("/ipmi/power"); w.Code != http.StatusServiceUnavailable || body["stale"] != nil {
+		t.Errorf("IPMI power with breaker open: status %d, body %v, want 503", w.Code, body)
+	}
+}
+
+func TestServeStale(t *testing.T) {
+	h := &Handlers{config: &Config{StaleWindow: time.Minute}}
+	h.lastGood.Store(lastGoodKey{"s1", stalePower}, lastGood{data: &powerReading{}, at: time.Now()})
+	h.lastGood.Store(lastGoodKey{"s2", stalePower}, lastGood{data: &powerReading{}, at: time.Now().Add(-2 * time.Minute)})
+
+	unreachable := &apiError{Status: http.StatusBadGateway, Message: "dial tcp: connection refused"}
+	if !h.serveStale(httptest.NewRecorder(), "s1", stalePower, unreachable) {
+		t.Error("recent reading should be served for an unreachable host")
+	}
+	if h.serveStale(httptest.NewRecorder(), "s1", stalePower, &apiError{Status: http.StatusUnauthorized, Message: "login failed"}) {
+		t.Error("a rejected login should not be masked by a stale reading")
+	}
+	if h.serveStale(httptest.NewRecorder(), "s2", stalePower, unreachable) {
+		t.Error("a reading older than the window should not be served")
+	}
+	h.forgetLastGood("s1")
+	if h.serveStale(httptest.NewRecorder(), "s1", stalePower, unreachable) {
+		t.Error("forgotten reading should not be served")
+	}
+}
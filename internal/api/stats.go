@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// hostLatency accumulates request latency for a single host.
+type hostLatency struct {
+	requests int64
+	errors   int64
+	total    time.Duration
+	max      time.Duration
+}
+
+// managerStats tracks operational metrics for the manager itself.
+type managerStats struct {
+	started time.Time
+
+	mu    sync.Mutex
+	hosts map[string]*hostLatency
+}
+
+func newManagerStats() *managerStats {
+	return &managerStats{
+		started: time.Now(),
+		hosts:   make(map[string]*hostLatency),
+	}
+}
+
+// record adds a single request observation for a host.
+func (s *managerStats) record(hostID string, d time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hl, ok := s.hosts[hostID]
+	if !ok {
+		hl = &hostLatency{}
+		s.hosts[hostID] = hl
+	}
+	hl.requests++
+	if failed {
+		hl.errors++
+	}
+	hl.total += d
+	if d > hl.max {
+		hl.max = d
+	}
+}
+
+// latencies returns a copy of the per-host latency counters.
+func (s *managerStats) latencies() map[string]hostLatency {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make(map[string]hostLatency, len(s.hosts))
+	for id, hl := range s.hosts {
+		out[id] = *hl
+	}
+	return out
+}
+
+// hostLatencyStats is the JSON view of hostLatency.
+type hostLatencyStats struct {
+	ID           string  `json:"id"`
+	Requests     int64   `json:"requests"`
+	Errors       int64   `json:"errors"`
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+	MaxLatencyMs float64 `json:"maxLatencyMs"`
+}
+
+// snapshot returns per-host latency stats sorted by host ID.
+func (s *managerStats) snapshot() []hostLatencyStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	out := make([]hostLatencyStats, 0, len(s.hosts))
+	for id, hl := range s.hosts {
+		st := hostLatencyStats{
+			ID:           id,
+			Requests:     hl.requests,
+			Errors:       hl.errors,
+			MaxLatencyMs: float64(hl.max) / float64(time.Millisecond),
+		}
+		if hl.requests > 0 {
+			st.AvgLatencyMs = float64(hl.total) / float64(hl.requests) / float64(time.Millisecond)
+		}
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// statusRecorder captures the response status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, e.g.
+// to flush an event stream.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// trackLatency records per-host request latency for routes under /hosts/{hostID}.
+func (h *Handlers) trackLatency(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rec, r)
+		h.stats.record(chi.URLParam(r, "hostID"), time.Since(start), rec.status >= http.StatusInternalServerError)
+	})
+}
+
+// Stats returns operational statistics about the manager.
+func (h *Handlers) Stats(w http.ResponseWriter, _ *http.Request) {
+	totals := h.pool.Stats()
+
+	resp := map[string]interface{}{
+		"uptimeSeconds": int64(time.Since(h.stats.started).Seconds()),
+		"cachedClients": h.pool.Len(),
+		"logins":        totals.Logins,
+		"loginFailures": totals.LoginFailures,
+		"retries":       totals.Retries,
+		"busyRetries":   totals.BusyRetries,
+		"hosts":         h.stats.snapshot(),
+	}
+	if h.refresher != nil {
+		resp["refresh"] = h.refresher.schedule()
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
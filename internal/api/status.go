@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+const (
+	// defaultSlowThreshold is used when Config.SlowThreshold is unset.
+	defaultSlowThreshold = 2 * time.Second
+	// statusTimeout is how long a host may take before it counts as down.
+	statusTimeout = 10 * time.Second
+)
+
+// checkHealth pings a host's iDRAC without logging in.
+func (h *Handlers) checkHealth(ctx context.Context, hostID string) idrac.Health {
+	hc, ok := h.hostConfig(hostID)
+	if !ok {
+		return idrac.Health{State: idrac.HealthDown, Error: "host not found"}
+	}
+	if h.config.Demo {
+		return idrac.Health{State: idrac.HealthUp}
+	}
+	opts, err := h.config.clientOptions(hc)
+	if err != nil {
+		return idrac.Health{State: idrac.HealthDown, Error: err.Error()}
+	}
+
+	slowAfter := h.config.SlowThreshold
+	if slowAfter <= 0 {
+		slowAfter = defaultSlowThreshold
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, statusTimeout)
+	defer cancel()
+	return idrac.NewClient(hc.Host, hc.Username, hc.Password, opts...).CheckHealth(ctx, slowAfter)
+}
+
+// hostStatus is a host's reachability plus its circuit breaker state and
+// latest chassis intrusion reading.
+type hostStatus struct {
+	idrac.Health
+	Breaker   string            `json:"breaker,omitempty"`
+	Intrusion *intrusionReading `json:"intrusion,omitempty"`
+}
+
+// Status reports each host's reachability as up, slow, or down with the
+// measured latency, so degrading controllers stand out before they fail.
+// The check pings even hosts whose breaker is open. Intrusion state is
+// the last one read from the host's intrusion endpoint, not re-read here,
+// since that takes a RACADM session. Disabled hosts are left out. Up to
+// ?concurrency= hosts are checked at once.
+func (h *Handlers) Status(w http.ResponseWriter, r *http.Request) {
+	concurrency, err := h.bulkConcurrency(r)
+	if err != nil {
+		handleError(w, err)
+		return
+	}
+	results := forEachHost(r.Context(), h.activeHostIDs(), concurrency, func(ctx context.Context, hostID string) (interface{}, error) {
+		return h.checkHealth(ctx, hostID), nil
+	})
+
+	now := time.Now()
+	hosts := make(map[string]hostStatus, len(results))
+	counts := map[idrac.HealthState]int{idrac.HealthUp: 0, idrac.HealthSlow: 0, idrac.HealthDown: 0}
+	for id, res := range results {
+		health, ok := res.Data.(idrac.Health)
+		if !ok {
+			health = idrac.Health{State: idrac.HealthDown, Error: res.Error}
+		}
+		status := hostStatus{Health: health, Intrusion: h.cachedIntrusion(id)}
+		if b := h.getBreaker(id); b != nil {
+			status.Breaker = b.state(now)
+		}
+		hosts[id] = status
+		counts[health.State]++
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"hosts":  hosts,
+		"counts": counts,
+	})
+}
+
+// DiagnoseTLS attempts a TLS handshake and reports the negotiated version,
+// cipher suite, and certificate, without logging in. The target is either
+// a configured host (hostId) or an address (host, with optional port), so
+// it can be run for an iDRAC before it is added.
+func (h *Handlers) DiagnoseTLS(w http.ResponseWriter, r *http.Request) {
+	if h.config.Demo {
+		handleError(w, idrac.ErrDemo)
+		return
+	}
+	q := r.URL.Query()
+	addr := q.Get("host")
+	if id := q.Get("hostId"); id != "" {
+		hc, ok := h.hostConfig(id)
+		if !ok {
+			writeError(w, http.StatusNotFound, "host not found")
+			return
+		}
+		addr = hc.Host
+	}
+	if addr == "" {
+		writeError(w, http.StatusBadRequest, "host or hostId is required")
+		return
+	}
+	if port := q.Get("port"); port != "" {
+		addr = net.JoinHostPort(idrac.HostName(addr), port)
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 2*statusTimeout)
+	defer cancel()
+	writeJSON(w, http.StatusOK, idrac.DiagnoseTLS(ctx, addr))
+}
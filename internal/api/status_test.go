@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestStatus(t *testing.T) {
+	up := mockIDRAC(t)
+
+	// A listener closed immediately gives an address that refuses connections.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	downAddr := ln.Addr().String()
+	ln.Close()
+
+	cfg := &Config{Hosts: map[string]*HostConfig{
+		"up":    {Host: up.Addr(), Username: "root", Password: "calvin"},
+		"down":  {Host: downAddr, Username: "root", Password: "calvin"},
+		"maint": {Host: downAddr, Username: "root", Password: "calvin", Disabled: true},
+	}}
+	router := NewRouter(cfg)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	var body struct {
+		Hosts  map[string]idrac.Health   `json:"hosts"`
+		Counts map[idrac.HealthState]int `json:"counts"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if got := body.Hosts["up"]; got.State != idrac.HealthUp || got.LatencyMs <= 0 {
+		t.Errorf("up host = %+v, want up with latency", got)
+	}
+	if got := body.Hosts["down"]; got.State != idrac.HealthDown || got.Error == "" {
+		t.Errorf("down host = %+v, want down with an error", got)
+	}
+	if body.Counts[idrac.HealthUp] != 1 || body.Counts[idrac.HealthDown] != 1 || body.Counts[idrac.HealthSlow] != 0 {
+		t.Errorf("counts = %v, want 1 up, 1 down", body.Counts)
+	}
+	if _, ok := body.Hosts["maint"]; ok {
+		t.Error("disabled host should be left out of status")
+	}
+}
+
+func TestDiagnoseTLSHandler(t *testing.T) {
+	server := mockIDRAC(t)
+	addr := server.Addr()
+	host, port, _ := net.SplitHostPort(addr)
+
+	cfg := &Config{Hosts: map[string]*HostConfig{"s1": {Host: addr}}}
+	router := NewRouter(cfg)
+
+	for _, query := range []string{"?hostId=s1", "?host=" + host + "&port=" + port} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/diagnostics/tls"+query, nil))
+		var d idrac.TLSDiagnosis
+		if err := json.Unmarshal(w.Body.Bytes(), &d); err != nil {
+			t.Fatalf("%s: invalid JSON %q", query, w.Body.String())
+		}
+		if w.Code != http.StatusOK || !d.OfferedCiphersOK || d.Address != addr {
+			t.Errorf("%s: %d %+v", query, w.Code, d)
+		}
+	}
+
+	for query, want := range map[string]int{"": http.StatusBadRequest, "?hostId=nope": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/diagnostics/tls"+query, nil))
+		if w.Code != want {
+			t.Errorf("%q: status = %d, want %d", query, w.Code, want)
+		}
+	}
+}
+
+func TestStatus_Intrusion(t *testing.T) {
+	h := &Handlers{
+		config: &Config{Hosts: map[string]*HostConfig{
+			"s1": {Host: mockIDRAC(t).Addr(), Username: "root", Password: "calvin"},
+		}},
+		pool:   idrac.NewPool(),
+		stats:  newManagerStats(),
+		events: newEventHub(),
+	}
+	events, unsubscribe := h.events.subscribe()
+	defer unsubscribe()
+
+	h.recordIntrusion("s1", &idrac.Intrusion{State: idrac.IntrusionClosed, Sensor: "System Board Intrusion"})
+	h.recordIntrusion("s1", &idrac.Intrusion{State: idrac.IntrusionOpen, Sensor: "System Board Intrusion"})
+	h.recordIntrusion("s1", &idrac.Intrusion{State: idrac.IntrusionOpen, Sensor: "System Board Intrusion"})
+	if len(events) != 1 {
+		t.Fatalf("events = %d, want one for the closed-to-open change", len(events))
+	}
+	if ev := <-events; ev.Type != eventIntrusionDetected || ev.State != "open" || ev.Value != nil {
+		t.Errorf("event = %+v", ev)
+	}
+
+	w := httptest.NewRecorder()
+	h.Status(w, httptest.NewRequest("GET", "/api/status", nil))
+	var body struct {
+		Hosts map[string]hostStatus `json:"hosts"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if in := body.Hosts["s1"].Intrusion; in == nil || in.State != idrac.IntrusionOpen || in.CheckedAt.IsZero() {
+		t.Errorf("status intrusion = %+v", in)
+	}
+}
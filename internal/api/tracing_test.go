@@ -0,0 +1,101 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_ServerSpan(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	recorder := tracetest.NewSpanRecorder()
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"server1": {Name: "Server 1", Host: "10.0.0.1", Username: "root", Password: "pass"},
+		},
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+	}
+	router := NewRouter(cfg)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest("DELETE", "/api/hosts/server1/sessions/abc", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "DELETE /api/hosts/{hostID}/sessions/{sessionID}" {
+		t.Errorf("span name = %q, want route pattern", span.Name())
+	}
+	if got := span.SpanContext().TraceID().String(); got != traceID {
+		t.Errorf("trace ID = %s, want %s propagated from traceparent", got, traceID)
+	}
+
+	attrs := map[string]string{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["idrac.host_id"] != "server1" {
+		t.Errorf("idrac.host_id = %q, want server1", attrs["idrac.host_id"])
+	}
+	if attrs["http.response.status_code"] != "400" {
+		t.Errorf("status attribute = %q, want 400", attrs["http.response.status_code"])
+	}
+}
+
+func TestTracing_NoProviderIsNoop(t *testing.T) {
+	router := NewRouter(&Config{Hosts: map[string]*HostConfig{}})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/health", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestTracing_IDRACSpansNestUnderRequest(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	cfg := &Config{
+		Hosts: map[string]*HostConfig{
+			"s1": {Host: mockIDRAC(t).Addr(), Username: "root", Password: "calvin"},
+		},
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+	}
+	router := NewRouter(cfg)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/hosts/s1/power", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	var server sdktrace.ReadOnlySpan
+	var children []sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.SpanKind() == trace.SpanKindServer {
+			server = span
+		} else {
+			children = append(children, span)
+		}
+	}
+	if server == nil || len(children) == 0 {
+		t.Fatalf("got server span %v and %d iDRAC spans, want both", server, len(children))
+	}
+	for _, span := range children {
+		if span.Parent().SpanID() != server.SpanContext().SpanID() {
+			t.Errorf("span %q is not a child of the request span", span.Name())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// webhookTimeout bounds each webhook delivery, so a hung receiver cannot
+// hold up the events behind it for long.
+const webhookTimeout = 10 * time.Second
+
+// ValidateWebhookURL checks that u is an absolute http or https URL.
+func ValidateWebhookURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return fmt.Errorf("webhook %q: %w", u, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("webhook %q: must be an http or https URL", u)
+	}
+	return nil
+}
+
+// webhookHost returns the host of a webhook URL for log lines; the path
+// and query of a chat webhook are often its secret.
+func webhookHost(u string) string {
+	if parsed, err := url.Parse(u); err == nil {
+		return parsed.Host
+	}
+	return "webhook"
+}
+
+// startWebhooks subscribes to the event hub and, until stop is closed,
+// POSTs each event to every URL as the same JSON the event stream sends.
+// Deliveries run in order in one goroutine; one that fails or is not
+// answered with a 2xx is logged and not retried. Like a slow stream
+// subscriber, a receiver that falls eventBuffer events behind misses
+// further ones.
+func (h *Handlers) startWebhooks(urls []string, stop <-chan struct{}) {
+	events, unsubscribe := h.events.subscribe()
+	client := &http.Client{Timeout: webhookTimeout}
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case ev := <-events:
+				body, err := json.Marshal(ev)
+				if err != nil {
+					continue
+				}
+				for _, u := range urls {
+					if err := postWebhook(client, u, ev.Type, body); err != nil {
+						log.Printf("webhook %s: delivering %s event %d: %v", webhookHost(u), ev.Type, ev.ID, err)
+					}
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// postWebhook sends one event body to a webhook URL.
+func postWebhook(client *http.Client, u, eventType string, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-Type", eventType)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return nil
+}
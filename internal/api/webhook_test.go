@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+)
+
+func TestStartWebhooks(t *testing.T) {
+	type delivery struct {
+		eventType string
+		ev        sensorEvent
+	}
+	received := make(chan delivery, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var ev sensorEvent
+		if err := json.Unmarshal(body, &ev); err != nil {
+			t.Errorf("webhook body %q: %v", body, err)
+		}
+		received <- delivery{r.Header.Get("X-Event-Type"), ev}
+	}))
+	defer srv.Close()
+
+	h := &Handlers{config: &Config{}, events: newEventHub()}
+	stop := make(chan struct{})
+	defer close(stop)
+	h.startWebhooks([]string{srv.URL + "/hook"}, stop)
+
+	h.checkThresholds("s1", tempReading(48))
+
+	select {
+	case d := <-received:
+		if d.eventType != eventThresholdBreach || d.ev.Type != eventThresholdBreach || d.ev.Host != "s1" ||
+			d.ev.Sensor != "Inlet" || d.ev.Severity != idrac.SeverityCritical || d.ev.Threshold != 47 {
+			t.Errorf("delivery = %+v", d)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook not called")
+	}
+}
+
+func TestValidateWebhookURL(t *testing.T) {
+	for u, ok := range map[string]bool{
+		"https://hooks.example.com/T000/B000": true,
+		"http://10.0.0.5:9000/events":         true,
+		"ftp://example.com/hook":              false,
+		"hooks.example.com/hook":              false,
+		"https://":                            false,
+	} {
+		if err := ValidateWebhookURL(u); (err == nil) != ok {
+			t.Errorf("ValidateWebhookURL(%q) = %v, want ok %v", u, err, ok)
+		}
+	}
+}
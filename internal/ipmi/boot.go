@@ -0,0 +1,114 @@
+package ipmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	goipmi "github.com/bougou/go-ipmi"
+)
+
+// BootDevices maps boot override names to IPMI boot device selectors.
+var BootDevices = map[string]goipmi.BootDeviceSelector{
+	"none":   goipmi.BootDeviceSelectorNoOverride,
+	"pxe":    goipmi.BootDeviceSelectorForcePXE,
+	"disk":   goipmi.BootDeviceSelectorForceHardDrive,
+	"safe":   goipmi.BootDeviceSelectorForceHardDriveSafe,
+	"diag":   goipmi.BootDeviceSelectorForceDiagnosticPartition,
+	"cdrom":  goipmi.BootDeviceSelectorForceCDROM,
+	"bios":   goipmi.BootDeviceSelectorForceBIOSSetup,
+	"floppy": goipmi.BootDeviceSelectorForceFloppy,
+	"remote": goipmi.BootDeviceSelectorForceRemoteMedia,
+}
+
+// BootOverride describes the BMC's boot device override.
+type BootOverride struct {
+	Device     string `json:"device"`
+	Valid      bool   `json:"valid"`
+	Persistent bool   `json:"persistent"`
+}
+
+// bootDeviceName returns the override name for a boot device selector.
+func bootDeviceName(sel goipmi.BootDeviceSelector) string {
+	for name, s := range BootDevices {
+		if s == sel {
+			return name
+		}
+	}
+	return sel.String()
+}
+
+// bootDeviceNames returns the sorted list of valid boot device names.
+func bootDeviceNames() string {
+	names := make([]string, 0, len(BootDevices))
+	for name := range BootDevices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// GetBootOverride returns the current boot device override and whether it
+// is persistent.
+func (c *Client) GetBootOverride() (override *BootOverride, err error) {
+	err = c.WithConnection(func(cl *Conn) error {
+		override, err = cl.GetBootOverride()
+		return err
+	})
+	return override, err
+}
+
+// GetBootOverride returns the current boot device override.
+func (cl *Conn) GetBootOverride() (*BootOverride, error) {
+	flags := &goipmi.BootOptionParam_BootFlags{}
+	if err := cl.client.GetSystemBootOptionsParamFor(cl.ctx, flags); err != nil {
+		return nil, fmt.Errorf("IPMI get boot flags: %w", err)
+	}
+
+	override := &BootOverride{
+		Device:     bootDeviceName(flags.BootDeviceSelector),
+		Valid:      flags.BootFlagsValid,
+		Persistent: flags.Persist,
+	}
+	if !override.Valid {
+		override.Device = "none"
+	}
+	return override, nil
+}
+
+// SetBootOnce sets a one-time (non-persistent) boot device override and
+// verifies the BMC accepted it. iDRAC clears the override after the next
+// boot, so the server reverts to its normal boot order afterwards.
+func (c *Client) SetBootOnce(device string) (override *BootOverride, err error) {
+	if _, ok := BootDevices[device]; !ok {
+		return nil, invalid("unknown boot device: %q (valid: %s)", device, bootDeviceNames())
+	}
+	err = c.WithConnection(func(cl *Conn) error {
+		override, err = cl.SetBootOnce(device)
+		return err
+	})
+	return override, err
+}
+
+// SetBootOnce sets a one-time boot device override and verifies it; see
+// Client.SetBootOnce.
+func (cl *Conn) SetBootOnce(device string) (*BootOverride, error) {
+	sel, ok := BootDevices[device]
+	if !ok {
+		return nil, invalid("unknown boot device: %q (valid: %s)", device, bootDeviceNames())
+	}
+
+	cl.mutated = true
+	if err := cl.client.SetBootDevice(cl.ctx, sel, goipmi.BIOSBootTypeLegacy, false); err != nil {
+		return nil, fmt.Errorf("IPMI set boot device: %w", err)
+	}
+
+	override, err := cl.GetBootOverride()
+	if err != nil {
+		return nil, fmt.Errorf("verifying boot override: %w", err)
+	}
+	if device != "none" && (override.Device != device || override.Persistent) {
+		return override, fmt.Errorf("boot override not applied: got device=%s persistent=%v", override.Device, override.Persistent)
+	}
+	return override, nil
+}
@@ -4,6 +4,10 @@ package ipmi
 import (
 	"context"
 	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	goipmi "github.com/bougou/go-ipmi"
@@ -15,59 +19,221 @@ type Client struct {
 	port     int
 	username string
 	password string
+
+	// dial opens a new session; it is c.connect unless replaced in tests.
+	dial func(ctx context.Context) (*goipmi.Client, error)
+
+	// sourceAddr, if set, is the local address IPMI packets are sent from.
+	sourceAddr net.IP
+
+	persistent bool
+	mu         sync.Mutex
+	conn       *goipmi.Client // idle open session when persistent; guarded by mu
+}
+
+// Option configures optional Client behavior.
+type Option func(*Client)
+
+// WithPersistentSession keeps one IPMI session open across calls instead
+// of connecting per call. A call checks the session out for its exchange,
+// so a concurrent call opens a session of its own rather than waiting; the
+// spare is closed when it is done. A failed call drops the session so the
+// next one reconnects; idempotent reads are retried once on a fresh session.
+func WithPersistentSession() Option {
+	return func(c *Client) {
+		c.persistent = true
+	}
+}
+
+// WithSourceAddress sends IPMI traffic from ip. A nil ip leaves the choice
+// to the OS.
+func WithSourceAddress(ip net.IP) Option {
+	return func(c *Client) {
+		c.sourceAddr = ip
+	}
 }
 
 // NewClient creates a new IPMI client.
-func NewClient(host string, port int, username, password string) *Client {
+func NewClient(host string, port int, username, password string, opts ...Option) *Client {
 	if port == 0 {
 		port = 623
 	}
-	return &Client{
+	c := &Client{
 		host:     host,
 		port:     port,
 		username: username,
 		password: password,
 	}
+	c.dial = c.connect
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
 }
 
-func (c *Client) ctx() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 10*time.Second)
+// ctx bounds one IPMI exchange made on behalf of parent.
+func (c *Client) ctx(parent context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(parent, 10*time.Second)
 }
 
 // connect creates an authenticated IPMI connection.
-func (c *Client) connect() (*goipmi.Client, error) {
+func (c *Client) connect(parent context.Context) (*goipmi.Client, error) {
 	client, err := goipmi.NewClient(c.host, c.port, c.username, c.password)
 	if err != nil {
-		return nil, fmt.Errorf("creating IPMI client: %w", err)
+		return nil, classify(fmt.Errorf("creating IPMI client: %w", err), CodeUnreachable)
 	}
 
 	client.WithInterface(goipmi.InterfaceLanplus)
+	if c.sourceAddr != nil {
+		// go-ipmi dials UDP through its proxy hook; a bound net.Dialer
+		// serves as one.
+		client.WithUDPProxy(&net.Dialer{LocalAddr: &net.UDPAddr{IP: c.sourceAddr}})
+	}
 
-	ctx, cancel := c.ctx()
+	ctx, cancel := c.ctx(parent)
 	defer cancel()
 
 	if err := client.Connect(ctx); err != nil {
-		return nil, fmt.Errorf("IPMI connect to %s:%d: %w", c.host, c.port, err)
+		return nil, classify(fmt.Errorf("IPMI connect to %s:%d: %w", c.host, c.port, err), CodeUnreachable)
 	}
 
 	return client, nil
 }
 
-// GetPowerStatus returns the chassis power status via IPMI.
-func (c *Client) GetPowerStatus() (bool, error) {
-	client, err := c.connect()
-	if err != nil {
-		return false, err
+// Conn is a live IPMI session handed to a WithConnection callback. Each
+// method runs one command on the session; the Conn is only valid until the
+// callback returns.
+type Conn struct {
+	ctx    context.Context
+	client *goipmi.Client
+	// mutated is set once a command that changes BMC or chassis state has
+	// been sent, after which a failed callback is never replayed.
+	mutated bool
+}
+
+// WithConnection runs fn with one connected IPMI session, so several
+// commands share a single handshake:
+//
+//	err := c.WithConnection(func(cl *ipmi.Conn) error {
+//		on, err := cl.GetPowerStatus()
+//		...
+//		entries, err := cl.GetSEL()
+//		...
+//	})
+//
+// Without a persistent session it connects and closes around fn. With one,
+// it reuses the open session and drops it if fn fails; if the session was
+// reused and fn had sent only reads, fn is retried once on a fresh session.
+// Commands that change state (chassis control, boot override, watchdog
+// configuration) are never replayed. Errors are classified (see Error) for
+// API callers.
+func (c *Client) WithConnection(fn func(cl *Conn) error) error {
+	return c.WithConnectionContext(context.Background(), fn)
+}
+
+// WithConnectionContext is WithConnection with a context that bounds the
+// connect and every command on the session.
+func (c *Client) WithConnectionContext(parent context.Context, fn func(cl *Conn) error) (err error) {
+	defer func() { err = classify(err, CodeCommand) }()
+
+	if !c.persistent {
+		client, err := c.dial(parent)
+		if err != nil {
+			return err
+		}
+		ctx, cancel := c.ctx(parent)
+		defer cancel()
+		defer client.Close(ctx) //nolint:errcheck
+		return fn(&Conn{ctx: ctx, client: client})
+	}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		client := c.checkout()
+		reused := client != nil
+		if client == nil {
+			if client, err = c.dial(parent); err != nil {
+				return err
+			}
+		}
+
+		ctx, cancel := c.ctx(parent)
+		cl := &Conn{ctx: ctx, client: client}
+		err = fn(cl)
+		cancel()
+		if err == nil {
+			c.checkin(client)
+			return nil
+		}
+
+		c.closeSession(client)
+		if !reused || cl.mutated {
+			break // a brand-new session failed, or replaying could repeat a change
+		}
 	}
-	ctx, cancel := c.ctx()
+	return err
+}
+
+// checkout takes the idle persistent session, if any, for one exchange.
+func (c *Client) checkout() *goipmi.Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	client := c.conn
+	c.conn = nil
+	return client
+}
+
+// checkin returns a healthy session for the next call to reuse, or closes
+// it if another call has already put one back.
+func (c *Client) checkin(client *goipmi.Client) {
+	c.mu.Lock()
+	if c.conn == nil {
+		c.conn = client
+		client = nil
+	}
+	c.mu.Unlock()
+	if client != nil {
+		c.closeSession(client)
+	}
+}
+
+// closeSession closes a session that is no longer cached.
+func (c *Client) closeSession(client *goipmi.Client) {
+	ctx, cancel := c.ctx(context.Background())
 	defer cancel()
-	defer client.Close(ctx) //nolint:errcheck
+	client.Close(ctx) //nolint:errcheck
+}
+
+// Close tears down the idle persistent session, if any; one checked out by
+// a running call is closed or kept when the call ends. The client remains
+// usable; the next call reconnects.
+func (c *Client) Close() {
+	if client := c.checkout(); client != nil {
+		c.closeSession(client)
+	}
+}
+
+// GetPowerStatus returns the chassis power status via IPMI.
+func (c *Client) GetPowerStatus() (bool, error) {
+	return c.GetPowerStatusContext(context.Background())
+}
 
-	status, err := client.GetChassisStatus(ctx)
+// GetPowerStatusContext is GetPowerStatus with a context for cancellation.
+func (c *Client) GetPowerStatusContext(ctx context.Context) (on bool, err error) {
+	err = c.WithConnectionContext(ctx, func(cl *Conn) error {
+		on, err = cl.GetPowerStatus()
+		return err
+	})
+	return on, err
+}
+
+// GetPowerStatus returns the chassis power status.
+func (cl *Conn) GetPowerStatus() (bool, error) {
+	status, err := cl.client.GetChassisStatus(cl.ctx)
 	if err != nil {
 		return false, fmt.Errorf("IPMI chassis status: %w", err)
 	}
-
 	return status.PowerIsOn, nil
 }
 
@@ -91,32 +257,76 @@ func (c *Client) HardReset() error {
 	return c.chassisControl(goipmi.ChassisControlHardReset)
 }
 
-func (c *Client) chassisControl(control goipmi.ChassisControl) error {
-	client, err := c.connect()
-	if err != nil {
-		return err
+// SoftShutdown requests a graceful ACPI shutdown, letting the OS power
+// the chassis down cleanly.
+func (c *Client) SoftShutdown() error {
+	return c.chassisControl(goipmi.ChassisControlSoftShutdown)
+}
+
+// PowerActions maps IPMI power action names to chassis control commands.
+var PowerActions = map[string]goipmi.ChassisControl{
+	"on":       goipmi.ChassisControlPowerUp,
+	"off":      goipmi.ChassisControlPowerDown,
+	"cycle":    goipmi.ChassisControlPowerCycle,
+	"reset":    goipmi.ChassisControlHardReset,
+	"nmi":      goipmi.ChassisControlDiagnosticInterrupt,
+	"shutdown": goipmi.ChassisControlSoftShutdown,
+}
+
+// SetPowerByName executes a power action from PowerActions.
+func (c *Client) SetPowerByName(name string) error {
+	control, ok := PowerActions[name]
+	if !ok {
+		return invalid("unknown IPMI power action: %q (valid: %s)", name, powerActionNames())
 	}
-	ctx, cancel := c.ctx()
-	defer cancel()
-	defer client.Close(ctx) //nolint:errcheck
+	return c.chassisControl(control)
+}
+
+// SetPowerByName executes a power action from PowerActions.
+func (cl *Conn) SetPowerByName(name string) error {
+	control, ok := PowerActions[name]
+	if !ok {
+		return invalid("unknown IPMI power action: %q (valid: %s)", name, powerActionNames())
+	}
+	return cl.chassisControl(control)
+}
+
+// powerActionNames returns the sorted list of valid power action names.
+func powerActionNames() string {
+	names := make([]string, 0, len(PowerActions))
+	for name := range PowerActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
 
-	if _, err := client.ChassisControl(ctx, control); err != nil {
+func (c *Client) chassisControl(control goipmi.ChassisControl) error {
+	return c.WithConnection(func(cl *Conn) error {
+		return cl.chassisControl(control)
+	})
+}
+
+func (cl *Conn) chassisControl(control goipmi.ChassisControl) error {
+	cl.mutated = true
+	if _, err := cl.client.ChassisControl(cl.ctx, control); err != nil {
 		return fmt.Errorf("IPMI chassis control: %w", err)
 	}
 	return nil
 }
 
 // GetSEL returns the System Event Log entries via IPMI.
-func (c *Client) GetSEL() ([]SELEntry, error) {
-	client, err := c.connect()
-	if err != nil {
-		return nil, err
-	}
-	ctx, cancel := c.ctx()
-	defer cancel()
-	defer client.Close(ctx) //nolint:errcheck
+func (c *Client) GetSEL() (entries []SELEntry, err error) {
+	err = c.WithConnection(func(cl *Conn) error {
+		entries, err = cl.GetSEL()
+		return err
+	})
+	return entries, err
+}
 
-	entries, err := client.GetSELEntries(ctx, 0)
+// GetSEL returns the System Event Log entries.
+func (cl *Conn) GetSEL() ([]SELEntry, error) {
+	entries, err := cl.client.GetSELEntries(cl.ctx, 0)
 	if err != nil {
 		return nil, fmt.Errorf("IPMI SEL entries: %w", err)
 	}
@@ -1,6 +1,15 @@
 package ipmi
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	goipmi "github.com/bougou/go-ipmi"
+)
 
 func TestNewClient(t *testing.T) {
 	c := NewClient("10.0.0.1", 0, "root", "pass")
@@ -23,3 +32,282 @@ func TestNewClient_CustomPort(t *testing.T) {
 		t.Errorf("port = %d, want 624", c.port)
 	}
 }
+
+func TestBootDeviceName(t *testing.T) {
+	for name, sel := range BootDevices {
+		if got := bootDeviceName(sel); got != name {
+			t.Errorf("bootDeviceName(%v) = %q, want %q", sel, got, name)
+		}
+	}
+}
+
+func TestSetBootOnce_UnknownDevice(t *testing.T) {
+	c := NewClient("127.0.0.1", 0, "root", "pass")
+	if _, err := c.SetBootOnce("usb-stick"); err == nil {
+		t.Fatal("SetBootOnce(usb-stick) should fail before connecting")
+	}
+}
+
+// fakeDialer counts dials and hands out sessions that close without I/O.
+func fakeDialer(dials *int) func(context.Context) (*goipmi.Client, error) {
+	return func(context.Context) (*goipmi.Client, error) {
+		*dials++
+		return &goipmi.Client{Interface: goipmi.InterfaceTool}, nil
+	}
+}
+
+func TestWithConnectionContext_BoundsCommands(t *testing.T) {
+	var dials int
+	c := NewClient("10.0.0.1", 0, "root", "pass")
+	c.dial = fakeDialer(&dials)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := c.WithConnectionContext(ctx, func(cl *Conn) error {
+		return cl.ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WithConnectionContext() with a cancelled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestWithConnection_PersistentReusesSession(t *testing.T) {
+	var dials int
+	c := NewClient("10.0.0.1", 0, "root", "pass", WithPersistentSession())
+	c.dial = fakeDialer(&dials)
+
+	for i := 0; i < 3; i++ {
+		if err := c.WithConnection(func(*Conn) error { return nil }); err != nil {
+			t.Fatalf("WithConnection() error = %v", err)
+		}
+	}
+	if dials != 1 {
+		t.Errorf("dials = %d, want 1", dials)
+	}
+
+	c.Close()
+	if c.conn != nil {
+		t.Error("Close() should drop the session")
+	}
+	c.WithConnection(func(*Conn) error { return nil }) //nolint:errcheck
+	if dials != 2 {
+		t.Errorf("dials after Close = %d, want 2", dials)
+	}
+}
+
+func TestWithConnection_ConcurrentCallsDoNotWait(t *testing.T) {
+	var dials atomic.Int32
+	c := NewClient("10.0.0.1", 0, "root", "pass", WithPersistentSession())
+	c.dial = func(context.Context) (*goipmi.Client, error) {
+		dials.Add(1)
+		return &goipmi.Client{Interface: goipmi.InterfaceTool}, nil
+	}
+
+	// A call stuck in its exchange must not hold up another one.
+	inside, release := make(chan struct{}), make(chan struct{})
+	go c.WithConnection(func(*Conn) error { //nolint:errcheck
+		close(inside)
+		<-release
+		return nil
+	})
+	<-inside
+	done := make(chan error, 1)
+	go func() { done <- c.WithConnection(func(*Conn) error { return nil }) }()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WithConnection() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("a second call waited for the first call's exchange")
+	}
+	close(release)
+	if dials.Load() != 2 {
+		t.Errorf("dials = %d, want a second session for the concurrent call", dials.Load())
+	}
+}
+
+func TestWithConnection_ReconnectsStaleSession(t *testing.T) {
+	var dials int
+	c := NewClient("10.0.0.1", 0, "root", "pass", WithPersistentSession())
+	c.dial = fakeDialer(&dials)
+
+	// Establish a session, then make the next call fail once on it.
+	c.WithConnection(func(*Conn) error { return nil }) //nolint:errcheck
+
+	calls := 0
+	err := c.WithConnection(func(*Conn) error {
+		calls++
+		if calls == 1 {
+			return errors.New("session timed out")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithConnection() error = %v, want retry to succeed", err)
+	}
+	if calls != 2 || dials != 2 {
+		t.Errorf("calls = %d, dials = %d; want 2 and 2", calls, dials)
+	}
+}
+
+func TestWithConnection_NonIdempotentNotReplayed(t *testing.T) {
+	var dials int
+	c := NewClient("10.0.0.1", 0, "root", "pass", WithPersistentSession())
+	c.dial = fakeDialer(&dials)
+	c.WithConnection(func(*Conn) error { return nil }) //nolint:errcheck
+
+	calls := 0
+	err := c.WithConnection(func(cl *Conn) error {
+		calls++
+		cl.mutated = true
+		return errors.New("session timed out")
+	})
+	if err == nil || calls != 1 {
+		t.Errorf("calls = %d, err = %v; want a single failed attempt", calls, err)
+	}
+	if c.conn != nil {
+		t.Error("failed call should drop the session for the next call to reconnect")
+	}
+}
+
+func TestWithConnection_BatchSharesSession(t *testing.T) {
+	var dials int
+	c := NewClient("10.0.0.1", 0, "root", "pass")
+	c.dial = fakeDialer(&dials)
+
+	// A snapshot-style batch: several reads on one callback's session.
+	steps := 0
+	err := c.WithConnection(func(cl *Conn) error {
+		for i := 0; i < 3; i++ {
+			if cl.client == nil {
+				return errors.New("no session")
+			}
+			steps++
+		}
+		return nil
+	})
+	if err != nil || steps != 3 || dials != 1 {
+		t.Errorf("err = %v, steps = %d, dials = %d; want one connection for the batch", err, steps, dials)
+	}
+}
+
+func TestWithConnection_PerCall(t *testing.T) {
+	var dials int
+	c := NewClient("10.0.0.1", 0, "root", "pass")
+	c.dial = fakeDialer(&dials)
+
+	for i := 0; i < 2; i++ {
+		c.WithConnection(func(*Conn) error { return nil }) //nolint:errcheck
+	}
+	if dials != 2 || c.conn != nil {
+		t.Errorf("dials = %d, conn cached = %v; want a connection per call", dials, c.conn != nil)
+	}
+}
+
+func TestSetPowerByName_Unknown(t *testing.T) {
+	c := NewClient("127.0.0.1", 0, "root", "pass")
+	if err := c.SetPowerByName("hibernate"); err == nil {
+		t.Fatal("SetPowerByName(hibernate) should fail before connecting")
+	}
+	if PowerActions["shutdown"] != goipmi.ChassisControlSoftShutdown {
+		t.Error("shutdown should map to the ACPI soft shutdown")
+	}
+}
+
+func TestWatchdogConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     WatchdogConfig
+		wantErr bool
+	}{
+		{"valid", WatchdogConfig{Action: "reset", TimeoutSeconds: 300, PretimeoutSeconds: 30}, false},
+		{"no pretimeout", WatchdogConfig{Action: "none", TimeoutSeconds: 60}, false},
+		{"unknown action", WatchdogConfig{Action: "explode", TimeoutSeconds: 60}, true},
+		{"zero timeout", WatchdogConfig{Action: "reset"}, true},
+		{"timeout too large", WatchdogConfig{Action: "reset", TimeoutSeconds: 7000}, true},
+		{"pretimeout not below timeout", WatchdogConfig{Action: "reset", TimeoutSeconds: 10, PretimeoutSeconds: 10}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWatchdogActionName(t *testing.T) {
+	for name, action := range WatchdogActions {
+		if got := watchdogActionName(action); got != name {
+			t.Errorf("watchdogActionName(%v) = %q, want %q", action, got, name)
+		}
+	}
+}
+
+func TestLANConfigFrom(t *testing.T) {
+	mac, _ := net.ParseMAC("00:21:9b:aa:bb:cc")
+	cfg := lanConfigFrom(&goipmi.LanConfig{
+		IP:               net.ParseIP("10.0.0.50").To4(),
+		SubnetMask:       net.ParseIP("255.255.255.0").To4(),
+		DefaultGatewayIP: net.ParseIP("10.0.0.1").To4(),
+		MAC:              mac,
+		IPSource:         goipmi.IPAddressSourceStatic,
+		VLANEnabled:      true,
+		VLANID:           42,
+	})
+
+	want := LANConfig{
+		IPAddress:   "10.0.0.50",
+		Netmask:     "255.255.255.0",
+		Gateway:     "10.0.0.1",
+		MACAddress:  "00:21:9b:aa:bb:cc",
+		IPSource:    "static",
+		VLANEnabled: true,
+		VLANID:      42,
+	}
+	if *cfg != want {
+		t.Errorf("lanConfigFrom() = %+v, want %+v", *cfg, want)
+	}
+
+	if empty := lanConfigFrom(&goipmi.LanConfig{VLANID: 7}); empty.IPAddress != "" || empty.VLANID != 0 {
+		t.Errorf("unset fields = %+v, want empty address and no VLAN ID when VLAN disabled", *empty)
+	}
+}
+
+func TestUserFrom(t *testing.T) {
+	u := userFrom(2, "root", &goipmi.GetUserAccessResponse{
+		EnableStatus:         0x01,
+		IPMIMessagingEnabled: true,
+		MaxPrivLevel:         goipmi.PrivilegeLevelAdministrator,
+	})
+	want := User{ID: 2, Name: "root", Enabled: true, Privilege: "administrator", IPMIMessaging: true}
+	if u != want {
+		t.Errorf("userFrom() = %+v, want %+v", u, want)
+	}
+
+	disabled := userFrom(3, "", &goipmi.GetUserAccessResponse{EnableStatus: 0x02, MaxPrivLevel: 0x0f})
+	if disabled.Enabled || disabled.Privilege != "no-access" {
+		t.Errorf("disabled slot = %+v, want disabled with no-access", disabled)
+	}
+}
+
+func TestPowerPolicyName(t *testing.T) {
+	for name, p := range PowerPolicies {
+		if got := powerPolicyName(p); got != name {
+			t.Errorf("powerPolicyName(%v) = %q, want %q", p, got, name)
+		}
+	}
+	if got := powerPolicyName(powerPolicyNoChange); got != "unknown" {
+		t.Errorf("powerPolicyName(no change) = %q, want unknown", got)
+	}
+}
+
+func TestSetPowerPolicy_Unknown(t *testing.T) {
+	c := NewClient("127.0.0.1", 0, "root", "pass")
+	_, err := c.SetPowerPolicy("sometimes")
+	var ierr *Error
+	if !errors.As(err, &ierr) || ierr.Code != CodeInvalid {
+		t.Fatalf("SetPowerPolicy(sometimes) error = %v, want %s before connecting", err, CodeInvalid)
+	}
+}
@@ -0,0 +1,57 @@
+package ipmi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// Error codes carried by Error.
+const (
+	CodeTimeout     = "ipmi_timeout"
+	CodeUnreachable = "ipmi_unreachable"
+	CodeCommand     = "ipmi_error"
+	CodeInvalid     = "invalid_request"
+)
+
+// Error is a classified IPMI failure with the HTTP status an API should
+// answer with and a stable code. Find one with errors.As.
+type Error struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// HTTPStatus returns the suggested HTTP status.
+func (e *Error) HTTPStatus() int { return e.Status }
+
+// ErrorCode returns the machine-readable error code.
+func (e *Error) ErrorCode() string { return e.Code }
+
+// classify wraps err with code unless it is already classified; timeouts
+// are always CodeTimeout.
+func classify(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return &Error{Status: http.StatusGatewayTimeout, Code: CodeTimeout, Err: err}
+	}
+	return &Error{Status: http.StatusBadGateway, Code: code, Err: err}
+}
+
+// invalid returns a CodeInvalid error for a bad argument.
+func invalid(format string, args ...interface{}) error {
+	return &Error{Status: http.StatusBadRequest, Code: CodeInvalid, Err: fmt.Errorf(format, args...)}
+}
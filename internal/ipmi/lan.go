@@ -0,0 +1,74 @@
+package ipmi
+
+import (
+	"fmt"
+	"net"
+
+	goipmi "github.com/bougou/go-ipmi"
+)
+
+// lanChannel is the IPMI channel of the iDRAC6 dedicated/shared NIC.
+const lanChannel = 1
+
+// LANConfig is the BMC's network configuration.
+type LANConfig struct {
+	IPAddress   string `json:"ipAddress"`
+	Netmask     string `json:"netmask"`
+	Gateway     string `json:"gateway"`
+	MACAddress  string `json:"macAddress"`
+	IPSource    string `json:"ipSource"`
+	VLANEnabled bool   `json:"vlanEnabled"`
+	VLANID      uint16 `json:"vlanId,omitempty"`
+}
+
+// GetLANConfig returns the BMC's IP, netmask, gateway, MAC, VLAN, and IP
+// source from the LAN configuration parameters.
+func (c *Client) GetLANConfig() (cfg *LANConfig, err error) {
+	err = c.WithConnection(func(cl *Conn) error {
+		cfg, err = cl.GetLANConfig()
+		return err
+	})
+	return cfg, err
+}
+
+// GetLANConfig returns the BMC's network configuration.
+func (cl *Conn) GetLANConfig() (*LANConfig, error) {
+	// Request only the parameters we report rather than the full table.
+	params := &goipmi.LanConfigParams{
+		IP:               &goipmi.LanConfigParam_IP{},
+		IPSource:         &goipmi.LanConfigParam_IPSource{},
+		MAC:              &goipmi.LanConfigParam_MAC{},
+		SubnetMask:       &goipmi.LanConfigParam_SubnetMask{},
+		DefaultGatewayIP: &goipmi.LanConfigParam_DefaultGatewayIP{},
+		VLANID:           &goipmi.LanConfigParam_VLANID{},
+	}
+	if err := cl.client.GetLanConfigParamsFor(cl.ctx, lanChannel, params); err != nil {
+		return nil, fmt.Errorf("IPMI get LAN config: %w", err)
+	}
+	return lanConfigFrom(params.ToLanConfig()), nil
+}
+
+func lanConfigFrom(lc *goipmi.LanConfig) *LANConfig {
+	cfg := &LANConfig{
+		IPAddress:   ipString(lc.IP),
+		Netmask:     ipString(lc.SubnetMask),
+		Gateway:     ipString(lc.DefaultGatewayIP),
+		IPSource:    lc.IPSource.String(),
+		VLANEnabled: lc.VLANEnabled,
+	}
+	if len(lc.MAC) > 0 {
+		cfg.MACAddress = lc.MAC.String()
+	}
+	if lc.VLANEnabled {
+		cfg.VLANID = lc.VLANID
+	}
+	return cfg
+}
+
+// ipString formats ip, returning "" for an unset address.
+func ipString(ip net.IP) string {
+	if len(ip) == 0 {
+		return ""
+	}
+	return ip.String()
+}
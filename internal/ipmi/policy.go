@@ -0,0 +1,133 @@
+package ipmi
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	goipmi "github.com/bougou/go-ipmi"
+)
+
+// PowerPolicies maps power restore policy names to their IPMI values.
+var PowerPolicies = map[string]goipmi.PowerRestorePolicy{
+	"always-off": goipmi.PowerRestorePolicyAlwaysOff,
+	"last-state": goipmi.PowerRestorePolicyPrevious,
+	"always-on":  goipmi.PowerRestorePolicyAlwaysOn,
+}
+
+// powerPolicyNoChange is the Set Power Restore Policy value that leaves the
+// policy alone and only reports which policies the chassis supports.
+const powerPolicyNoChange goipmi.PowerRestorePolicy = 3
+
+// PowerPolicy is what the chassis does when AC power returns after a loss.
+type PowerPolicy struct {
+	Policy    string   `json:"policy"`
+	Supported []string `json:"supported"`
+}
+
+// powerPolicyName returns the policy name for an IPMI restore policy.
+func powerPolicyName(p goipmi.PowerRestorePolicy) string {
+	for name, v := range PowerPolicies {
+		if v == p {
+			return name
+		}
+	}
+	return "unknown"
+}
+
+// powerPolicyNames returns the sorted list of valid policy names.
+func powerPolicyNames() string {
+	names := make([]string, 0, len(PowerPolicies))
+	for name := range PowerPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// GetPowerPolicy returns the power restore policy and the policies the
+// chassis supports.
+func (c *Client) GetPowerPolicy() (policy *PowerPolicy, err error) {
+	err = c.WithConnection(func(cl *Conn) error {
+		policy, err = cl.GetPowerPolicy()
+		return err
+	})
+	return policy, err
+}
+
+// GetPowerPolicy returns the power restore policy; see Client.GetPowerPolicy.
+func (cl *Conn) GetPowerPolicy() (*PowerPolicy, error) {
+	status, err := cl.client.GetChassisStatus(cl.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("IPMI chassis status: %w", err)
+	}
+	supported, err := cl.supportedPowerPolicies()
+	if err != nil {
+		return nil, err
+	}
+	return &PowerPolicy{Policy: powerPolicyName(status.PowerRestorePolicy), Supported: supported}, nil
+}
+
+// supportedPowerPolicies asks the chassis which restore policies it
+// supports without changing the current one.
+func (cl *Conn) supportedPowerPolicies() ([]string, error) {
+	res, err := cl.client.SetPowerRestorePolicy(cl.ctx, powerPolicyNoChange)
+	if err != nil {
+		return nil, fmt.Errorf("IPMI power restore policy support: %w", err)
+	}
+	var supported []string
+	for _, p := range []struct {
+		name string
+		ok   bool
+	}{{"always-off", res.SupportPolicyAlwaysOff}, {"last-state", res.SupportPolicyPrevious}, {"always-on", res.SupportPolicyAlwaysOn}} {
+		if p.ok {
+			supported = append(supported, p.name)
+		}
+	}
+	return supported, nil
+}
+
+// SetPowerPolicy sets the power restore policy after checking the chassis
+// supports it, and verifies the change. On Dell 11G servers this is the
+// BIOS "AC Power Recovery" setting.
+func (c *Client) SetPowerPolicy(name string) (policy *PowerPolicy, err error) {
+	if _, ok := PowerPolicies[name]; !ok {
+		return nil, invalid("unknown power restore policy: %q (valid: %s)", name, powerPolicyNames())
+	}
+	err = c.WithConnection(func(cl *Conn) error {
+		policy, err = cl.SetPowerPolicy(name)
+		return err
+	})
+	return policy, err
+}
+
+// SetPowerPolicy sets the power restore policy and verifies it; see
+// Client.SetPowerPolicy.
+func (cl *Conn) SetPowerPolicy(name string) (*PowerPolicy, error) {
+	p, ok := PowerPolicies[name]
+	if !ok {
+		return nil, invalid("unknown power restore policy: %q (valid: %s)", name, powerPolicyNames())
+	}
+	supported, err := cl.supportedPowerPolicies()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(supported, name) {
+		return nil, invalid("power restore policy %q is not supported by this chassis (supported: %s)", name, strings.Join(supported, ", "))
+	}
+
+	cl.mutated = true
+	if _, err := cl.client.SetPowerRestorePolicy(cl.ctx, p); err != nil {
+		return nil, fmt.Errorf("IPMI set power restore policy: %w", err)
+	}
+
+	policy, err := cl.GetPowerPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("verifying power restore policy: %w", err)
+	}
+	if policy.Policy != name {
+		return policy, fmt.Errorf("power restore policy not applied: got %s", policy.Policy)
+	}
+	return policy, nil
+}
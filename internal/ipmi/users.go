@@ -0,0 +1,87 @@
+package ipmi
+
+import (
+	"errors"
+	"fmt"
+
+	goipmi "github.com/bougou/go-ipmi"
+)
+
+// User is an entry in the BMC user table. IPMI never returns passwords.
+type User struct {
+	ID            uint8  `json:"id"`
+	Name          string `json:"name"`
+	Enabled       bool   `json:"enabled"`
+	Privilege     string `json:"privilege"`
+	IPMIMessaging bool   `json:"ipmiMessaging"`
+	LinkAuth      bool   `json:"linkAuth"`
+}
+
+// userEnabled is the "user ID enable status" value for an enabled user.
+const userEnabled = 0x01
+
+// GetUsers returns the BMC user table for the LAN channel, including
+// empty slots, so unexpected enabled accounts stand out.
+func (c *Client) GetUsers() (users []User, err error) {
+	err = c.WithConnection(func(cl *Conn) error {
+		users, err = cl.GetUsers()
+		return err
+	})
+	return users, err
+}
+
+// GetUsers returns the BMC user table for the LAN channel.
+func (cl *Conn) GetUsers() ([]User, error) {
+	var users []User
+	for id := uint8(1); ; id++ {
+		access, err := cl.client.GetUserAccess(cl.ctx, lanChannel, id)
+		if err != nil {
+			return nil, fmt.Errorf("IPMI get user access for ID %d: %w", id, err)
+		}
+
+		name := ""
+		resp, err := cl.client.GetUsername(cl.ctx, id)
+		var respErr *goipmi.ResponseError
+		switch {
+		case err == nil:
+			name = resp.Username
+		case errors.As(err, &respErr):
+			// Unset user slots return a completion code; leave the name empty.
+		default:
+			return nil, fmt.Errorf("IPMI get username for ID %d: %w", id, err)
+		}
+
+		users = append(users, userFrom(id, name, access))
+		if id >= access.MaxUsersIDCount {
+			return users, nil
+		}
+	}
+}
+
+func userFrom(id uint8, name string, access *goipmi.GetUserAccessResponse) User {
+	return User{
+		ID:            id,
+		Name:          name,
+		Enabled:       access.EnableStatus == userEnabled,
+		Privilege:     privilegeName(access.MaxPrivLevel),
+		IPMIMessaging: access.IPMIMessagingEnabled,
+		LinkAuth:      access.LinkAuthEnabled,
+	}
+}
+
+// privilegeName returns a lowercase privilege level name.
+func privilegeName(level goipmi.PrivilegeLevel) string {
+	switch level {
+	case goipmi.PrivilegeLevelCallback:
+		return "callback"
+	case goipmi.PrivilegeLevelUser:
+		return "user"
+	case goipmi.PrivilegeLevelOperator:
+		return "operator"
+	case goipmi.PrivilegeLevelAdministrator:
+		return "administrator"
+	case goipmi.PrivilegeLevelOEM:
+		return "oem"
+	}
+	return "no-access"
+}
@@ -0,0 +1,153 @@
+package ipmi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	goipmi "github.com/bougou/go-ipmi"
+)
+
+// WatchdogActions maps watchdog expiry action names to IPMI timeout actions.
+var WatchdogActions = map[string]goipmi.TimeoutAction{
+	"none":  goipmi.TimeoutActionNoAction,
+	"reset": goipmi.TimeoutActionHardReset,
+	"off":   goipmi.TimeoutActionPowerDown,
+	"cycle": goipmi.TimeoutActionPowerCycle,
+}
+
+// maxWatchdogTimeout is the largest countdown IPMI can express: a 16-bit
+// count of 100ms ticks.
+const maxWatchdogTimeout = 6553
+
+// Watchdog is the BMC watchdog timer state.
+//
+// Once started, the timer counts down and the BMC performs Action (for
+// example a hard reset) when it reaches zero, unless something resets
+// ("pets") it first. Only start it when a process on the host, such as
+// an OS watchdog daemon, will keep resetting it.
+type Watchdog struct {
+	Running           bool    `json:"running"`
+	Use               string  `json:"use,omitempty"`
+	Action            string  `json:"action"`
+	TimeoutSeconds    float64 `json:"timeoutSeconds"`
+	PretimeoutSeconds int     `json:"pretimeoutSeconds"`
+	RemainingSeconds  float64 `json:"remainingSeconds"`
+}
+
+// WatchdogConfig configures the watchdog timer.
+type WatchdogConfig struct {
+	Action            string `json:"action"`
+	TimeoutSeconds    int    `json:"timeoutSeconds"`
+	PretimeoutSeconds int    `json:"pretimeoutSeconds,omitempty"`
+}
+
+// Validate checks the configuration against IPMI's limits.
+func (cfg WatchdogConfig) Validate() error {
+	if _, ok := WatchdogActions[cfg.Action]; !ok {
+		return invalid("unknown watchdog action: %q (valid: %s)", cfg.Action, watchdogActionNames())
+	}
+	if cfg.TimeoutSeconds < 1 || cfg.TimeoutSeconds > maxWatchdogTimeout {
+		return invalid("timeoutSeconds must be between 1 and %d", maxWatchdogTimeout)
+	}
+	if cfg.PretimeoutSeconds < 0 || cfg.PretimeoutSeconds > 255 || cfg.PretimeoutSeconds >= cfg.TimeoutSeconds {
+		return invalid("pretimeoutSeconds must be between 0 and 255 and less than timeoutSeconds")
+	}
+	return nil
+}
+
+// watchdogActionName returns the name for a timeout action.
+func watchdogActionName(action goipmi.TimeoutAction) string {
+	for name, a := range WatchdogActions {
+		if a == action {
+			return name
+		}
+	}
+	return action.String()
+}
+
+// watchdogActionNames returns the sorted list of valid watchdog actions.
+func watchdogActionNames() string {
+	names := make([]string, 0, len(WatchdogActions))
+	for name := range WatchdogActions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// GetWatchdog returns the current watchdog timer configuration and state.
+func (c *Client) GetWatchdog() (wd *Watchdog, err error) {
+	err = c.WithConnection(func(cl *Conn) error {
+		wd, err = cl.GetWatchdog()
+		return err
+	})
+	return wd, err
+}
+
+// GetWatchdog returns the current watchdog timer configuration and state.
+func (cl *Conn) GetWatchdog() (*Watchdog, error) {
+	resp, err := cl.client.GetWatchdogTimer(cl.ctx)
+	if err != nil {
+		return nil, fmt.Errorf("IPMI get watchdog timer: %w", err)
+	}
+	return &Watchdog{
+		Running:           resp.TimerIsStarted,
+		Use:               resp.TimerUse.String(),
+		Action:            watchdogActionName(resp.TimeoutAction),
+		TimeoutSeconds:    float64(resp.InitialCountdown) / 10,
+		PretimeoutSeconds: int(resp.PreTimeoutIntervalSec),
+		RemainingSeconds:  float64(resp.PresentCountdown) / 10,
+	}, nil
+}
+
+// SetWatchdog configures the watchdog timer. Per the IPMI spec, setting
+// the timer stops it; call ResetWatchdog to start the countdown.
+func (c *Client) SetWatchdog(cfg WatchdogConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	return c.WithConnection(func(cl *Conn) error {
+		return cl.SetWatchdog(cfg)
+	})
+}
+
+// SetWatchdog configures the watchdog timer; see Client.SetWatchdog.
+func (cl *Conn) SetWatchdog(cfg WatchdogConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	req := &goipmi.SetWatchdogTimerRequest{
+		TimerUse:              goipmi.TimerUseSMSOS,
+		TimeoutAction:         WatchdogActions[cfg.Action],
+		PreTimeoutIntervalSec: uint8(cfg.PretimeoutSeconds),
+		InitialCountdown:      uint16(cfg.TimeoutSeconds * 10),
+	}
+	if cfg.PretimeoutSeconds > 0 {
+		req.PreTimeoutInterrupt = goipmi.PreTimeoutInterruptMessaging
+	}
+
+	cl.mutated = true
+	if err := cl.client.Exchange(cl.ctx, req, &goipmi.SetWatchdogTimerResponse{}); err != nil {
+		return fmt.Errorf("IPMI set watchdog timer: %w", err)
+	}
+	return nil
+}
+
+// ResetWatchdog starts the watchdog countdown, or restarts it from the
+// configured timeout if it is already running ("petting" the watchdog).
+func (c *Client) ResetWatchdog() error {
+	return c.WithConnection(func(cl *Conn) error {
+		return cl.ResetWatchdog()
+	})
+}
+
+// ResetWatchdog starts or restarts the watchdog countdown. Restarting it
+// twice is harmless, so it does not prevent a retry.
+func (cl *Conn) ResetWatchdog() error {
+	if _, err := cl.client.ResetWatchdogTimer(cl.ctx); err != nil {
+		return fmt.Errorf("IPMI reset watchdog timer: %w", err)
+	}
+	return nil
+}
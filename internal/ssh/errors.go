@@ -0,0 +1,77 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// Error codes carried by Error.
+const (
+	CodeAuthFailed  = "racadm_auth_failed"
+	CodeTimeout     = "racadm_timeout"
+	CodeUnreachable = "racadm_unreachable"
+	CodeCommand     = "racadm_error"
+	CodeCanceled    = "canceled"
+)
+
+// StatusClientClosedRequest is the non-standard status (from nginx) for a
+// request abandoned by its client; it does not count as an iDRAC failure.
+const StatusClientClosedRequest = 499
+
+// Error is a classified RACADM failure with the HTTP status an API should
+// answer with and a stable code. Find one with errors.As.
+type Error struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// HTTPStatus returns the suggested HTTP status.
+func (e *Error) HTTPStatus() int { return e.Status }
+
+// ErrorCode returns the machine-readable error code.
+func (e *Error) ErrorCode() string { return e.Code }
+
+// dialError classifies a failed SSH connection.
+func dialError(err error) error {
+	var netErr net.Error
+	switch {
+	case strings.Contains(err.Error(), "unable to authenticate"):
+		return &Error{Status: http.StatusBadGateway, Code: CodeAuthFailed, Err: err}
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return &Error{Status: http.StatusGatewayTimeout, Code: CodeTimeout, Err: err}
+	default:
+		return &Error{Status: http.StatusBadGateway, Code: CodeUnreachable, Err: err}
+	}
+}
+
+// contextError replaces err with ctx's error once ctx has ended, since the
+// connection was closed on purpose and err only describes the fallout.
+func contextError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case nil:
+		return err
+	case context.DeadlineExceeded:
+		return &Error{Status: http.StatusGatewayTimeout, Code: CodeTimeout, Err: ctx.Err()}
+	default:
+		return &Error{Status: StatusClientClosedRequest, Code: CodeCanceled, Err: ctx.Err()}
+	}
+}
+
+// Disconnected reports whether err is a command that was cut off by the
+// connection closing before it exited, as happens when the command resets
+// the iDRAC.
+func Disconnected(err error) bool {
+	var exitMissing *ssh.ExitMissingError
+	return errors.As(err, &exitMissing) || errors.Is(err, io.EOF)
+}
@@ -3,7 +3,10 @@ package ssh
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"strings"
 	"time"
 
@@ -16,24 +19,67 @@ type RACAdm struct {
 	port     int
 	username string
 	password string
+	// sourceAddr, if set, is the local address SSH connections use.
+	sourceAddr net.IP
+	// timeout, if positive, bounds each command from dial to exit.
+	timeout time.Duration
+}
+
+// Option configures optional RACAdm behavior.
+type Option func(*RACAdm)
+
+// WithSourceAddress makes SSH connections originate from ip. A nil ip
+// leaves the choice to the OS.
+func WithSourceAddress(ip net.IP) Option {
+	return func(r *RACAdm) {
+		r.sourceAddr = ip
+	}
+}
+
+// WithTimeout bounds each command, connecting included, to d. A command
+// still running when it expires is aborted with a CodeTimeout error. Zero
+// leaves commands unbounded apart from the handshake timeout.
+func WithTimeout(d time.Duration) Option {
+	return func(r *RACAdm) {
+		r.timeout = d
+	}
 }
 
 // NewRACAdm creates a new RACADM SSH executor.
-func NewRACAdm(host string, port int, username, password string) *RACAdm {
+func NewRACAdm(host string, port int, username, password string, opts ...Option) *RACAdm {
 	if port == 0 {
 		port = 22
 	}
-	return &RACAdm{
+	r := &RACAdm{
 		host:     host,
 		port:     port,
 		username: username,
 		password: password,
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
+// handshakeTimeout bounds connecting and the SSH handshake.
+const handshakeTimeout = 10 * time.Second
+
 // Run executes a RACADM command and returns stdout.
 func (r *RACAdm) Run(args ...string) (string, error) {
+	return r.RunContext(context.Background(), args...)
+}
+
+// RunContext is Run with a context. When ctx ends, the SSH connection is
+// closed, aborting the handshake or the running command, so an abandoned
+// request does not leave a session open on the iDRAC.
+func (r *RACAdm) RunContext(ctx context.Context, args ...string) (string, error) {
 	cmd := "racadm " + strings.Join(args, " ")
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
 
 	config := &ssh.ClientConfig{
 		User: r.username,
@@ -41,19 +87,33 @@ func (r *RACAdm) Run(args ...string) (string, error) {
 			ssh.Password(r.password),
 		},
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(), //nolint:gosec // iDRAC6 has no CA
-		Timeout:         10 * time.Second,
 	}
 
 	addr := fmt.Sprintf("%s:%d", r.host, r.port)
-	client, err := ssh.Dial("tcp", addr, config)
+	dialer := &net.Dialer{Timeout: handshakeTimeout}
+	if r.sourceAddr != nil {
+		dialer.LocalAddr = &net.TCPAddr{IP: r.sourceAddr}
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("SSH connect to %s: %w", addr, contextError(ctx, dialError(err)))
+	}
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetDeadline(time.Now().Add(handshakeTimeout)) //nolint:errcheck
+	c, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
 	if err != nil {
-		return "", fmt.Errorf("SSH connect to %s: %w", addr, err)
+		conn.Close()
+		return "", fmt.Errorf("SSH connect to %s: %w", addr, contextError(ctx, dialError(err)))
 	}
+	conn.SetDeadline(time.Time{}) //nolint:errcheck
+	client := ssh.NewClient(c, chans, reqs)
 	defer client.Close()
 
 	session, err := client.NewSession()
 	if err != nil {
-		return "", fmt.Errorf("SSH session: %w", err)
+		return "", fmt.Errorf("SSH session: %w", contextError(ctx, &Error{Status: http.StatusBadGateway, Code: CodeUnreachable, Err: err}))
 	}
 	defer session.Close()
 
@@ -62,7 +122,13 @@ func (r *RACAdm) Run(args ...string) (string, error) {
 	session.Stderr = &stderr
 
 	if err := session.Run(cmd); err != nil {
-		return "", fmt.Errorf("RACADM command %q: %w (stderr: %s)", cmd, err, stderr.String())
+		err = contextError(ctx, &Error{Status: http.StatusBadGateway, Code: CodeCommand, Err: err})
+		// RACADM prints most errors to stdout, so fall back to it.
+		output := strings.TrimSpace(stderr.String())
+		if output == "" {
+			output = strings.TrimSpace(stdout.String())
+		}
+		return "", fmt.Errorf("RACADM command %q: %w (output: %s)", cmd, err, output)
 	}
 
 	return strings.TrimSpace(stdout.String()), nil
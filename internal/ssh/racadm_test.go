@@ -1,6 +1,12 @@
 package ssh
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
 
 func TestNewRACAdm(t *testing.T) {
 	r := NewRACAdm("10.0.0.1", 0, "root", "pass")
@@ -33,3 +39,86 @@ func TestRun_ConnectionError(t *testing.T) {
 		t.Fatal("Run() should fail with connection error")
 	}
 }
+
+func TestWithSourceAddress(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	// An address no local interface holds cannot be bound, so the dial
+	// fails before reaching the listener.
+	r := NewRACAdm("127.0.0.1", port, "root", "pass", WithSourceAddress(net.ParseIP("192.0.2.1")))
+	if r.sourceAddr.String() != "192.0.2.1" {
+		t.Fatalf("sourceAddr = %v, want 192.0.2.1", r.sourceAddr)
+	}
+	if _, err := r.Run("getsysinfo"); err == nil {
+		t.Error("Run() from an unassigned source address should fail")
+	}
+}
+
+func TestRunContext_CancelAbortsHandshake(t *testing.T) {
+	// A listener that accepts but never speaks SSH stalls the handshake.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	r := NewRACAdm("127.0.0.1", addr.Port, "root", "pass")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = r.RunContext(ctx, "getsysinfo")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("RunContext took %v after cancel", elapsed)
+	}
+	var e *Error
+	if !errors.Is(err, context.Canceled) || !errors.As(err, &e) || e.Status != StatusClientClosedRequest {
+		t.Errorf("RunContext() error = %v, want classified cancellation", err)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	addr := ln.Addr().(*net.TCPAddr)
+	r := NewRACAdm("127.0.0.1", addr.Port, "root", "pass", WithTimeout(50*time.Millisecond))
+
+	start := time.Now()
+	_, err = r.Run("getsysinfo")
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("Run took %v with a 50ms timeout", elapsed)
+	}
+	var e *Error
+	if !errors.As(err, &e) || e.Code != CodeTimeout {
+		t.Errorf("Run() error = %v, want %s", err, CodeTimeout)
+	}
+}
@@ -0,0 +1,67 @@
+// Package version reports which build of the manager is running. Version,
+// Commit, and Date are set at build time:
+//
+//	go build -ldflags "-X github.com/williamzujkowski/idrac6-manager/internal/version.Version=v1.2.0 \
+//	  -X github.com/williamzujkowski/idrac6-manager/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/williamzujkowski/idrac6-manager/internal/version.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+//
+// Values left unset fall back to the VCS stamp and module version the Go
+// toolchain embeds, then to "dev" and "unknown".
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Set with -ldflags "-X ...". See the package documentation.
+var (
+	Version = ""
+	Commit  = ""
+	Date    = ""
+)
+
+// Info describes the running build.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	Date      string `json:"date"`
+	GoVersion string `json:"goVersion"`
+	// Modified is set when the binary was built from a tree with
+	// uncommitted changes, as recorded by the Go toolchain.
+	Modified bool `json:"modified,omitempty"`
+}
+
+// Get returns the build information.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, Date: Date, GoVersion: runtime.Version()}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.Version == "" && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if info.Commit == "" {
+					info.Commit = s.Value
+				}
+			case "vcs.time":
+				if info.Date == "" {
+					info.Date = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.Date == "" {
+		info.Date = "unknown"
+	}
+	return info
+}
@@ -0,0 +1,99 @@
+package idrac
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+)
+
+// racadmRunner executes RACADM commands. *ssh.RACAdm satisfies it.
+type racadmRunner interface {
+	Run(args ...string) (string, error)
+}
+
+// Admin performs iDRAC6 operations that are only exposed through RACADM
+// over SSH (sessions, configuration groups, and similar).
+type Admin struct {
+	racadm racadmRunner
+}
+
+// contextRunner is a racadmRunner that can also be cancelled.
+type contextRunner interface {
+	RunContext(ctx context.Context, args ...string) (string, error)
+}
+
+// boundRunner runs every command under a fixed context.
+type boundRunner struct {
+	ctx    context.Context
+	runner contextRunner
+}
+
+func (b boundRunner) Run(args ...string) (string, error) {
+	return b.runner.RunContext(b.ctx, args...)
+}
+
+// WithContext returns a copy of a whose commands run under ctx: when ctx
+// is cancelled, for example because the inbound request was aborted, the
+// SSH session of any running command is closed. a itself is unchanged.
+func (a *Admin) WithContext(ctx context.Context) *Admin {
+	if b, ok := a.racadm.(boundRunner); ok {
+		return &Admin{racadm: boundRunner{ctx: ctx, runner: b.runner}}
+	}
+	if r, ok := a.racadm.(contextRunner); ok {
+		return &Admin{racadm: boundRunner{ctx: ctx, runner: r}}
+	}
+	return a
+}
+
+// RACADMOption configures the SSH connections behind Admin and
+// VirtualMedia.
+type RACADMOption = racadmssh.Option
+
+// WithRACADMSourceAddress makes RACADM's SSH connections originate from
+// ip; see WithSourceAddress.
+func WithRACADMSourceAddress(ip net.IP) RACADMOption {
+	return racadmssh.WithSourceAddress(ip)
+}
+
+// WithRACADMTimeout bounds each RACADM command to d; see
+// racadmssh.WithTimeout.
+func WithRACADMTimeout(d time.Duration) RACADMOption {
+	return racadmssh.WithTimeout(d)
+}
+
+// NewAdmin creates a new RACADM-backed Admin.
+func NewAdmin(host string, port int, username, password string, opts ...RACADMOption) *Admin {
+	return &Admin{
+		racadm: racadmssh.NewRACAdm(host, port, username, password, opts...),
+	}
+}
+
+// racadmSafe reports whether s can be passed as a RACADM argument over SSH
+// unquoted: printable ASCII with no whitespace, quotes, or backslashes.
+func racadmSafe(s string) bool {
+	for _, r := range s {
+		if r <= ' ' || r > '~' || strings.ContainsRune(`"'\`+"`", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseConfigGroup parses "racadm getconfig -g <group>" output into a map of
+// property names to values. Read-only properties are prefixed with "#" and
+// some firmware indexes them as "[Key=...]"; both are normalized.
+func parseConfigGroup(output string) map[string]string {
+	props := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		key, val, ok := strings.Cut(line, "=")
+		if !ok || strings.HasPrefix(line, "[") {
+			continue
+		}
+		props[strings.TrimSpace(key)] = strings.TrimSpace(val)
+	}
+	return props
+}
@@ -0,0 +1,49 @@
+package idrac
+
+import (
+	"context"
+	"testing"
+)
+
+// ctxRACADM records the context each command ran under.
+type ctxRACADM struct {
+	fakeRACADM
+	ctxs []context.Context
+}
+
+func (f *ctxRACADM) RunContext(ctx context.Context, args ...string) (string, error) {
+	f.ctxs = append(f.ctxs, ctx)
+	return f.Run(args...)
+}
+
+func TestAdminWithContext(t *testing.T) {
+	fake := &ctxRACADM{fakeRACADM: fakeRACADM{output: "Asset Tag=A1"}}
+	a := &Admin{racadm: fake}
+
+	type key struct{}
+	ctx := context.WithValue(context.Background(), key{}, "req")
+	bound := a.WithContext(ctx)
+	if _, err := bound.GetAssetTag(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fake.ctxs) != 1 || fake.ctxs[0].Value(key{}) != "req" {
+		t.Fatalf("commands ran under %v, want the bound context", fake.ctxs)
+	}
+
+	// Rebinding replaces the context rather than nesting adapters.
+	other := context.WithValue(context.Background(), key{}, "other")
+	if _, err := bound.WithContext(other).GetAssetTag(); err != nil {
+		t.Fatal(err)
+	}
+	if fake.ctxs[1].Value(key{}) != "other" {
+		t.Errorf("rebound context not used")
+	}
+	if a.racadm != fake {
+		t.Errorf("WithContext modified the original Admin")
+	}
+
+	plain := &Admin{racadm: &fakeRACADM{}}
+	if plain.WithContext(ctx) != plain {
+		t.Errorf("WithContext should return a runner without RunContext unchanged")
+	}
+}
@@ -0,0 +1,123 @@
+package idrac
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// maxAlertDestinations is how many SNMP trap (cfgIpmiPet) and email
+// (cfgEmailAlert) destinations iDRAC6 has, indexes 1-4 of each.
+const maxAlertDestinations = 4
+
+// AlertDestination is one SNMP trap or email alert slot. Address is empty
+// for an unused slot.
+type AlertDestination struct {
+	Index   int    `json:"index"`
+	Address string `json:"address,omitempty"`
+	Enabled bool   `json:"enabled"`
+}
+
+// AlertConfig is where the iDRAC sends platform event alerts. Warnings
+// flag settings under which no alert reaches anyone.
+type AlertConfig struct {
+	// Enabled is the global platform event alert switch
+	// (cfgIpmiLanAlertEnable); without it no destination is sent anything.
+	Enabled bool `json:"enabled"`
+	// Community is the SNMP community traps are sent with.
+	Community  string             `json:"community,omitempty"`
+	SMTPServer string             `json:"smtpServer,omitempty"`
+	SNMPTraps  []AlertDestination `json:"snmpTraps"`
+	Email      []AlertDestination `json:"email"`
+	Warnings   []string           `json:"warnings,omitempty"`
+}
+
+// GetAlertConfig reads the SNMP trap and email alert destinations from
+// the cfgIpmiPet and cfgEmailAlert groups, the alert switch and trap
+// community from cfgIpmiLan, and the SMTP server from cfgRemoteHosts.
+// iDRAC6 predates "racadm get iDRAC.SNMP.Alert", so each destination is a
+// separate getconfig call.
+func (a *Admin) GetAlertConfig() (*AlertConfig, error) {
+	lan, err := a.configGroup("cfgIpmiLan", 0)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := a.configGroup("cfgRemoteHosts", 0)
+	if err != nil {
+		return nil, err
+	}
+	ac := &AlertConfig{
+		Enabled:    lan["cfgIpmiLanAlertEnable"] == "1",
+		Community:  lan["cfgIpmiPetCommunityName"],
+		SMTPServer: unsetAddress(remote["cfgRhostsSmtpServerIpAddr"]),
+	}
+	for i := 1; i <= maxAlertDestinations; i++ {
+		pet, err := a.configGroup("cfgIpmiPet", i)
+		if err != nil {
+			return nil, err
+		}
+		ac.SNMPTraps = append(ac.SNMPTraps, AlertDestination{
+			Index:   i,
+			Address: unsetAddress(pet["cfgIpmiPetAlertDestIpAddr"]),
+			Enabled: pet["cfgIpmiPetAlertEnable"] == "1",
+		})
+
+		email, err := a.configGroup("cfgEmailAlert", i)
+		if err != nil {
+			return nil, err
+		}
+		ac.Email = append(ac.Email, AlertDestination{
+			Index:   i,
+			Address: email["cfgEmailAlertAddress"],
+			Enabled: email["cfgEmailAlertEnable"] == "1",
+		})
+	}
+	ac.Warnings = alertWarnings(ac)
+	return ac, nil
+}
+
+// configGroup reads a configuration group, or with a positive index one
+// instance of an indexed group.
+func (a *Admin) configGroup(group string, index int) (map[string]string, error) {
+	args := []string{"getconfig", "-g", group}
+	if index > 0 {
+		args = append(args, "-i", strconv.Itoa(index))
+	}
+	output, err := a.racadm.Run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s: %w", group, err)
+	}
+	return parseConfigGroup(output), nil
+}
+
+// unsetAddress maps the placeholder 0.0.0.0 of an unused slot to "".
+func unsetAddress(addr string) string {
+	if addr == "0.0.0.0" {
+		return ""
+	}
+	return addr
+}
+
+// alertWarnings flags alert settings that deliver nothing.
+func alertWarnings(ac *AlertConfig) []string {
+	active := func(dests []AlertDestination) bool {
+		for _, d := range dests {
+			if d.Enabled && d.Address != "" {
+				return true
+			}
+		}
+		return false
+	}
+	traps, email := active(ac.SNMPTraps), active(ac.Email)
+
+	var warnings []string
+	switch {
+	case !traps && !email:
+		warnings = append(warnings, "no enabled SNMP trap or email destination: alerts are not sent anywhere")
+	case !ac.Enabled:
+		warnings = append(warnings, "platform event alerts are disabled (cfgIpmiLanAlertEnable=0): enabled destinations receive nothing")
+	}
+	if email && ac.SMTPServer == "" {
+		warnings = append(warnings, "email destinations are enabled but no SMTP server is set (cfgRhostsSmtpServerIpAddr)")
+	}
+	return warnings
+}
@@ -0,0 +1,55 @@
+package idrac
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetAlertConfig(t *testing.T) {
+	fake := &scriptedRACADM{outputs: map[string]string{
+		"getconfig -g cfgIpmiLan":         "cfgIpmiLanEnable=1\ncfgIpmiLanAlertEnable=1\ncfgIpmiPetCommunityName=public\n",
+		"getconfig -g cfgRemoteHosts":     "cfgRhostsFwUpdateTftpEnable=1\ncfgRhostsSmtpServerIpAddr=0.0.0.0\n",
+		"getconfig -g cfgIpmiPet -i 1":    "# cfgIpmiPetIndex=1\ncfgIpmiPetAlertDestIpAddr=10.0.0.50\ncfgIpmiPetAlertEnable=1\n",
+		"getconfig -g cfgIpmiPet -i 2":    "# cfgIpmiPetIndex=2\ncfgIpmiPetAlertDestIpAddr=0.0.0.0\ncfgIpmiPetAlertEnable=0\n",
+		"getconfig -g cfgEmailAlert -i 1": "# cfgEmailAlertIndex=1\ncfgEmailAlertEnable=1\ncfgEmailAlertAddress=ops@example.com\n",
+		"getconfig -g cfgEmailAlert -i 2": "# cfgEmailAlertIndex=2\ncfgEmailAlertEnable=0\ncfgEmailAlertAddress=\n",
+	}}
+	a := &Admin{racadm: fake}
+
+	ac, err := a.GetAlertConfig()
+	if err != nil {
+		t.Fatalf("GetAlertConfig() error = %v", err)
+	}
+	if !ac.Enabled || ac.Community != "public" || ac.SMTPServer != "" {
+		t.Errorf("GetAlertConfig() = %+v", ac)
+	}
+	if len(ac.SNMPTraps) != maxAlertDestinations || len(ac.Email) != maxAlertDestinations {
+		t.Fatalf("destinations = %d traps, %d email; want %d each", len(ac.SNMPTraps), len(ac.Email), maxAlertDestinations)
+	}
+	if d := ac.SNMPTraps[0]; d.Index != 1 || d.Address != "10.0.0.50" || !d.Enabled {
+		t.Errorf("trap 1 = %+v", d)
+	}
+	if d := ac.SNMPTraps[1]; d.Address != "" || d.Enabled {
+		t.Errorf("unused trap 2 = %+v, want no address", d)
+	}
+	if d := ac.Email[0]; d.Address != "ops@example.com" || !d.Enabled {
+		t.Errorf("email 1 = %+v", d)
+	}
+	if len(ac.Warnings) != 1 || !strings.Contains(ac.Warnings[0], "SMTP") {
+		t.Errorf("Warnings = %q, want the missing SMTP server", ac.Warnings)
+	}
+	if len(fake.calls) != 2+2*maxAlertDestinations {
+		t.Errorf("%d RACADM commands, want %d", len(fake.calls), 2+2*maxAlertDestinations)
+	}
+}
+
+func TestAlertWarnings(t *testing.T) {
+	none := &AlertConfig{Enabled: true, SNMPTraps: []AlertDestination{{Index: 1, Enabled: true}}}
+	if w := alertWarnings(none); len(w) != 1 || !strings.Contains(w[0], "not sent anywhere") {
+		t.Errorf("no destination warnings = %q", w)
+	}
+	off := &AlertConfig{SNMPTraps: []AlertDestination{{Index: 1, Address: "10.0.0.50", Enabled: true}}}
+	if w := alertWarnings(off); len(w) != 1 || !strings.Contains(w[0], "disabled") {
+		t.Errorf("alerts off warnings = %q", w)
+	}
+}
@@ -0,0 +1,41 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+)
+
+// maxAssetTagLen is the longest asset tag the PowerEdge BIOS stores.
+const maxAssetTagLen = 10
+
+// ValidateAssetTag checks that tag fits the BIOS asset tag field.
+func ValidateAssetTag(tag string) error {
+	if tag == "" || len(tag) > maxAssetTagLen {
+		return fmt.Errorf("asset tag must be 1-%d characters", maxAssetTagLen)
+	}
+	if !racadmSafe(tag) {
+		return errors.New("asset tag may only contain printable ASCII without spaces, quotes, or backslashes")
+	}
+	return nil
+}
+
+// GetAssetTag returns the server's asset tag (cfgServerAssetTag), the
+// owner-assigned inventory ID, as opposed to Dell's service tag.
+func (a *Admin) GetAssetTag() (string, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgServerInfo", "-o", "cfgServerAssetTag")
+	if err != nil {
+		return "", fmt.Errorf("getting asset tag: %w", err)
+	}
+	return parseConfigValue(output, "cfgServerAssetTag"), nil
+}
+
+// SetAssetTag sets the server's asset tag.
+func (a *Admin) SetAssetTag(tag string) error {
+	if err := ValidateAssetTag(tag); err != nil {
+		return err
+	}
+	if _, err := a.racadm.Run("config", "-g", "cfgServerInfo", "-o", "cfgServerAssetTag", tag); err != nil {
+		return fmt.Errorf("setting asset tag: %w", err)
+	}
+	return nil
+}
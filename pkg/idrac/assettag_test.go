@@ -0,0 +1,33 @@
+package idrac
+
+import "testing"
+
+func TestValidateAssetTag(t *testing.T) {
+	for _, tag := range []string{"A1234", "INV-000042"} {
+		if err := ValidateAssetTag(tag); err != nil {
+			t.Errorf("ValidateAssetTag(%q) error = %v", tag, err)
+		}
+	}
+	for _, tag := range []string{"", "INV-0000042", "has space", `q"uote`} {
+		if err := ValidateAssetTag(tag); err == nil {
+			t.Errorf("ValidateAssetTag(%q) should fail", tag)
+		}
+	}
+}
+
+func TestAssetTag(t *testing.T) {
+	fake := &fakeRACADM{output: "cfgServerAssetTag=INV-42"}
+	a := &Admin{racadm: fake}
+
+	tag, err := a.GetAssetTag()
+	if err != nil || tag != "INV-42" {
+		t.Errorf("GetAssetTag() = %q, %v; want INV-42", tag, err)
+	}
+
+	if err := a.SetAssetTag("INV-43"); err != nil {
+		t.Fatalf("SetAssetTag() error = %v", err)
+	}
+	if last := fake.calls[len(fake.calls)-1]; last != "config -g cfgServerInfo -o cfgServerAssetTag INV-43" {
+		t.Errorf("command = %q", last)
+	}
+}
@@ -0,0 +1,68 @@
+package idrac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FirstBootDevices are the values cfgServerFirstBootDevice accepts, as
+// spelled in the iDRAC6 RACADM reference. "No-Override" leaves the BIOS
+// boot sequence in charge.
+var FirstBootDevices = []string{"No-Override", "PXE", "HDD", "DIAG", "CD-DVD", "BIOS", "vFDD", "VCD-DVD", "iSCSI", "FDD", "SD", "RFS"}
+
+// FirstBootDevice is the device the iDRAC boots the server from ahead of
+// the BIOS boot sequence (cfgServerInfo). iDRAC6 cannot read or reorder
+// the sequence itself, which lives in BIOS setup; the first boot device
+// is the persistent boot control it has. With BootOnce the iDRAC reverts
+// to No-Override after the next boot.
+type FirstBootDevice struct {
+	Device    string   `json:"device"`
+	BootOnce  bool     `json:"bootOnce"`
+	Available []string `json:"available"`
+}
+
+// ValidateFirstBootDevice returns the canonical spelling of device, one of
+// FirstBootDevices matched case-insensitively.
+func ValidateFirstBootDevice(device string) (string, error) {
+	for _, d := range FirstBootDevices {
+		if strings.EqualFold(d, device) {
+			return d, nil
+		}
+	}
+	return "", fmt.Errorf("unknown first boot device %q (valid: %s)", device, strings.Join(FirstBootDevices, ", "))
+}
+
+// GetFirstBootDevice returns the first boot device and whether it applies
+// to the next boot only.
+func (a *Admin) GetFirstBootDevice() (*FirstBootDevice, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgServerInfo")
+	if err != nil {
+		return nil, fmt.Errorf("getting first boot device: %w", err)
+	}
+	group := parseConfigGroup(output)
+	return &FirstBootDevice{
+		Device:    group["cfgServerFirstBootDevice"],
+		BootOnce:  group["cfgServerBootOnce"] == "1",
+		Available: append([]string(nil), FirstBootDevices...),
+	}, nil
+}
+
+// SetFirstBootDevice sets the first boot device, for the next boot only
+// when once is set and persistently otherwise.
+func (a *Admin) SetFirstBootDevice(device string, once bool) error {
+	device, err := ValidateFirstBootDevice(device)
+	if err != nil {
+		return err
+	}
+	bootOnce := "0"
+	if once {
+		bootOnce = "1"
+	}
+	if _, err := a.racadm.Run("config", "-g", "cfgServerInfo", "-o", "cfgServerBootOnce", bootOnce); err != nil {
+		return fmt.Errorf("setting boot once: %w", err)
+	}
+	if _, err := a.racadm.Run("config", "-g", "cfgServerInfo", "-o", "cfgServerFirstBootDevice", device); err != nil {
+		return fmt.Errorf("setting first boot device: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package idrac
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateFirstBootDevice(t *testing.T) {
+	if got, err := ValidateFirstBootDevice("pxe"); err != nil || got != "PXE" {
+		t.Errorf("ValidateFirstBootDevice(pxe) = %q, %v; want PXE", got, err)
+	}
+	for _, device := range []string{"", "NIC.Embedded.1-1", "HDD; racreset"} {
+		if _, err := ValidateFirstBootDevice(device); err == nil {
+			t.Errorf("ValidateFirstBootDevice(%q) should fail", device)
+		}
+	}
+}
+
+func TestFirstBootDevice(t *testing.T) {
+	fake := &fakeRACADM{output: "cfgServerName=\ncfgServerFirstBootDevice=HDD\ncfgServerBootOnce=1\n"}
+	a := &Admin{racadm: fake}
+
+	boot, err := a.GetFirstBootDevice()
+	if err != nil {
+		t.Fatalf("GetFirstBootDevice() error = %v", err)
+	}
+	if boot.Device != "HDD" || !boot.BootOnce || len(boot.Available) != len(FirstBootDevices) {
+		t.Errorf("GetFirstBootDevice() = %+v, want HDD once", boot)
+	}
+
+	fake.calls = nil
+	if err := a.SetFirstBootDevice("vcd-dvd", false); err != nil {
+		t.Fatalf("SetFirstBootDevice() error = %v", err)
+	}
+	want := "config -g cfgServerInfo -o cfgServerBootOnce 0|config -g cfgServerInfo -o cfgServerFirstBootDevice VCD-DVD"
+	if got := strings.Join(fake.calls, "|"); got != want {
+		t.Errorf("commands = %s, want %s", got, want)
+	}
+	if err := a.SetFirstBootDevice("Floppy.1", true); err == nil {
+		t.Error("SetFirstBootDevice() with an unknown device should fail")
+	}
+}
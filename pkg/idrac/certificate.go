@@ -0,0 +1,24 @@
+package idrac
+
+import racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+
+// RegenerateCertificate runs "racadm sslresetcfg", which replaces the web
+// server certificate with a newly generated self-signed one, the fix for
+// the long-expired certificates many iDRAC6s still serve. The iDRAC keeps
+// serving the old certificate until it is reset; see ResetController.
+func (a *Admin) RegenerateCertificate() error {
+	_, err := a.rawOutput("sslresetcfg")
+	return err
+}
+
+// ResetController runs "racadm racreset", restarting the iDRAC itself
+// (not the host). Every session, including this SSH connection, drops, and
+// the iDRAC is unreachable for a minute or two. The connection often drops
+// before the command reports back, so that counts as success.
+func (a *Admin) ResetController() error {
+	_, err := a.rawOutput("racreset")
+	if err != nil && racadmssh.Disconnected(err) {
+		return nil
+	}
+	return err
+}
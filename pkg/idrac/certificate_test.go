@@ -0,0 +1,41 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+)
+
+func TestRegenerateCertificate(t *testing.T) {
+	fake := &fakeRACADM{output: "Certificate generated successfully and webserver restarted.\n"}
+	a := &Admin{racadm: fake}
+	if err := a.RegenerateCertificate(); err != nil {
+		t.Fatalf("RegenerateCertificate() error = %v", err)
+	}
+	if err := a.ResetController(); err != nil {
+		t.Fatalf("ResetController() error = %v", err)
+	}
+	if len(fake.calls) != 2 || fake.calls[0] != "sslresetcfg" || fake.calls[1] != "racreset" {
+		t.Errorf("commands = %q, want sslresetcfg then racreset", fake.calls)
+	}
+
+	fake.output = "ERROR: Unable to perform requested operation."
+	var rerr *racadmssh.Error
+	if err := a.RegenerateCertificate(); !errors.As(err, &rerr) || rerr.Code != racadmssh.CodeCommand {
+		t.Errorf("RegenerateCertificate() on failure error = %v, want a RACADM command error", err)
+	}
+
+	// racreset often cuts the SSH session before the command exits.
+	fake.output, fake.err = "", fmt.Errorf("RACADM command %q: %w", "racadm racreset", &racadmssh.Error{Code: racadmssh.CodeCommand, Err: &ssh.ExitMissingError{}})
+	if err := a.ResetController(); err != nil {
+		t.Errorf("ResetController() with the session dropped error = %v, want nil", err)
+	}
+	fake.err = &racadmssh.Error{Code: racadmssh.CodeUnreachable, Err: errors.New("dial tcp: connection refused")}
+	if err := a.ResetController(); err == nil {
+		t.Error("ResetController() on an unreachable iDRAC error = nil")
+	}
+}
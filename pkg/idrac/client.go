@@ -0,0 +1,965 @@
+package idrac
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies spans created by this package.
+const tracerName = "github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+
+// Client communicates with an iDRAC6 controller via its XML REST API.
+type Client struct {
+	host     string
+	username string
+	password string
+	baseURL  string
+	// hostErr is why host could not be parsed; Login and Ping return it.
+	hostErr error
+
+	mu        sync.Mutex
+	http      *http.Client
+	sessionID string
+	// cookieName is the session cookie, _appwebSessionId_ unless an OEM
+	// build renamed it.
+	cookieName string
+	st1        string
+	st2        string
+	newAuth    bool
+	// firmware is the controller's firmware version, when known; it
+	// decides whether a tokenless login is worth a second look.
+	firmware string
+	// sessionGen increments on every successful login so concurrent 401
+	// handlers can tell whether someone else already re-authenticated.
+	sessionGen uint64
+	// unauthorizedAt is when (UnixNano) a request last got 401 right after
+	// a fresh login; within authzGrace of it a 401 fails without re-login.
+	unauthorizedAt atomic.Int64
+	authzGrace     time.Duration
+
+	// fallbacks are tried after username/password; credIndex is the
+	// candidate that last worked (0 is username/password).
+	fallbacks []Credential
+	credIndex int
+
+	// cookieAttempts and cookieDelay bound the retries when /start.html
+	// sets no session cookie.
+	cookieAttempts int
+	cookieDelay    time.Duration
+
+	// timeouts bound each attempt of Get (Query) and Set (Action).
+	timeouts Timeouts
+	// busyDelay is the wait before retrying a 503 without Retry-After.
+	busyDelay time.Duration
+
+	loginForm   LoginForm
+	middlewares []Middleware
+	tracer      trace.Tracer
+
+	logins        atomic.Int64
+	loginFailures atomic.Int64
+	retries       atomic.Int64
+	busyRetries   atomic.Int64
+}
+
+// ClientStats holds operational counters for a Client.
+type ClientStats struct {
+	Logins        int64 `json:"logins"`
+	LoginFailures int64 `json:"loginFailures"`
+	Retries       int64 `json:"retries"`
+	// BusyRetries counts requests repeated after a 503.
+	BusyRetries int64 `json:"busyRetries"`
+}
+
+// loginResponse is the XML response from POST /data/login.
+type loginResponse struct {
+	XMLName    xml.Name `xml:"root"`
+	AuthResult int      `xml:"authResult"`
+	ForwardURL string   `xml:"forwardUrl"`
+	ErrorMsg   string   `xml:"errorMsg"`
+}
+
+// LoginForm describes how credentials are encoded in the login POST body.
+// Genuine iDRAC6 firmware expects "user" then "password"; some OEM-rebranded
+// controllers use different field names or reverse the order.
+type LoginForm struct {
+	UserField     string `json:"userField,omitempty" yaml:"user_field,omitempty"`
+	PasswordField string `json:"passwordField,omitempty" yaml:"password_field,omitempty"`
+	PasswordFirst bool   `json:"passwordFirst,omitempty" yaml:"password_first,omitempty"`
+}
+
+// DefaultLoginForm is the login form layout used by Dell iDRAC6 firmware.
+var DefaultLoginForm = LoginForm{UserField: "user", PasswordField: "password"}
+
+// encode builds the form body, preserving field order.
+func (f LoginForm) encode(username, password string) string {
+	userField := f.UserField
+	if userField == "" {
+		userField = DefaultLoginForm.UserField
+	}
+	passField := f.PasswordField
+	if passField == "" {
+		passField = DefaultLoginForm.PasswordField
+	}
+
+	userPart := userField + "=" + url.QueryEscape(username)
+	passPart := passField + "=" + url.QueryEscape(password)
+	if f.PasswordFirst {
+		return passPart + "&" + userPart
+	}
+	return userPart + "&" + passPart
+}
+
+// Option configures optional Client behavior.
+type Option func(*Client)
+
+// DefaultSessionCookie is the session cookie genuine iDRAC6 firmware sets.
+const DefaultSessionCookie = "_appwebSessionId_"
+
+// WithSessionCookie overrides the session cookie name for OEM-rebranded
+// iDRAC6-derived controllers. An empty name keeps the default.
+func WithSessionCookie(name string) Option {
+	return func(c *Client) {
+		if name != "" {
+			c.cookieName = name
+		}
+	}
+}
+
+// Defaults for WithSessionCookieRetry.
+const (
+	DefaultSessionCookieAttempts = 3
+	DefaultSessionCookieDelay    = 500 * time.Millisecond
+)
+
+// WithSessionCookieRetry sets how many times login requests /start.html,
+// waiting delay between tries, when a busy iDRAC answers without setting
+// the session cookie. attempts below 1 means one try.
+func WithSessionCookieRetry(attempts int, delay time.Duration) Option {
+	return func(c *Client) {
+		c.cookieAttempts = max(attempts, 1)
+		c.cookieDelay = delay
+	}
+}
+
+// DefaultAuthzGrace is how long a Client stops re-logging in on 401 after
+// a fresh session was itself rejected.
+const DefaultAuthzGrace = 30 * time.Second
+
+// WithAuthzGrace sets how long, after a request is rejected with 401 right
+// after a successful login, further 401s fail at once with a
+// CodeNotAuthorized error instead of logging in again and retrying, which
+// would only be rejected the same way. The next 200 ends the grace early.
+// Zero or less re-logs in on every 401.
+func WithAuthzGrace(d time.Duration) Option {
+	return func(c *Client) {
+		c.authzGrace = d
+	}
+}
+
+// DefaultBusyRetryDelay is how long the client waits before retrying a
+// 503 that carries no Retry-After header, and the least it waits for one
+// that does.
+const DefaultBusyRetryDelay = time.Second
+
+// WithBusyRetry sets the wait before retrying a 503 without Retry-After,
+// which is also the floor under a Retry-After of zero or a past date.
+// Retries continue while the total wait fits in the request's timeout.
+// Zero or less returns the first 503 at once.
+func WithBusyRetry(delay time.Duration) Option {
+	return func(c *Client) {
+		c.busyDelay = delay
+	}
+}
+
+// WithLoginForm overrides the login form field names and order.
+func WithLoginForm(f LoginForm) Option {
+	return func(c *Client) {
+		c.loginForm = f
+	}
+}
+
+// Middleware wraps the client's HTTP transport, e.g. to log, sign, or trace
+// every request sent to the iDRAC.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithMiddleware wraps the client's transport with mw. Middlewares are
+// applied after all other options, in order, so the first one given is the
+// outermost; the default TLS transport stays innermost.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, mw...)
+	}
+}
+
+// WithTracerProvider sets the provider for Login/Get/Set spans. By default
+// the global OpenTelemetry provider is used, which is a no-op until the
+// application installs one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// WithTLSVerify enables certificate verification against roots. A nil pool
+// uses the system roots. By default verification is disabled because iDRAC6
+// ships with self-signed certificates.
+func WithTLSVerify(roots *x509.CertPool) Option {
+	return func(c *Client) {
+		if tr := c.transport(); tr != nil {
+			tr.TLSClientConfig.InsecureSkipVerify = false
+			tr.TLSClientConfig.RootCAs = roots
+		}
+	}
+}
+
+// WithSourceAddress makes connections to the iDRAC originate from ip, for
+// multi-homed hosts whose firewalls only admit one interface. A nil ip
+// leaves the choice to the OS.
+func WithSourceAddress(ip net.IP) Option {
+	return func(c *Client) {
+		if tr := c.transport(); tr != nil && ip != nil {
+			tr.DialContext = (&net.Dialer{
+				LocalAddr: &net.TCPAddr{IP: ip},
+				Timeout:   30 * time.Second,
+				KeepAlive: 30 * time.Second,
+			}).DialContext
+		}
+	}
+}
+
+// ConnReuse tunes how many idle connections the client keeps open for
+// reuse and for how long. iDRAC6 TLS handshakes take hundreds of
+// milliseconds on its slow service processor, so reusing a connection
+// makes back-to-back requests much cheaper.
+type ConnReuse struct {
+	// MaxIdleConns caps idle connections across all hosts.
+	MaxIdleConns int
+	// MaxIdleConnsPerHost caps idle connections to the iDRAC.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept.
+	IdleConnTimeout time.Duration
+}
+
+// DefaultConnReuse favors reuse: a client talks to one iDRAC, so every
+// idle slot is for it, enough to cover a snapshot's parallel reads, and
+// connections outlive a typical 30-60s polling interval.
+var DefaultConnReuse = ConnReuse{MaxIdleConns: 4, MaxIdleConnsPerHost: 4, IdleConnTimeout: 2 * time.Minute}
+
+// WithConnReuse overrides the connection reuse settings. Zero fields keep
+// the DefaultConnReuse value; a negative IdleConnTimeout disables
+// keep-alive so every request opens a new connection.
+func WithConnReuse(cr ConnReuse) Option {
+	return func(c *Client) {
+		tr := c.transport()
+		if tr == nil {
+			return
+		}
+		if cr.MaxIdleConns > 0 {
+			tr.MaxIdleConns = cr.MaxIdleConns
+		}
+		if cr.MaxIdleConnsPerHost > 0 {
+			tr.MaxIdleConnsPerHost = cr.MaxIdleConnsPerHost
+		}
+		switch {
+		case cr.IdleConnTimeout > 0:
+			tr.IdleConnTimeout = cr.IdleConnTimeout
+		case cr.IdleConnTimeout < 0:
+			tr.DisableKeepAlives = true
+		}
+	}
+}
+
+// Timeouts bounds requests to the iDRAC by kind. Query covers reads and
+// logins; Action covers Set and PostForm, such as power changes, which the
+// iDRAC6 may take much longer to acknowledge than a sensor read.
+type Timeouts struct {
+	Query  time.Duration
+	Action time.Duration
+}
+
+// DefaultTimeouts keeps reads snappy while giving actions room to finish.
+var DefaultTimeouts = Timeouts{Query: 15 * time.Second, Action: 60 * time.Second}
+
+// WithTimeouts overrides the request timeouts. Zero fields keep the
+// DefaultTimeouts value. Each attempt gets the full timeout, so a request
+// retried after a re-login can take up to twice as long.
+func WithTimeouts(t Timeouts) Option {
+	return func(c *Client) {
+		if t.Query > 0 {
+			c.timeouts.Query = t.Query
+		}
+		if t.Action > 0 {
+			c.timeouts.Action = t.Action
+		}
+	}
+}
+
+// LoadCABundle reads a PEM file of CA certificates into a new pool, for
+// iDRACs whose certificates are re-signed by an internal CA.
+func LoadCABundle(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// NewClient creates a new iDRAC6 API client.
+func NewClient(host, username, password string, opts ...Option) *Client {
+	c := &Client{
+		host:           host,
+		username:       username,
+		password:       password,
+		loginForm:      DefaultLoginForm,
+		cookieName:     DefaultSessionCookie,
+		cookieAttempts: DefaultSessionCookieAttempts,
+		cookieDelay:    DefaultSessionCookieDelay,
+		authzGrace:     DefaultAuthzGrace,
+		timeouts:       DefaultTimeouts,
+		busyDelay:      DefaultBusyRetryDelay,
+		tracer:         otel.GetTracerProvider().Tracer(tracerName),
+		http: &http.Client{
+			// No cookie jar — session cookies are managed manually via applySession()
+			// to avoid duplicate cookie issues with iDRAC6's strict session handling.
+			CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+				return http.ErrUseLastResponse // Don't follow redirects
+			},
+			Transport: &http.Transport{
+				// Let net/http negotiate and transparently decode gzip.
+				DisableCompression:  false,
+				TLSClientConfig:     legacyTLSConfig(),
+				MaxIdleConns:        DefaultConnReuse.MaxIdleConns,
+				MaxIdleConnsPerHost: DefaultConnReuse.MaxIdleConnsPerHost,
+				IdleConnTimeout:     DefaultConnReuse.IdleConnTimeout,
+			},
+		},
+	}
+
+	c.baseURL, c.hostErr = ParseHost(host)
+	if c.hostErr != nil {
+		c.baseURL = "https://" + host
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+	// Requests bound themselves by kind; the client-wide timeout is only a
+	// backstop for those that do not, such as Logout and Ping.
+	c.http.Timeout = max(c.timeouts.Query, c.timeouts.Action)
+
+	// Wrap last so transport options above still see the *http.Transport.
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		c.http.Transport = c.middlewares[i](c.http.Transport)
+	}
+
+	return c
+}
+
+// transport returns the client's *http.Transport, or nil if it was replaced.
+func (c *Client) transport() *http.Transport {
+	tr, _ := c.http.Transport.(*http.Transport)
+	return tr
+}
+
+// hostOnly strips any port from an "ip:port" host string.
+func hostOnly(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// ParseHost normalizes an iDRAC address to the client's base URL. host is
+// a bare "ip" or "ip:port", which get https, or a URL with an http or
+// https scheme, e.g. "http://proxy:8080/idrac1" for an iDRAC behind a
+// plain-HTTP proxy. A path is kept as a prefix; user info, a query, or a
+// fragment is an error.
+func ParseHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return "", errors.New("iDRAC host is empty")
+	}
+	raw := host
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid iDRAC host %q: %w", host, err)
+	}
+	switch {
+	case u.Scheme != "http" && u.Scheme != "https":
+		return "", fmt.Errorf("invalid iDRAC host %q: scheme must be http or https", host)
+	case u.Hostname() == "":
+		return "", fmt.Errorf("invalid iDRAC host %q: no host name", host)
+	case u.User != nil || u.RawQuery != "" || u.Fragment != "":
+		return "", fmt.Errorf("invalid iDRAC host %q: only scheme, host, port, and path are allowed", host)
+	}
+	return u.Scheme + "://" + u.Host + strings.TrimRight(u.EscapedPath(), "/"), nil
+}
+
+// HostName returns the host name or IP of an address in any form
+// ParseHost accepts, without scheme, port, or path, for RACADM and IPMI,
+// which do not use the web port.
+func HostName(host string) string {
+	if base, err := ParseHost(host); err == nil {
+		if u, err := url.Parse(base); err == nil {
+			return u.Hostname()
+		}
+	}
+	return hostOnly(host)
+}
+
+// describeTLSError turns certificate verification failures into an
+// actionable error instead of a generic handshake failure.
+func describeTLSError(host string, err error) error {
+	var verifyErr *tls.CertificateVerificationError
+	var unknownAuth x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	var reason string
+	switch {
+	case errors.As(err, &unknownAuth):
+		reason = "certificate signed by unknown authority (provide the issuing CA bundle)"
+	case errors.As(err, &hostErr):
+		reason = "certificate does not match host " + HostName(host)
+	case errors.As(err, &invalidErr):
+		reason = "certificate is invalid or expired"
+	case errors.As(err, &verifyErr):
+		reason = "certificate verification failed"
+	default:
+		return err
+	}
+	return fmt.Errorf("TLS verification for %s failed: %s: %w", host, reason, err)
+}
+
+// startSpan starts a client span tagged with the iDRAC host.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs, attribute.String("idrac.host", c.host))
+	return c.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Login authenticates with the iDRAC6 and stores the session.
+func (c *Client) Login() error {
+	return c.LoginContext(context.Background())
+}
+
+// LoginContext is Login with a context for cancellation and tracing.
+func (c *Client) LoginContext(ctx context.Context) (err error) {
+	ctx, span := c.startSpan(ctx, "idrac.Login")
+	defer func() { endSpan(span, err) }()
+
+	if c.hostErr != nil {
+		return c.hostErr
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.login(ctx)
+}
+
+// login authenticates under the query timeout, which bounds the whole
+// exchange, cookie retries included.
+func (c *Client) login(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, c.timeouts.Query)
+	defer cancel()
+	c.logins.Add(1)
+	if err := c.loginAny(ctx); err != nil {
+		c.loginFailures.Add(1)
+		return err
+	}
+	c.sessionGen++
+	return nil
+}
+
+func (c *Client) doLogin(ctx context.Context, username, password string) error {
+	// Step 1: Get session cookie from /start.html
+	if err := c.acquireSessionCookie(ctx); err != nil {
+		return err
+	}
+
+	// Step 2: Login with the session cookie
+	// IMPORTANT: iDRAC6 requires "user" before "password" in the POST body.
+	// Go's url.Values.Encode() sorts alphabetically, which breaks auth.
+	formBody := c.loginForm.encode(username, password)
+
+	loginReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/data/login", strings.NewReader(formBody))
+	if err != nil {
+		return fmt.Errorf("creating login request: %w", err)
+	}
+	loginReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	loginReq.AddCookie(&http.Cookie{Name: c.cookieName, Value: c.sessionID})
+
+	loginResp, err := c.http.Do(loginReq)
+	if err != nil {
+		return fmt.Errorf("login request failed: %w", transportError(err))
+	}
+	defer loginResp.Body.Close()
+
+	body, err := readBody(loginResp)
+	if err != nil {
+		return fmt.Errorf("reading login response: %w", err)
+	}
+
+	var result loginResponse
+	if err := decodeXML(body, &result); err != nil {
+		return fmt.Errorf("parsing login response: %w", err)
+	}
+
+	// authResult: 0=success, non-zero=failure
+	// (1=bad credentials, 2=missing user, 3=missing password, 4=privilege, 5=session limit)
+	switch result.AuthResult {
+	case 0:
+	case 1, 2, 3:
+		return fmt.Errorf("login failed: authResult=%d, error=%s: %w", result.AuthResult, result.ErrorMsg, ErrBadCredentials)
+	case 5:
+		return &Error{Status: http.StatusServiceUnavailable, Code: CodeSessionLimit,
+			Err: fmt.Errorf("login failed: authResult=%d, error=%s (session limit reached)", result.AuthResult, result.ErrorMsg)}
+	default:
+		return &Error{Status: http.StatusBadGateway, Code: CodeUpstream,
+			Err: fmt.Errorf("login failed: authResult=%d, error=%s", result.AuthResult, result.ErrorMsg)}
+	}
+
+	// Check if login response provides a new/different session cookie
+	for _, cookie := range loginResp.Cookies() {
+		if cookie.Name == c.cookieName {
+			c.sessionID = cookie.Value
+			break
+		}
+	}
+
+	// Extract ST1/ST2 tokens for newAuth (firmware >=2.92). Most builds put
+	// them in forwardUrl; some send them as headers, and some only on the
+	// forward page itself.
+	c.st1, c.st2, c.newAuth = "", "", false
+	if result.ForwardURL != "" {
+		c.extractTokens(result.ForwardURL)
+	}
+	if !c.newAuth {
+		c.tokensFromHeader(loginResp.Header)
+	}
+	if c.firmware == "" {
+		c.detectFirmware(ctx)
+	}
+	if !c.newAuth && requiresNewAuth(c.firmware) {
+		c.fetchTokens(ctx, result.ForwardURL)
+	}
+
+	return nil
+}
+
+// acquireSessionCookie requests /start.html until it sets the session
+// cookie, up to c.cookieAttempts times. A busy iDRAC sometimes answers
+// without one; transport errors are not retried.
+func (c *Client) acquireSessionCookie(ctx context.Context) error {
+	for attempt := 1; ; attempt++ {
+		err := c.startSession(ctx)
+		if err == nil || !errors.Is(err, errNoSessionCookie) || attempt >= c.cookieAttempts {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", err, ctx.Err())
+		case <-time.After(c.cookieDelay):
+		}
+	}
+}
+
+// errNoSessionCookie marks a /start.html response without a session cookie.
+var errNoSessionCookie = errors.New("no session cookie from /start.html")
+
+// startSession requests /start.html and records its session cookie.
+// iDRAC6 sets the session cookie (_appwebSessionId_) on the start page,
+// not on login POST.
+func (c *Client) startSession(ctx context.Context) error {
+	sessionReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/start.html", nil)
+	if err != nil {
+		return fmt.Errorf("creating session request: %w", err)
+	}
+
+	sessionResp, err := c.http.Do(sessionReq)
+	if err != nil {
+		return fmt.Errorf("session request failed: %w", transportError(describeTLSError(c.host, err)))
+	}
+	sessionResp.Body.Close()
+
+	// Extract session cookie from start.html response
+	c.sessionID = ""
+	for _, cookie := range sessionResp.Cookies() {
+		if cookie.Name == c.cookieName {
+			c.sessionID = cookie.Value
+			break
+		}
+	}
+
+	// Also check set-cookie header directly
+	if c.sessionID == "" {
+		setCookie := sessionResp.Header.Get("Set-Cookie")
+		if idx := strings.Index(setCookie, c.cookieName+"="); idx >= 0 {
+			val := setCookie[idx+len(c.cookieName)+1:]
+			if semi := strings.Index(val, ";"); semi >= 0 {
+				val = val[:semi]
+			}
+			c.sessionID = val
+		}
+	}
+
+	if c.sessionID == "" {
+		return &Error{Status: http.StatusBadGateway, Code: CodeUpstream,
+			Err: fmt.Errorf("%w (cookie %s)", errNoSessionCookie, c.cookieName)}
+	}
+	return nil
+}
+
+// extractTokens parses ST1/ST2 from forwardUrl like "index.html?ST1=abc,ST2=def"
+func (c *Client) extractTokens(forwardURL string) {
+	parts := strings.SplitN(forwardURL, "?", 2)
+	if len(parts) < 2 {
+		return
+	}
+
+	for _, param := range strings.Split(parts[1], ",") {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "ST1":
+			c.st1 = kv[1]
+			c.newAuth = true
+		case "ST2":
+			c.st2 = kv[1]
+			c.newAuth = true
+		}
+	}
+}
+
+// Get fetches data from the iDRAC6 API. keys are comma-separated data type names
+// like "pwState", "temperatures", "sysDesc".
+func (c *Client) Get(keys ...string) ([]byte, error) {
+	return c.GetContext(context.Background(), keys...)
+}
+
+// GetContext is Get with a context for cancellation and tracing.
+func (c *Client) GetContext(ctx context.Context, keys ...string) (data []byte, err error) {
+	ctx, span := c.startSpan(ctx, "idrac.Get", attribute.StringSlice("idrac.keys", keys))
+	defer func() { endSpan(span, err) }()
+
+	return c.doWithRetry(ctx, c.timeouts.Query, func(ctx context.Context) (*http.Response, error) {
+		reqURL := fmt.Sprintf("%s/data?get=%s", c.baseURL, strings.Join(keys, ","))
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.applySession(req)
+		return c.http.Do(req)
+	})
+}
+
+// Set sends a set command to the iDRAC6 API (e.g., "pwState:1" for power on).
+func (c *Client) Set(param string) ([]byte, error) {
+	return c.SetContext(context.Background(), param)
+}
+
+// SetContext is Set with a context for cancellation and tracing. Only the
+// parameter name is recorded on the span, never its value.
+func (c *Client) SetContext(ctx context.Context, param string) (data []byte, err error) {
+	name, _, _ := strings.Cut(param, ":")
+	ctx, span := c.startSpan(ctx, "idrac.Set", attribute.String("idrac.action", name))
+	defer func() { endSpan(span, err) }()
+
+	return c.doWithRetry(ctx, c.timeouts.Action, func(ctx context.Context) (*http.Response, error) {
+		reqURL := fmt.Sprintf("%s/data?set=%s", c.baseURL, url.QueryEscape(param))
+		req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		c.applySession(req)
+		return c.http.Do(req)
+	})
+}
+
+// PostForm sends a POST with form data to the given path.
+func (c *Client) PostForm(path string, form url.Values) ([]byte, error) {
+	return c.doWithRetry(context.Background(), c.timeouts.Action, func(ctx context.Context) (*http.Response, error) {
+		req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		c.applySession(req)
+		return c.http.Do(req)
+	})
+}
+
+// applySession adds auth headers/cookies to a request.
+func (c *Client) applySession(req *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.sessionID != "" {
+		req.AddCookie(&http.Cookie{
+			Name:  c.cookieName,
+			Value: c.sessionID,
+		})
+	}
+	if c.newAuth && c.st2 != "" {
+		req.Header.Set("ST2", c.st2)
+	}
+}
+
+// doWithRetry executes a request, retrying once on 401 after re-login. A
+// 401 on the retry means the iDRAC accepted the login but not the session,
+// which is reported as notAuthorized rather than a bare status.
+// When many requests hit 401 at once only the first re-logs in; the rest
+// wait on c.mu and retry with the session it established. Each attempt,
+// body included, is bounded by timeout.
+func (c *Client) doWithRetry(ctx context.Context, timeout time.Duration, fn func(context.Context) (*http.Response, error)) ([]byte, error) {
+	c.mu.Lock()
+	gen := c.sessionGen
+	c.mu.Unlock()
+
+	resp, cancel, err := c.send(ctx, timeout, fn)
+	defer cancel()
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", transportError(describeTLSError(c.host, err)))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+
+		if at := c.unauthorizedAt.Load(); at != 0 && c.authzGrace > 0 && time.Since(time.Unix(0, at)) < c.authzGrace {
+			return nil, c.notAuthorized()
+		}
+		c.retries.Add(1)
+		trace.SpanFromContext(ctx).AddEvent("re-login after 401")
+
+		c.mu.Lock()
+		var loginErr error
+		if c.sessionGen == gen {
+			loginErr = c.login(ctx)
+		}
+		c.mu.Unlock()
+
+		if loginErr != nil {
+			return nil, fmt.Errorf("re-login after 401 failed: %w", loginErr)
+		}
+
+		var cancel context.CancelFunc
+		resp, cancel, err = c.send(ctx, timeout, fn)
+		defer cancel()
+		if err != nil {
+			return nil, fmt.Errorf("retry request failed: %w", transportError(describeTLSError(c.host, err)))
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode == http.StatusUnauthorized {
+			c.unauthorizedAt.Store(time.Now().UnixNano())
+			return nil, c.notAuthorized()
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(fmt.Errorf("unexpected status %d", resp.StatusCode), resp.StatusCode)
+	}
+	c.unauthorizedAt.Store(0)
+
+	body, err := readBody(resp)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	return body, nil
+}
+
+// send runs fn under timeout, retrying while the iDRAC answers 503, as it
+// does when too busy to serve a request. Each wait honors Retry-After but
+// lasts at least busyDelay, and the last 503 is returned once another wait
+// would run past timeout. The timeout covers every attempt and wait
+// together: each retry gets only what is left of it. The returned cancel
+// must be called after the body is read.
+func (c *Client) send(ctx context.Context, timeout time.Duration, fn func(context.Context) (*http.Response, error)) (*http.Response, context.CancelFunc, error) {
+	deadline := time.Now().Add(timeout)
+	for attempt := timeout; ; attempt = time.Until(deadline) {
+		attemptCtx, cancel := withTimeout(ctx, attempt)
+		resp, err := fn(attemptCtx)
+		if err != nil || resp.StatusCode != http.StatusServiceUnavailable || c.busyDelay <= 0 || timeout <= 0 {
+			return resp, cancel, err
+		}
+		wait := parseRetryAfter(resp.Header.Get("Retry-After"), c.busyDelay, time.Now())
+		if time.Now().Add(wait).After(deadline) {
+			return resp, cancel, nil
+		}
+		io.Copy(io.Discard, resp.Body) //nolint:errcheck
+		resp.Body.Close()
+		cancel()
+
+		c.busyRetries.Add(1)
+		trace.SpanFromContext(ctx).AddEvent("retry after 503", trace.WithAttributes(attribute.Int64("idrac.wait_ms", wait.Milliseconds())))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, func() {}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// parseRetryAfter reads a Retry-After header, either delay seconds or an
+// HTTP date, falling back to def when it is missing or unparsable. The
+// result is never less than def, so a zero or past value cannot make the
+// caller retry in a tight loop.
+func parseRetryAfter(v string, def time.Duration, now time.Time) time.Duration {
+	if v == "" {
+		return def
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return max(time.Duration(secs)*time.Second, def)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(t.Sub(now), def)
+	}
+	return def
+}
+
+// withTimeout bounds ctx by d; zero or less leaves it unbounded.
+func withTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// notAuthorized is the error for a 401 that a fresh login did not cure:
+// the credentials were accepted, so the session cookie or, on newAuth
+// firmware, the ST2 header is not reaching the iDRAC intact.
+func (c *Client) notAuthorized() error {
+	c.mu.Lock()
+	newAuth := c.newAuth
+	c.mu.Unlock()
+	hint := "the session cookie was not accepted; an OEM build may use a different cookie name"
+	if newAuth {
+		hint = "the ST2 token header was missing or not accepted (newAuth firmware)"
+	}
+	return &Error{Status: http.StatusBadGateway, Code: CodeNotAuthorized,
+		Err: fmt.Errorf("authenticated but authorized=false: %s rejected a fresh session with 401; %s", c.host, hint)}
+}
+
+// readBody reads a full response body, decompressing gzip payloads.
+// net/http only decompresses transparently when it requested compression
+// itself; some iDRAC6 firmware gzips responses unprompted, occasionally
+// without a Content-Encoding header, so also sniff the gzip magic bytes.
+func readBody(resp *http.Response) ([]byte, error) {
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	gzipped := strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") ||
+		bytes.HasPrefix(data, []byte{0x1f, 0x8b})
+	if resp.Uncompressed || !gzipped {
+		return data, nil
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decompressing gzip body: %w", err)
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// Logout terminates the iDRAC6 session.
+func (c *Client) Logout() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	req, err := http.NewRequest("GET", c.baseURL+"/data/logout", nil)
+	if err != nil {
+		return err
+	}
+	if c.sessionID != "" {
+		req.AddCookie(&http.Cookie{
+			Name:  c.cookieName,
+			Value: c.sessionID,
+		})
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	c.sessionID = ""
+	c.st1 = ""
+	c.st2 = ""
+	c.newAuth = false
+
+	return nil
+}
+
+// Stats returns a snapshot of the client's operational counters.
+func (c *Client) Stats() ClientStats {
+	return ClientStats{
+		Logins:        c.logins.Load(),
+		LoginFailures: c.loginFailures.Load(),
+		Retries:       c.retries.Load(),
+		BusyRetries:   c.busyRetries.Load(),
+	}
+}
+
+// Host returns the configured iDRAC host address.
+func (c *Client) Host() string {
+	return c.host
+}
+
+// BaseURL returns the base URL for the iDRAC API.
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
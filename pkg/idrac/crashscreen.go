@@ -0,0 +1,66 @@
+package idrac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CrashScreen reports whether the iDRAC is set up to capture the host's
+// screen when the OS hangs and whether it likely holds a capture. iDRAC6
+// takes the screenshot when the OS watchdog (Automatic System Recovery,
+// set up through Server Administrator) expires; the image itself can only
+// be viewed in the web UI, as RACADM has no command to export it.
+type CrashScreen struct {
+	// CaptureEnabled is set when the watchdog has a recovery action, the
+	// precondition for a capture.
+	CaptureEnabled bool   `json:"captureEnabled"`
+	RecoveryAction string `json:"recoveryAction,omitempty"`
+	// Available is set when the SEL records a watchdog expiry, after which
+	// the iDRAC keeps the screen until it is cleared or replaced.
+	Available bool `json:"available"`
+	// LastCrash is the timestamp of the newest watchdog SEL entry.
+	LastCrash string `json:"lastCrash,omitempty"`
+}
+
+// GetCrashScreen reads the watchdog settings from "racadm getsysinfo -w".
+// Available and LastCrash come from the SEL; see LastWatchdogEvent.
+func (a *Admin) GetCrashScreen() (*CrashScreen, error) {
+	output, err := a.racadm.Run("getsysinfo", "-w")
+	if err != nil {
+		return nil, fmt.Errorf("getting watchdog info: %w", err)
+	}
+	return parseCrashScreen(output), nil
+}
+
+// parseCrashScreen reads the Watchdog Information section of getsysinfo
+// output. Firmware that prints no heading for "-w" leaves the keys
+// outside any section.
+func parseCrashScreen(output string) *CrashScreen {
+	sections := parseSysInfoSections(output)
+	action := sections["Watchdog Information"]["Recovery Action"]
+	if action == "" {
+		action = sections[""]["Recovery Action"]
+	}
+	return &CrashScreen{
+		CaptureEnabled: action != "" && !strings.EqualFold(action, "None"),
+		RecoveryAction: action,
+	}
+}
+
+// ClearCrashScreen runs "racadm clearasrscreen", discarding the last
+// crash screen.
+func (a *Admin) ClearCrashScreen() error {
+	_, err := a.rawOutput("clearasrscreen")
+	return err
+}
+
+// LastWatchdogEvent returns the newest SEL entry about the OS watchdog
+// expiring, the event that captures a crash screen.
+func (d *SELData) LastWatchdogEvent() (SELEntry, bool) {
+	for i := len(d.Entries) - 1; i >= 0; i-- {
+		if strings.Contains(strings.ToLower(d.Entries[i].Description), "watchdog") {
+			return d.Entries[i], true
+		}
+	}
+	return SELEntry{}, false
+}
@@ -0,0 +1,62 @@
+package idrac
+
+import (
+	"errors"
+	"testing"
+
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+)
+
+const sampleWatchdogInfo = `Watchdog Information:
+Recovery Action         = Reboot
+Present countdown value = 478 seconds
+Initial countdown value = 480 seconds
+`
+
+func TestGetCrashScreen(t *testing.T) {
+	fake := &fakeRACADM{output: sampleWatchdogInfo}
+	a := &Admin{racadm: fake}
+
+	cs, err := a.GetCrashScreen()
+	if err != nil {
+		t.Fatalf("GetCrashScreen() error = %v", err)
+	}
+	if !cs.CaptureEnabled || cs.RecoveryAction != "Reboot" {
+		t.Errorf("GetCrashScreen() = %+v", cs)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "getsysinfo -w" {
+		t.Errorf("commands = %q", fake.calls)
+	}
+
+	if cs := parseCrashScreen("Recovery Action = None\n"); cs.CaptureEnabled || cs.RecoveryAction != "None" {
+		t.Errorf("no recovery action = %+v", cs)
+	}
+}
+
+func TestClearCrashScreen(t *testing.T) {
+	fake := &fakeRACADM{output: "Last crash screen cleared successfully.\n"}
+	a := &Admin{racadm: fake}
+	if err := a.ClearCrashScreen(); err != nil || len(fake.calls) != 1 || fake.calls[0] != "clearasrscreen" {
+		t.Fatalf("ClearCrashScreen() = %v, commands %q", err, fake.calls)
+	}
+
+	fake.output = "ERROR: Unable to clear the last crash screen."
+	var rerr *racadmssh.Error
+	if err := a.ClearCrashScreen(); !errors.As(err, &rerr) || rerr.Code != racadmssh.CodeCommand {
+		t.Errorf("ClearCrashScreen() on failure error = %v, want a RACADM command error", err)
+	}
+}
+
+func TestLastWatchdogEvent(t *testing.T) {
+	sel := &SELData{Entries: []SELEntry{
+		{ID: "1", Timestamp: "Mon Jan 05 2015 10:00:00", Description: "The watchdog timer reset the system."},
+		{ID: "2", Timestamp: "Mon Jan 05 2015 11:00:00", Description: "Power supply redundancy is lost."},
+	}}
+	e, ok := sel.LastWatchdogEvent()
+	if !ok || e.ID != "1" {
+		t.Errorf("LastWatchdogEvent() = %+v, %v; want entry 1", e, ok)
+	}
+	if _, ok := (&SELData{}).LastWatchdogEvent(); ok {
+		t.Error("empty SEL should have no watchdog event")
+	}
+}
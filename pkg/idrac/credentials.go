@@ -0,0 +1,86 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// ErrBadCredentials is returned when the iDRAC rejects the username or
+// password, as opposed to failing for a network or session-limit reason.
+var ErrBadCredentials error = &Error{Status: http.StatusBadGateway, Code: CodeAuthFailed, Err: errors.New("bad credentials")}
+
+// Credential is a username/password pair to try when logging in. Label
+// identifies it in logs so passwords never need to be printed.
+type Credential struct {
+	Label    string `json:"label,omitempty" yaml:"label,omitempty"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password"`
+}
+
+// name returns the credential's label, or its position if unlabeled.
+func (cr Credential) name(i int) string {
+	if cr.Label != "" {
+		return cr.Label
+	}
+	if i == 0 {
+		return "primary"
+	}
+	return fmt.Sprintf("#%d", i)
+}
+
+// WithCredentials adds fallback credentials, tried in order after the ones
+// given to NewClient when the iDRAC rejects them. The first credential that
+// works is remembered and tried first on later logins. Each rejected
+// attempt counts toward the iDRAC's failed-login lockout, so keep the list
+// short.
+func WithCredentials(creds ...Credential) Option {
+	return func(c *Client) {
+		c.fallbacks = append(c.fallbacks, creds...)
+	}
+}
+
+// ActiveCredential returns the credential used for the current session,
+// which differs from the one given to NewClient after a fallback login.
+func (c *Client) ActiveCredential() Credential {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.credIndex == 0 {
+		return Credential{Label: "primary", Username: c.username, Password: c.password}
+	}
+	return c.fallbacks[c.credIndex-1]
+}
+
+// credential returns candidate i: 0 is the NewClient credential, the rest
+// are fallbacks.
+func (c *Client) credential(i int) Credential {
+	if i == 0 {
+		return Credential{Username: c.username, Password: c.password}
+	}
+	return c.fallbacks[i-1]
+}
+
+// loginAny logs in with the remembered credential, then the others in
+// order, moving on only when a credential is rejected. Callers hold c.mu.
+func (c *Client) loginAny(ctx context.Context) error {
+	n := len(c.fallbacks) + 1
+	var err error
+	for k := 0; k < n; k++ {
+		i := (c.credIndex + k) % n
+		cred := c.credential(i)
+		err = c.doLogin(ctx, cred.Username, cred.Password)
+		if err == nil {
+			if i != c.credIndex {
+				log.Printf("iDRAC %s: logged in with credential %s", c.host, cred.name(i))
+				c.credIndex = i
+			}
+			return nil
+		}
+		if !errors.Is(err, ErrBadCredentials) {
+			return err
+		}
+	}
+	return fmt.Errorf("all %d credentials rejected: %w", n, err)
+}
@@ -0,0 +1,75 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// credentialIDRAC accepts only the given password and counts login POSTs.
+func credentialIDRAC(t *testing.T, password string, attempts *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start.html":
+			http.SetCookie(w, &http.Cookie{Name: "_appwebSessionId_", Value: "sess"})
+		case "/data/login":
+			*attempts++
+			r.ParseForm()
+			result := 1
+			if r.PostForm.Get("password") == password {
+				result = 0
+			}
+			fmt.Fprintf(w, `<root><authResult>%d</authResult><forwardUrl>index.html</forwardUrl></root>`, result)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLogin_CredentialFallback(t *testing.T) {
+	var attempts int
+	server := credentialIDRAC(t, "rotated", &attempts)
+
+	c := NewClient("localhost", "root", "calvin", WithCredentials(
+		Credential{Label: "old", Username: "root", Password: "old-rotation"},
+		Credential{Label: "current", Username: "root", Password: "rotated"},
+	))
+	c.baseURL = server.URL
+	c.http = server.Client()
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("login attempts = %d, want 3", attempts)
+	}
+	if got := c.ActiveCredential().Label; got != "current" {
+		t.Errorf("ActiveCredential() = %q, want current", got)
+	}
+
+	// The working credential is remembered for re-logins.
+	attempts = 0
+	if err := c.Login(); err != nil || attempts != 1 {
+		t.Errorf("re-login: error = %v, attempts = %d; want 1 attempt", err, attempts)
+	}
+}
+
+func TestLogin_AllCredentialsRejected(t *testing.T) {
+	var attempts int
+	server := credentialIDRAC(t, "unknown", &attempts)
+
+	c := NewClient("localhost", "root", "calvin", WithCredentials(Credential{Username: "root", Password: "other"}))
+	c.baseURL = server.URL
+	c.http = server.Client()
+
+	err := c.Login()
+	if !errors.Is(err, ErrBadCredentials) {
+		t.Errorf("Login() error = %v, want ErrBadCredentials", err)
+	}
+	if attempts != 2 {
+		t.Errorf("login attempts = %d, want 2", attempts)
+	}
+}
@@ -0,0 +1,62 @@
+package idrac
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// redactPatterns scrub session IDs, ST1/ST2 tokens, and credentials from
+// logged URLs and bodies. Each replacement keeps the key and drops the value.
+var redactPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`(ST[12]=)[^,&"'<\s]+`), "${1}REDACTED"},
+	{regexp.MustCompile(`(_appwebSessionId_=)[^;&"'<\s]+`), "${1}REDACTED"},
+	// Credentials as query or form values ("password=x", URL-encoded
+	// "password%3Dx") and data API set params ("cfgUserAdminPassword:x").
+	{regexp.MustCompile(`(?i)((?:user|password|passwd|pwd)\w*(?:=|%3D|:))[^&"'<\s,]+`), "${1}REDACTED"},
+	{regexp.MustCompile(`(?i)(<(?:ST[12]|\w*(?:password|passwd|pwd)\w*)>)[^<]*`), "${1}REDACTED"},
+}
+
+// redact removes secrets from s.
+func redact(s string) string {
+	for _, p := range redactPatterns {
+		s = p.re.ReplaceAllString(s, p.repl)
+	}
+	return s
+}
+
+// DebugLog returns a Middleware that logs every iDRAC request URL and raw
+// response body via logf, with session cookies, ST1/ST2 tokens, and
+// credentials redacted. Request bodies (the login form) and headers are
+// never logged. Intended for triaging parser bugs from real payloads.
+func DebugLog(logf func(format string, args ...interface{})) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			reqURL := redact(req.URL.String())
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				logf("idrac debug: %s %s: %v", req.Method, reqURL, err)
+				return nil, err
+			}
+
+			body, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if readErr != nil {
+				logf("idrac debug: %s %s -> %d (reading body: %v)", req.Method, reqURL, resp.StatusCode, readErr)
+				return resp, nil
+			}
+
+			if bytes.HasPrefix(body, []byte{0x1f, 0x8b}) {
+				logf("idrac debug: %s %s -> %d (gzip body, %d bytes)", req.Method, reqURL, resp.StatusCode, len(body))
+			} else {
+				logf("idrac debug: %s %s -> %d\n%s", req.Method, reqURL, resp.StatusCode, redact(string(body)))
+			}
+			return resp, nil
+		})
+	}
+}
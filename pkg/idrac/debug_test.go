@@ -0,0 +1,66 @@
+package idrac
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		in, leak string
+	}{
+		{"index.html?ST1=abc123,ST2=def456", "def456"},
+		{"_appwebSessionId_=sess-9f8e; path=/", "sess-9f8e"},
+		{"user=root&password=calvin", "calvin"},
+		{"/data?set=cfgUserAdminPassword:hunter2", "hunter2"},
+		{"/data?set=password%3Dhunter2", "hunter2"},
+		{"<root><userPassword>hunter2</userPassword></root>", "hunter2"},
+		{"<root><forwardUrl>index.html?ST1=tok1,ST2=tok2</forwardUrl></root>", "tok2"},
+	}
+	for _, tt := range tests {
+		got := redact(tt.in)
+		if strings.Contains(got, tt.leak) {
+			t.Errorf("redact(%q) = %q, still contains %q", tt.in, got, tt.leak)
+		}
+		if !strings.Contains(got, "REDACTED") {
+			t.Errorf("redact(%q) = %q, want REDACTED marker", tt.in, got)
+		}
+	}
+}
+
+func TestDebugLog(t *testing.T) {
+	server := mockIDRAC(t, 0, "index.html?ST1=secret1,ST2=secret2")
+	defer server.Close()
+
+	var logged []string
+	logf := func(format string, args ...interface{}) {
+		logged = append(logged, fmt.Sprintf(format, args...))
+	}
+
+	c := NewClient("localhost", "root", "calvin")
+	c.baseURL = server.URL
+	c.http = server.Client()
+	c.http.Transport = DebugLog(logf)(c.http.Transport)
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	data, err := c.Get("pwState")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if !strings.Contains(string(data), "<pwState>1</pwState>") {
+		t.Errorf("body consumed by debug logging: %q", data)
+	}
+
+	all := strings.Join(logged, "\n")
+	for _, secret := range []string{"secret2", "sess-1", "calvin"} {
+		if strings.Contains(all, secret) {
+			t.Errorf("debug log leaked %q:\n%s", secret, all)
+		}
+	}
+	if !strings.Contains(all, "/data?get=pwState") || !strings.Contains(all, "<pwState>1</pwState>") {
+		t.Errorf("debug log missing request URL or body:\n%s", all)
+	}
+}
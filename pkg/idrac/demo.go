@@ -0,0 +1,217 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrDemo is returned for operations demo mode does not simulate: raw
+// web API requests and everything over RACADM or IPMI.
+var ErrDemo error = &Error{Status: http.StatusNotImplemented, Code: CodeDemo, Err: errors.New("not available in demo mode, which simulates the web API's power, sensor, event log, and system info data only")}
+
+// DemoClient is a HostClient that serves synthetic data shaped like an
+// iDRAC6 in a PowerEdge R710, for UI development and demos. It never
+// touches the network. Power actions and SEL clears change its state, and
+// readings drift slowly so dashboards have something to plot. Hosts with
+// different names get different service tags and baselines.
+type DemoClient struct {
+	name string
+	// seed varies the baselines between hosts.
+	seed  uint32
+	start time.Time
+
+	mu      sync.Mutex
+	power   PowerState
+	sel     []SELEntry
+	nextSEL int
+}
+
+// NewDemoClient returns a powered-on DemoClient for the host name, with a
+// short event log.
+func NewDemoClient(name string) *DemoClient {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	d := &DemoClient{
+		name:  name,
+		seed:  h.Sum32(),
+		start: time.Now(),
+		power: PowerOn,
+	}
+	boot := d.start.Add(-36 * time.Hour)
+	d.logAt(boot, "Normal", "Log cleared.")
+	d.logAt(boot.Add(10*time.Hour), "Warning", "The system inlet temperature is greater than the upper warning threshold.")
+	d.logAt(boot.Add(10*time.Hour+4*time.Minute), "Normal", "The system inlet temperature is within range.")
+	if d.seed%3 == 0 {
+		d.logAt(boot.Add(20*time.Hour), "Critical", "Power supply 2 input is lost. Power supply redundancy is lost.")
+		d.logAt(boot.Add(20*time.Hour+90*time.Second), "Normal", "Power supply 2 input is restored. Power supply redundancy is regained.")
+	}
+	if d.seed%4 == 1 {
+		d.logAt(boot.Add(30*time.Hour), "Warning", "Correctable memory error rate exceeded for DIMM_A3.")
+	}
+	return d
+}
+
+// logAt appends a SEL entry with the iDRAC's severity wording ("Normal",
+// "Warning", "Critical"). Called with d.mu held or before d is shared.
+func (d *DemoClient) logAt(t time.Time, severity, description string) {
+	d.nextSEL++
+	d.sel = append(d.sel, SELEntry{
+		ID:          strconv.Itoa(d.nextSEL),
+		Timestamp:   t.Format("2006-01-02 15:04:05"),
+		Severity:    severity,
+		Description: description,
+	})
+}
+
+// drift returns base plus a slow wave of the given amplitude, phased per
+// host and per sensor so readings do not move in lockstep.
+func (d *DemoClient) drift(base, amplitude float64, sensor int) float64 {
+	phase := float64((d.seed>>uint(sensor%16))%628) / 100
+	t := time.Since(d.start).Seconds() / 90
+	return math.Round((base+amplitude*math.Sin(t+phase))*10) / 10
+}
+
+// on reports whether the demo host is powered on.
+func (d *DemoClient) on() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.power == PowerOn
+}
+
+// GetPowerState returns the simulated power state.
+func (d *DemoClient) GetPowerState() (*PowerStatus, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &PowerStatus{State: d.power, Status: d.power.String()}, nil
+}
+
+// SetPower applies a power action to the simulated host. Restarts and
+// resets leave it on; NMI is logged like a real diagnostic interrupt.
+func (d *DemoClient) SetPower(action PowerAction) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch action {
+	case ActionPowerOff, ActionGracefulShut:
+		d.power = PowerOff
+	case ActionPowerOn, ActionPowerRestart, ActionPowerReset:
+		d.power = PowerOn
+	case ActionNMI:
+		d.logAt(time.Now(), "Critical", "An NMI was asserted by the iDRAC.")
+	default:
+		return fmt.Errorf("setting power state: unknown action %d", action)
+	}
+	return nil
+}
+
+// SetPowerByName applies a power action by name.
+func (d *DemoClient) SetPowerByName(name string) error {
+	action, ok := ValidPowerActions[name]
+	if !ok {
+		return fmt.Errorf("unknown power action: %q (valid: off, on, restart, reset, nmi, shutdown)", name)
+	}
+	return d.SetPower(action)
+}
+
+// GetSensors returns simulated R710 temperatures, fans, and voltages.
+// A powered-off host cools to ambient with its fans idling.
+func (d *DemoClient) GetSensors() (*SensorData, error) {
+	on := d.on()
+	ambient := d.drift(21+float64(d.seed%4), 1, 1)
+	cpu := func(i int) float64 {
+		if !on {
+			return ambient + 2
+		}
+		return d.drift(44+float64((d.seed>>uint(i))%8), 4, 2+i)
+	}
+	fan := func(i int) float64 {
+		if !on {
+			return 1800
+		}
+		return math.Round(d.drift(3600+float64((d.seed>>uint(i))%5)*120, 240, 4+i))
+	}
+	temp := func(name string, v, warn, crit, minWarn, minCrit float64) SensorReading {
+		return SensorReading{Name: name, Value: v, Unit: "degrees C", Status: "normal", Warning: warn, Critical: crit, MinWarning: minWarn, MinCritical: minCrit}
+	}
+
+	data := &SensorData{
+		Temperatures: []SensorReading{
+			temp("System Board Ambient Temp", ambient, 42, 47, 8, 3),
+			temp("CPU1 Temp", cpu(0), 85, 90, 0, 0),
+			temp("CPU2 Temp", cpu(1), 85, 90, 0, 0),
+		},
+	}
+	for i := 1; i <= 5; i++ {
+		data.Fans = append(data.Fans, SensorReading{Name: fmt.Sprintf("System Board FAN %d RPM", i), Value: fan(i), Unit: "RPM", Status: "normal", MinCritical: 720})
+	}
+	data.Voltages = []SensorReading{
+		{Name: "CPU1 VCORE PG", Value: 1, Status: "normal"},
+		{Name: "CPU2 VCORE PG", Value: 1, Status: "normal"},
+		{Name: "System Board 3.3V PG", Value: 1, Status: "normal"},
+		{Name: "PS1 Voltage 1", Value: d.drift(230, 2, 9), Unit: "Volts", Status: "normal", Warning: 264, Critical: 270, MinWarning: 190, MinCritical: 180},
+		{Name: "PS2 Voltage 2", Value: d.drift(230, 2, 10), Unit: "Volts", Status: "normal", Warning: 264, Critical: 270, MinWarning: 190, MinCritical: 180},
+	}
+	for _, group := range []struct {
+		readings []SensorReading
+		kind     string
+	}{{data.Temperatures, "temperatures"}, {data.Fans, "fans"}, {data.Voltages, "voltages"}} {
+		low, high := HealthBounds(group.kind)
+		for i := range group.readings {
+			group.readings[i].Health = SensorHealth(group.readings[i], low, high)
+		}
+	}
+	return data, nil
+}
+
+// GetSystemInfo returns the simulated host's identity. The service tag
+// is derived from the host name.
+func (d *DemoClient) GetSystemInfo() (*SystemInfo, error) {
+	const tagChars = "0123456789BCDFGHJKLMNPQRSTVWXYZ"
+	tag := make([]byte, 7)
+	for i, n := 0, d.seed; i < len(tag); i, n = i+1, n/uint32(len(tagChars)) {
+		tag[i] = tagChars[n%uint32(len(tagChars))]
+	}
+	return &SystemInfo{
+		Hostname:    d.name,
+		Model:       "PowerEdge R710",
+		ServiceTag:  string(tag),
+		BIOSVersion: "6.6.0",
+		FWVersion:   "2.92 (Build 05)",
+		LCCVersion:  "1.7.5.4",
+		OSName:      "Ubuntu 22.04.4 LTS",
+	}, nil
+}
+
+// GetSEL returns the simulated event log.
+func (d *DemoClient) GetSEL() (*SELData, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	entries := append([]SELEntry{}, d.sel...)
+	return &SELData{Entries: entries, TotalCount: len(entries)}, nil
+}
+
+// ClearSEL empties the event log, leaving the entry a real BMC records
+// for the clear.
+func (d *DemoClient) ClearSEL() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sel = nil
+	d.logAt(time.Now(), "Normal", "Log cleared.")
+	return nil
+}
+
+// GetContext fails with ErrDemo: there is no raw web API to query.
+func (d *DemoClient) GetContext(context.Context, ...string) ([]byte, error) {
+	return nil, ErrDemo
+}
+
+// SetContext fails with ErrDemo.
+func (d *DemoClient) SetContext(context.Context, string) ([]byte, error) {
+	return nil, ErrDemo
+}
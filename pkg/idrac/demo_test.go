@@ -0,0 +1,50 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDemoClient(t *testing.T) {
+	d := NewDemoClient("r710-a")
+
+	if status, _ := d.GetPowerState(); status.State != PowerOn {
+		t.Fatalf("initial power = %v, want on", status.State)
+	}
+	if err := d.SetPowerByName("off"); err != nil {
+		t.Fatalf("SetPowerByName(off) error = %v", err)
+	}
+	if status, _ := d.GetPowerState(); status.State != PowerOff {
+		t.Errorf("power after off = %v, want off", status.State)
+	}
+	if err := d.SetPowerByName("hibernate"); err == nil {
+		t.Error("SetPowerByName(hibernate) should fail")
+	}
+
+	sensors, _ := d.GetSensors()
+	if len(sensors.Temperatures) == 0 || len(sensors.Fans) == 0 || len(sensors.Voltages) == 0 {
+		t.Fatalf("GetSensors() = %+v, want every type", sensors)
+	}
+	if h := sensors.Temperatures[0].Health; h != SeverityNormal {
+		t.Errorf("ambient health = %q, want %s", h, SeverityNormal)
+	}
+
+	info, _ := d.GetSystemInfo()
+	other, _ := NewDemoClient("r710-b").GetSystemInfo()
+	if info.Hostname != "r710-a" || len(info.ServiceTag) != 7 || info.ServiceTag == other.ServiceTag {
+		t.Errorf("GetSystemInfo() = %+v, want a per-host service tag (other %s)", info, other.ServiceTag)
+	}
+
+	if err := d.ClearSEL(); err != nil {
+		t.Fatal(err)
+	}
+	if sel, _ := d.GetSEL(); sel.TotalCount != 1 || sel.Entries[0].Description != "Log cleared." {
+		t.Errorf("GetSEL() after clear = %+v, want only the clear record", sel)
+	}
+
+	var ierr *Error
+	if _, err := d.GetContext(context.Background(), "pwState"); !errors.As(err, &ierr) || ierr.Code != CodeDemo {
+		t.Errorf("GetContext() error = %v, want %s", err, CodeDemo)
+	}
+}
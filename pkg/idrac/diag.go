@@ -0,0 +1,37 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+)
+
+// RACDump runs "racadm racdump", the controller's own diagnostic dump
+// (system and controller info, sessions, sensors, crash data), and
+// returns its raw output for a support case.
+func (a *Admin) RACDump() (string, error) {
+	return a.rawOutput("racdump")
+}
+
+// GetRACLog runs "racadm getraclog" and returns the controller's own
+// event log (logins, configuration changes) as raw text.
+func (a *Admin) GetRACLog() (string, error) {
+	return a.rawOutput("getraclog")
+}
+
+// rawOutput runs a RACADM command whose output is kept verbatim. Output
+// starting with "ERROR", as firmware without the command prints, is an
+// error.
+func (a *Admin) rawOutput(cmd string) (string, error) {
+	output, err := a.racadm.Run(cmd)
+	if err != nil {
+		return "", fmt.Errorf("running %s: %w", cmd, err)
+	}
+	if strings.HasPrefix(strings.TrimSpace(output), "ERROR") {
+		return "", fmt.Errorf("running %s: %w", cmd, &racadmssh.Error{Status: http.StatusBadGateway, Code: racadmssh.CodeCommand, Err: errors.New(strings.TrimSpace(output))})
+	}
+	return output, nil
+}
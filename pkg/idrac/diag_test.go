@@ -0,0 +1,31 @@
+package idrac
+
+import (
+	"errors"
+	"testing"
+
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+)
+
+func TestRACDump(t *testing.T) {
+	fake := &fakeRACADM{output: "===== General System Information =====\nRAC Date/Time = Tue Jan 1 00:00:00 2030\n"}
+	a := &Admin{racadm: fake}
+
+	out, err := a.RACDump()
+	if err != nil || out != fake.output {
+		t.Fatalf("RACDump() = %q, %v", out, err)
+	}
+	if _, err := a.GetRACLog(); err != nil {
+		t.Fatalf("GetRACLog() error = %v", err)
+	}
+	if len(fake.calls) != 2 || fake.calls[0] != "racdump" || fake.calls[1] != "getraclog" {
+		t.Errorf("commands = %q", fake.calls)
+	}
+
+	fake.output = "ERROR: Invalid subcommand specified."
+	_, err = a.RACDump()
+	var rerr *racadmssh.Error
+	if !errors.As(err, &rerr) || rerr.Code != racadmssh.CodeCommand {
+		t.Errorf("RACDump() on unsupported firmware error = %v, want a RACADM command error", err)
+	}
+}
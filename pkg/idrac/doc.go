@@ -0,0 +1,38 @@
+// Package idrac is a client for the Dell iDRAC6 web API (XML over HTTPS)
+// and the RACADM operations the web API does not expose.
+//
+// The stable API surface is the web API Client and its result types:
+//
+//   - NewClient and its Options (TLS, middleware, tracing, login form)
+//   - Client.Login, Client.Logout, Client.Get, Client.Set and their
+//     Context variants for raw access to /data
+//   - Client.GetPowerState, Client.SetPower, Client.SetPowerByName (PowerStatus)
+//   - Client.GetSensors, Client.GetTemperatures (SensorData, SensorReading)
+//   - Client.GetSystemInfo (SystemInfo)
+//   - Client.GetSEL, Client.ClearSEL (SELData, SELEntry)
+//
+// Log in once; the Client re-logs in transparently when the session
+// expires:
+//
+//	client := idrac.NewClient("192.168.1.172", "root", "calvin")
+//	if err := client.Login(); err != nil {
+//		log.Fatal(err)
+//	}
+//	defer client.Logout()
+//
+//	status, err := client.GetPowerState()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(status.Status) // "on", "off", or "unknown"
+//
+// iDRAC6 allows only a few concurrent sessions, so programs managing many
+// hosts should share logged-in clients through a Pool. Admin (RACADM over
+// SSH) and VirtualMedia cover sessions, configuration groups, license
+// detection, power statistics, large SEL reads, and image mounting; their
+// APIs may still change.
+//
+// HostClient is the subset of Client an application needs to read and
+// control a host. DemoClient implements it with synthetic data and no
+// network, for demos and for testing code written against HostClient.
+package idrac
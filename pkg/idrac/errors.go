@@ -0,0 +1,61 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// Error codes carried by Error.
+const (
+	CodeAuthFailed         = "idrac_auth_failed"
+	CodeSessionLimit       = "idrac_session_limit"
+	CodeNotAuthorized      = "idrac_not_authorized"
+	CodeTimeout            = "idrac_timeout"
+	CodeUnreachable        = "idrac_unreachable"
+	CodeUpstream           = "idrac_error"
+	CodeNotFound           = "not_found"
+	CodeRequiresEnterprise = "requires_enterprise"
+	CodeVirtualMediaBusy   = "virtual_media_busy"
+	CodeDemo               = "demo_unsupported"
+)
+
+// Error is a classified iDRAC failure. Status is the HTTP status an API
+// built on this package should answer with and Code a stable identifier,
+// so callers can tell a rejected login from a timeout without parsing
+// messages. Find one in a wrapped chain with errors.As.
+type Error struct {
+	Status int
+	Code   string
+	Err    error
+}
+
+func (e *Error) Error() string { return e.Err.Error() }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// HTTPStatus returns the suggested HTTP status.
+func (e *Error) HTTPStatus() int { return e.Status }
+
+// ErrorCode returns the machine-readable error code.
+func (e *Error) ErrorCode() string { return e.Code }
+
+// transportError classifies a failed HTTP round trip as a timeout or an
+// unreachable controller. The iDRAC is upstream of any API using this
+// client, hence gateway statuses.
+func transportError(err error) error {
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr) && netErr.Timeout() {
+		return &Error{Status: http.StatusGatewayTimeout, Code: CodeTimeout, Err: err}
+	}
+	return &Error{Status: http.StatusBadGateway, Code: CodeUnreachable, Err: err}
+}
+
+// statusError classifies an unexpected HTTP status from the iDRAC.
+func statusError(err error, status int) error {
+	if status == http.StatusNotFound {
+		return &Error{Status: http.StatusNotFound, Code: CodeNotFound, Err: err}
+	}
+	return &Error{Status: http.StatusBadGateway, Code: CodeUpstream, Err: err}
+}
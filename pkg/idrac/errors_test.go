@@ -0,0 +1,38 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestLogin_ErrorClassification(t *testing.T) {
+	for authResult, want := range map[int]string{1: CodeAuthFailed, 5: CodeSessionLimit, 4: CodeUpstream} {
+		server := mockIDRAC(t, authResult, "")
+		c := NewClient("localhost", "root", "calvin")
+		c.baseURL = server.URL
+		c.http = server.Client()
+
+		var e *Error
+		err := c.Login()
+		if !errors.As(err, &e) || e.Code != want {
+			t.Errorf("authResult=%d: Login() = %v, want code %s", authResult, err, want)
+		}
+		server.Close()
+	}
+}
+
+func TestTransportError(t *testing.T) {
+	var e *Error
+	if err := transportError(context.DeadlineExceeded); !errors.As(err, &e) || e.Status != http.StatusGatewayTimeout {
+		t.Errorf("deadline: %v", err)
+	}
+	if err := transportError(errors.New("connection refused")); !errors.As(err, &e) || e.Code != CodeUnreachable {
+		t.Errorf("refused: %v", err)
+	}
+	if err := fmt.Errorf("login: %w", ErrBadCredentials); !errors.Is(err, ErrBadCredentials) || !errors.As(err, &e) || e.Code != CodeAuthFailed {
+		t.Errorf("wrapped ErrBadCredentials = %v, want it classified as %s", err, CodeAuthFailed)
+	}
+}
@@ -0,0 +1,157 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FirmwareJobState is the normalized state of a firmware update job.
+type FirmwareJobState string
+
+const (
+	FirmwareJobPending   FirmwareJobState = "pending"
+	FirmwareJobRunning   FirmwareJobState = "running"
+	FirmwareJobCompleted FirmwareJobState = "completed"
+	FirmwareJobFailed    FirmwareJobState = "failed"
+	FirmwareJobUnknown   FirmwareJobState = "unknown"
+)
+
+// FirmwareJob is the status of a firmware update.
+type FirmwareJob struct {
+	ID              string           `json:"id"`
+	State           FirmwareJobState `json:"state"`
+	PercentComplete int              `json:"percentComplete"`
+	Message         string           `json:"message,omitempty"`
+}
+
+var (
+	jobIDPattern   = regexp.MustCompile(`JID_[0-9]+`)
+	percentPattern = regexp.MustCompile(`(\d{1,3})\s*%`)
+)
+
+// ValidateFirmwareURL checks that imageURL is a tftp, ftp, http or https
+// URL with a host and path that RACADM can take as an argument.
+func ValidateFirmwareURL(imageURL string) error {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return fmt.Errorf("invalid image URL: %w", err)
+	}
+	switch u.Scheme {
+	case "tftp", "ftp", "http", "https":
+	default:
+		return errors.New("image URL must use tftp, ftp, http or https")
+	}
+	if u.Hostname() == "" || strings.Trim(u.Path, "/") == "" {
+		return errors.New("image URL must include a host and image path")
+	}
+	if !racadmSafe(imageURL) {
+		return errors.New("image URL may only contain printable ASCII without spaces, quotes, or backslashes")
+	}
+	return nil
+}
+
+// StartFirmwareUpdate starts a firmware update from imageURL. TFTP images
+// use iDRAC6's "fwupdate -g -u", the only remote source its firmware
+// supports; other schemes use "update -f", which needs Lifecycle
+// Controller support. The returned job ID is the "JID_..." that
+// "update" reports, or "" for fwupdate, whose status is not per job.
+//
+// An iDRAC firmware update resets the iDRAC when it finishes, dropping all
+// sessions for several minutes; the host keeps running. Component updates
+// queued as jobs apply on the host's next reboot.
+func (a *Admin) StartFirmwareUpdate(imageURL string) (string, error) {
+	if err := ValidateFirmwareURL(imageURL); err != nil {
+		return "", err
+	}
+	u, _ := url.Parse(imageURL)
+
+	var output string
+	var err error
+	if u.Scheme == "tftp" {
+		output, err = a.racadm.Run("fwupdate", "-g", "-u", "-a", u.Hostname(), "-d", strings.TrimPrefix(u.Path, "/"))
+	} else {
+		output, err = a.racadm.Run("update", "-f", imageURL)
+	}
+	if err != nil {
+		return "", fmt.Errorf("starting firmware update: %w", err)
+	}
+	return jobIDPattern.FindString(output), nil
+}
+
+// FirmwareJobStatus reports the status of a firmware update. "JID_..."
+// IDs are looked up in the job queue; any other ID reads the fwupdate
+// status, of which there is only one per iDRAC.
+func (a *Admin) FirmwareJobStatus(jobID string) (*FirmwareJob, error) {
+	if strings.HasPrefix(jobID, "JID_") {
+		output, err := a.racadm.Run("jobqueue", "view", "-i", jobID)
+		if err != nil {
+			return nil, fmt.Errorf("getting job %s: %w", jobID, err)
+		}
+		job := parseJobQueueView(output)
+		job.ID = jobID
+		return job, nil
+	}
+
+	output, err := a.racadm.Run("fwupdate", "-s")
+	if err != nil {
+		return nil, fmt.Errorf("getting firmware update status: %w", err)
+	}
+	job := parseFWUpdateStatus(output)
+	job.ID = jobID
+	return job, nil
+}
+
+// parseJobQueueView parses "racadm jobqueue view -i" output, whose values
+// are often wrapped in brackets: "Percent Complete=[100]".
+func parseJobQueueView(output string) *FirmwareJob {
+	props := parseConfigGroup(output)
+	unwrap := func(s string) string {
+		return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"))
+	}
+
+	job := &FirmwareJob{Message: unwrap(props["Message"])}
+	job.PercentComplete, _ = strconv.Atoi(unwrap(props["Percent Complete"]))
+
+	switch status := strings.ToLower(props["Status"]); {
+	case strings.Contains(status, "fail"):
+		job.State = FirmwareJobFailed
+	case strings.Contains(status, "complete"):
+		job.State = FirmwareJobCompleted
+	case strings.Contains(status, "running"), strings.Contains(status, "progress"), strings.Contains(status, "download"):
+		job.State = FirmwareJobRunning
+	case strings.Contains(status, "schedul"), strings.Contains(status, "new"), strings.Contains(status, "pending"):
+		job.State = FirmwareJobPending
+	default:
+		job.State = FirmwareJobUnknown
+	}
+	return job
+}
+
+// parseFWUpdateStatus parses "racadm fwupdate -s" output such as
+// "Firmware update in progress [34% complete]".
+func parseFWUpdateStatus(output string) *FirmwareJob {
+	msg := strings.TrimSpace(output)
+	job := &FirmwareJob{Message: msg}
+	if m := percentPattern.FindStringSubmatch(msg); m != nil {
+		job.PercentComplete, _ = strconv.Atoi(m[1])
+	}
+
+	switch lower := strings.ToLower(msg); {
+	case strings.Contains(lower, "fail"), strings.Contains(lower, "error"):
+		job.State = FirmwareJobFailed
+	case strings.Contains(lower, "complete") && !strings.Contains(lower, "progress"):
+		job.State = FirmwareJobCompleted
+		job.PercentComplete = 100
+	case strings.Contains(lower, "progress"), strings.Contains(lower, "transfer"), strings.Contains(lower, "updating"):
+		job.State = FirmwareJobRunning
+	case strings.Contains(lower, "ready"):
+		job.State = FirmwareJobPending
+	default:
+		job.State = FirmwareJobUnknown
+	}
+	return job
+}
@@ -0,0 +1,79 @@
+package idrac
+
+import "testing"
+
+func TestValidateFirmwareURL(t *testing.T) {
+	for _, u := range []string{"tftp://10.0.0.5/idrac/firmimg.d6", "https://repo.example/fw/firmimg.d6"} {
+		if err := ValidateFirmwareURL(u); err != nil {
+			t.Errorf("ValidateFirmwareURL(%q) error = %v", u, err)
+		}
+	}
+	for _, u := range []string{"", "file:///tmp/firmimg.d6", "tftp://10.0.0.5/", "http:///firmimg.d6", "tftp://10.0.0.5/a b"} {
+		if err := ValidateFirmwareURL(u); err == nil {
+			t.Errorf("ValidateFirmwareURL(%q) should fail", u)
+		}
+	}
+}
+
+func TestStartFirmwareUpdate(t *testing.T) {
+	fake := &fakeRACADM{output: "Firmware update started"}
+	a := &Admin{racadm: fake}
+
+	id, err := a.StartFirmwareUpdate("tftp://10.0.0.5/idrac/firmimg.d6")
+	if err != nil || id != "" {
+		t.Fatalf("StartFirmwareUpdate(tftp) = %q, %v", id, err)
+	}
+	if got := fake.calls[0]; got != "fwupdate -g -u -a 10.0.0.5 -d idrac/firmimg.d6" {
+		t.Errorf("command = %q", got)
+	}
+
+	fake.output = "RAC987: Update initiated.\nJID_123456789012"
+	id, err = a.StartFirmwareUpdate("https://repo.example/fw/BIOS.exe")
+	if err != nil || id != "JID_123456789012" {
+		t.Errorf("StartFirmwareUpdate(https) = %q, %v", id, err)
+	}
+	if got := fake.calls[1]; got != "update -f https://repo.example/fw/BIOS.exe" {
+		t.Errorf("command = %q", got)
+	}
+}
+
+func TestFirmwareJobStatus(t *testing.T) {
+	fake := &fakeRACADM{output: `---------------------------- JOB -------------------------
+[Job ID=JID_123456789012]
+Job Name=Firmware Update: BIOS
+Status=Running
+Message=[PR20: Job in progress.]
+Percent Complete=[42]
+----------------------------------------------------------`}
+	a := &Admin{racadm: fake}
+
+	job, err := a.FirmwareJobStatus("JID_123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.State != FirmwareJobRunning || job.PercentComplete != 42 || job.Message != "PR20: Job in progress." {
+		t.Errorf("job = %+v", job)
+	}
+
+	tests := map[string]FirmwareJobState{
+		"Firmware update in progress [34% complete]": FirmwareJobRunning,
+		"Firmware update completed successfully.":    FirmwareJobCompleted,
+		"Firmware update failed: invalid image":      FirmwareJobFailed,
+		"Ready for firmware update":                  FirmwareJobPending,
+	}
+	fake.output = "Firmware update in progress [34% complete]"
+	if job, _ := a.FirmwareJobStatus("fwupdate-1"); job.PercentComplete != 34 {
+		t.Errorf("PercentComplete = %d, want 34", job.PercentComplete)
+	}
+	if last := fake.calls[len(fake.calls)-1]; last != "fwupdate -s" {
+		t.Errorf("command = %q", last)
+	}
+
+	for out, want := range tests {
+		fake.output = out
+		job, err := a.FirmwareJobStatus("fwupdate-1")
+		if err != nil || job.State != want || job.ID != "fwupdate-1" {
+			t.Errorf("%q: job = %+v, %v; want %s", out, job, err, want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package idrac
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RACSysInfo is the parsed output of "racadm getsysinfo", which covers the
+// controller itself (network settings, firmware build) as well as the
+// host. Sections keeps every key=value pair by section heading, including
+// ones without a dedicated field (watchdog, chassis, embedded NIC MACs).
+type RACSysInfo struct {
+	FirmwareVersion    string   `json:"firmwareVersion,omitempty"`
+	FirmwareBuild      string   `json:"firmwareBuild,omitempty"`
+	LastFirmwareUpdate string   `json:"lastFirmwareUpdate,omitempty"`
+	HardwareVersion    string   `json:"hardwareVersion,omitempty"`
+	MACAddress         string   `json:"macAddress,omitempty"`
+	DNSRacName         string   `json:"dnsRacName,omitempty"`
+	DNSDomain          string   `json:"dnsDomain,omitempty"`
+	IPAddress          string   `json:"ipAddress,omitempty"`
+	Gateway            string   `json:"gateway,omitempty"`
+	Netmask            string   `json:"netmask,omitempty"`
+	DHCPEnabled        bool     `json:"dhcpEnabled"`
+	DNSServers         []string `json:"dnsServers,omitempty"`
+	IPv6Address        string   `json:"ipv6Address,omitempty"`
+	SystemModel        string   `json:"systemModel,omitempty"`
+	SystemRevision     string   `json:"systemRevision,omitempty"`
+	BIOSVersion        string   `json:"biosVersion,omitempty"`
+	ServiceTag         string   `json:"serviceTag,omitempty"`
+	ExpressServiceCode string   `json:"expressServiceCode,omitempty"`
+	HostName           string   `json:"hostName,omitempty"`
+	OSName             string   `json:"osName,omitempty"`
+	PowerStatus        string   `json:"powerStatus,omitempty"`
+
+	Sections map[string]map[string]string `json:"sections"`
+}
+
+// ExtendedSystemInfo merges the web API's SystemInfo with RACADM's
+// getsysinfo. RAC is nil when RACADM was unavailable.
+type ExtendedSystemInfo struct {
+	SystemInfo
+	RAC *RACSysInfo `json:"rac,omitempty"`
+}
+
+// GetSysInfo runs "racadm getsysinfo" and parses its output.
+func (a *Admin) GetSysInfo() (*RACSysInfo, error) {
+	output, err := a.racadm.Run("getsysinfo")
+	if err != nil {
+		return nil, fmt.Errorf("getting sysinfo: %w", err)
+	}
+	return parseSysInfo(output), nil
+}
+
+// parseSysInfoSections splits getsysinfo output into sections. A heading
+// is a line ending in ":" without "="; keys before any heading go under "".
+// Indentation and the column padding around "=" are ignored, and a key
+// repeated within a section keeps its first value.
+func parseSysInfoSections(output string) map[string]map[string]string {
+	sections := make(map[string]map[string]string)
+	current := ""
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			if strings.HasSuffix(line, ":") {
+				current = strings.TrimSpace(strings.TrimSuffix(line, ":"))
+			}
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if sections[current] == nil {
+			sections[current] = make(map[string]string)
+		}
+		if _, dup := sections[current][key]; !dup {
+			sections[current][key] = strings.TrimSpace(val)
+		}
+	}
+	return sections
+}
+
+func parseSysInfo(output string) *RACSysInfo {
+	sections := parseSysInfoSections(output)
+	get := func(section, key string) string {
+		return sections[section][key]
+	}
+
+	info := &RACSysInfo{
+		FirmwareVersion:    get("RAC Information", "Firmware Version"),
+		FirmwareBuild:      get("RAC Information", "Firmware Build"),
+		LastFirmwareUpdate: get("RAC Information", "Last Firmware Update"),
+		HardwareVersion:    get("RAC Information", "Hardware Version"),
+		MACAddress:         get("RAC Information", "MAC Address"),
+		DNSRacName:         get("Common settings", "DNS RAC Name"),
+		DNSDomain:          get("Common settings", "Current DNS Domain"),
+		IPAddress:          get("IPv4 settings", "Current IP Address"),
+		Gateway:            get("IPv4 settings", "Current IP Gateway"),
+		Netmask:            get("IPv4 settings", "Current IP Netmask"),
+		DHCPEnabled:        get("IPv4 settings", "DHCP Enabled") == "1",
+		IPv6Address:        get("IPv6 settings", "Current IP Address 1"),
+		SystemModel:        get("System Information", "System Model"),
+		SystemRevision:     get("System Information", "System Revision"),
+		BIOSVersion:        get("System Information", "System BIOS Version"),
+		ServiceTag:         get("System Information", "Service Tag"),
+		ExpressServiceCode: get("System Information", "Express Svc Code"),
+		HostName:           get("System Information", "Host Name"),
+		OSName:             get("System Information", "OS Name"),
+		PowerStatus:        get("System Information", "Power Status"),
+		Sections:           sections,
+	}
+	for _, key := range []string{"Current DNS Server 1", "Current DNS Server 2"} {
+		if dns := get("IPv4 settings", key); dns != "" && dns != "0.0.0.0" {
+			info.DNSServers = append(info.DNSServers, dns)
+		}
+	}
+	return info
+}
+
+// MergeSystemInfo combines web and RACADM system info. Web values win;
+// RACADM fills any the web API left blank. Either argument may be nil.
+func MergeSystemInfo(web *SystemInfo, rac *RACSysInfo) *ExtendedSystemInfo {
+	out := &ExtendedSystemInfo{RAC: rac}
+	if web != nil {
+		out.SystemInfo = *web
+	}
+	if rac == nil {
+		return out
+	}
+
+	fill := func(dst *string, src string) {
+		if *dst == "" {
+			*dst = src
+		}
+	}
+	fill(&out.Hostname, rac.HostName)
+	fill(&out.Model, rac.SystemModel)
+	fill(&out.ServiceTag, rac.ServiceTag)
+	fill(&out.BIOSVersion, rac.BIOSVersion)
+	fill(&out.OSName, rac.OSName)
+	if out.FWVersion == "" && rac.FirmwareVersion != "" {
+		out.FWVersion = rac.FirmwareVersion
+		if rac.FirmwareBuild != "" {
+			out.FWVersion += " (Build " + rac.FirmwareBuild + ")"
+		}
+	}
+	return out
+}
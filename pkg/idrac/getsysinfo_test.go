@@ -0,0 +1,100 @@
+package idrac
+
+import "testing"
+
+const sampleGetSysInfo = `
+RAC Information:
+RAC Date/Time           = Thu Dec 11 21:28:16 2014
+
+Firmware Version        = 2.92
+Firmware Build          = 05
+Last Firmware Update    = 12/01/2014 19:39:21
+Hardware Version        = 0.01
+MAC Address             = 00:24:e8:3e:4b:c3
+
+Common settings:
+Register DNS RAC Name   = 0
+DNS RAC Name            = idrac-r710
+Current DNS Domain      = lab.example
+
+IPv4 settings:
+Enabled                 = 1
+Current IP Address      = 192.168.1.172
+Current IP Gateway      = 192.168.1.1
+Current IP Netmask      = 255.255.255.0
+DHCP Enabled            = 0
+Current DNS Server 1    = 192.168.1.53
+Current DNS Server 2    = 0.0.0.0
+
+IPv6 settings:
+Enabled                 = 0
+Current IP Address 1    = ::
+Current IP Gateway      = ::
+
+System Information:
+  System Model            = PowerEdge R710
+  System Revision         = II
+  System BIOS Version     = 6.4.0
+  Service Tag             = ABC1234
+  Host Name               = r710
+  Power Status            = ON
+
+Embedded NIC MAC Addresses:
+NIC1 Ethernet           = 00:24:e8:3e:4b:bb
+`
+
+func TestGetSysInfo(t *testing.T) {
+	fake := &fakeRACADM{output: sampleGetSysInfo}
+	info, err := (&Admin{racadm: fake}).GetSysInfo()
+	if err != nil {
+		t.Fatalf("GetSysInfo() error = %v", err)
+	}
+
+	checks := map[string][2]string{
+		"firmware":   {info.FirmwareVersion, "2.92"},
+		"build":      {info.FirmwareBuild, "05"},
+		"mac":        {info.MACAddress, "00:24:e8:3e:4b:c3"},
+		"dns name":   {info.DNSRacName, "idrac-r710"},
+		"ip":         {info.IPAddress, "192.168.1.172"},
+		"gateway":    {info.Gateway, "192.168.1.1"},
+		"ipv6":       {info.IPv6Address, "::"},
+		"model":      {info.SystemModel, "PowerEdge R710"},
+		"serviceTag": {info.ServiceTag, "ABC1234"},
+		"nic1":       {info.Sections["Embedded NIC MAC Addresses"]["NIC1 Ethernet"], "00:24:e8:3e:4b:bb"},
+	}
+	for name, c := range checks {
+		if c[0] != c[1] {
+			t.Errorf("%s = %q, want %q", name, c[0], c[1])
+		}
+	}
+	if info.DHCPEnabled {
+		t.Error("DHCPEnabled = true, want false")
+	}
+	if len(info.DNSServers) != 1 || info.DNSServers[0] != "192.168.1.53" {
+		t.Errorf("DNSServers = %v, want [192.168.1.53]", info.DNSServers)
+	}
+	// "Enabled" appears in both IP sections with different values.
+	if info.Sections["IPv4 settings"]["Enabled"] != "1" || info.Sections["IPv6 settings"]["Enabled"] != "0" {
+		t.Errorf("section-scoped Enabled values lost: %v", info.Sections)
+	}
+}
+
+func TestMergeSystemInfo(t *testing.T) {
+	web := &SystemInfo{Hostname: "web-name", Model: "PowerEdge R710"}
+	rac := parseSysInfo(sampleGetSysInfo)
+
+	got := MergeSystemInfo(web, rac)
+	if got.Hostname != "web-name" {
+		t.Errorf("Hostname = %q, web value should win", got.Hostname)
+	}
+	if got.ServiceTag != "ABC1234" || got.BIOSVersion != "6.4.0" {
+		t.Errorf("blank web fields not filled: %+v", got.SystemInfo)
+	}
+	if got.FWVersion != "2.92 (Build 05)" {
+		t.Errorf("FWVersion = %q", got.FWVersion)
+	}
+
+	if got := MergeSystemInfo(web, nil); got.RAC != nil || got.Hostname != "web-name" {
+		t.Errorf("MergeSystemInfo(web, nil) = %+v", got)
+	}
+}
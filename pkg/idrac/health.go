@@ -0,0 +1,65 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// HealthState classifies how an iDRAC responded to a health check.
+type HealthState string
+
+const (
+	// HealthUp means the iDRAC answered within the latency threshold.
+	HealthUp HealthState = "up"
+	// HealthSlow means the iDRAC answered, but slower than the threshold.
+	HealthSlow HealthState = "slow"
+	// HealthDown means the iDRAC did not answer.
+	HealthDown HealthState = "down"
+)
+
+// Health is the result of a health check.
+type Health struct {
+	State     HealthState `json:"state"`
+	LatencyMs float64     `json:"latencyMs"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// Ping fetches the iDRAC's login page without authenticating, so it costs
+// no session, and returns the round-trip latency. Any HTTP response counts
+// as reachable.
+func (c *Client) Ping(ctx context.Context) (time.Duration, error) {
+	if c.hostErr != nil {
+		return 0, c.hostErr
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/start.html", nil)
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return time.Since(start), fmt.Errorf("ping %s: %w", c.host, err)
+	}
+	io.Copy(io.Discard, resp.Body) //nolint:errcheck
+	resp.Body.Close()
+	return time.Since(start), nil
+}
+
+// CheckHealth pings the iDRAC and classifies it as up, slow (answered but
+// slower than slowAfter), or down (no answer before ctx expires).
+func (c *Client) CheckHealth(ctx context.Context, slowAfter time.Duration) Health {
+	latency, err := c.Ping(ctx)
+	h := Health{State: HealthUp, LatencyMs: float64(latency) / float64(time.Millisecond)}
+	switch {
+	case err != nil:
+		h.State = HealthDown
+		h.Error = err.Error()
+	case latency > slowAfter:
+		h.State = HealthSlow
+	}
+	return h
+}
@@ -0,0 +1,37 @@
+package idrac
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCheckHealth(t *testing.T) {
+	server := mockIDRAC(t, 0, "index.html")
+	defer server.Close()
+	client := NewClient("localhost", "root", "calvin")
+	client.baseURL = server.URL
+	client.http = server.Client()
+
+	if h := client.CheckHealth(context.Background(), time.Minute); h.State != HealthUp || h.Error != "" {
+		t.Errorf("CheckHealth() = %+v, want up", h)
+	}
+	if h := client.CheckHealth(context.Background(), 0); h.State != HealthSlow {
+		t.Errorf("CheckHealth() with zero threshold = %+v, want slow", h)
+	}
+
+	hung := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer hung.Close()
+	client.baseURL = hung.URL
+	client.http = hung.Client()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if h := client.CheckHealth(ctx, time.Minute); h.State != HealthDown || h.Error == "" {
+		t.Errorf("CheckHealth() against a hung server = %+v, want down with an error", h)
+	}
+}
@@ -0,0 +1,67 @@
+package idrac
+
+import "context"
+
+// HostClient is the web API surface an application reads and controls a
+// host through. *Client implements it against a real iDRAC and
+// *DemoClient with synthetic data, so code written against HostClient
+// runs, and can be tested, without a controller.
+type HostClient interface {
+	GetPowerState() (*PowerStatus, error)
+	SetPower(action PowerAction) error
+	SetPowerByName(name string) error
+	GetSensors() (*SensorData, error)
+	GetSystemInfo() (*SystemInfo, error)
+	GetSEL() (*SELData, error)
+	ClearSEL() error
+	// GetContext and SetContext are raw "data?get=" and "data?set="
+	// requests, returning the response body.
+	GetContext(ctx context.Context, keys ...string) ([]byte, error)
+	SetContext(ctx context.Context, param string) ([]byte, error)
+}
+
+var (
+	_ HostClient = (*Client)(nil)
+	_ HostClient = (*DemoClient)(nil)
+)
+
+// boundClient is a Client whose calls all run under a fixed context.
+type boundClient struct {
+	ctx context.Context
+	*Client
+}
+
+func (b boundClient) GetPowerState() (*PowerStatus, error) {
+	return b.Client.GetPowerStateContext(b.ctx)
+}
+
+func (b boundClient) SetPower(action PowerAction) error {
+	return b.Client.SetPowerContext(b.ctx, action)
+}
+
+func (b boundClient) SetPowerByName(name string) error {
+	return b.Client.SetPowerByNameContext(b.ctx, name)
+}
+
+func (b boundClient) GetSensors() (*SensorData, error) {
+	return b.Client.GetSensorsContext(b.ctx)
+}
+
+func (b boundClient) GetSystemInfo() (*SystemInfo, error) {
+	return b.Client.GetSystemInfoContext(b.ctx)
+}
+
+func (b boundClient) GetSEL() (*SELData, error) {
+	return b.Client.GetSELContext(b.ctx)
+}
+
+func (b boundClient) ClearSEL() error {
+	return b.Client.ClearSELContext(b.ctx)
+}
+
+// WithContext returns a HostClient whose requests run under ctx: they are
+// cancelled with it and traced as children of its span. c itself is
+// unchanged.
+func (c *Client) WithContext(ctx context.Context) HostClient {
+	return boundClient{ctx: ctx, Client: c}
+}
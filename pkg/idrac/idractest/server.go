@@ -0,0 +1,361 @@
+// Package idractest provides a mock iDRAC6 for tests of code built on
+// package idrac. A Server speaks the two-step login (/start.html, then
+// POST /data/login), serves power, sensor, SEL, and system information
+// through /data, tracks power actions, and can inject the faults real
+// controllers produce: expired sessions, HTML instead of XML, and
+// truncated XML.
+//
+//	srv := idractest.NewServer(idractest.Options{})
+//	defer srv.Close()
+//
+//	client := idrac.NewClient(srv.Addr(), "root", "calvin")
+package idractest
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Fault is a failure injected into /data responses.
+type Fault int
+
+const (
+	// FaultNone serves normal responses.
+	FaultNone Fault = iota
+	// FaultUnauthorized answers 401, as when the session has expired.
+	FaultUnauthorized
+	// FaultHTML answers 200 with an HTML login page instead of XML, as
+	// some firmware does for an invalid session.
+	FaultHTML
+	// FaultMalformedXML answers 200 with truncated XML.
+	FaultMalformedXML
+	// FaultServerError answers 500.
+	FaultServerError
+)
+
+// DefaultSessionCookie is the session cookie a Server sets unless
+// Options.SessionCookie overrides it.
+const DefaultSessionCookie = "_appwebSessionId_"
+
+// Options configures a Server. The zero value is a powered-on R710 that
+// accepts any credentials.
+type Options struct {
+	// Username and Password are the accepted credentials. If Username is
+	// empty, any credentials are accepted.
+	Username string
+	Password string
+	// AuthResult, if non-zero, fails every login with this authResult
+	// (1 bad credentials, 5 session limit, ...).
+	AuthResult int
+	// ForwardURL is the login response's forwardUrl; default "index.html".
+	// With "?ST1=a,ST2=b" the server also requires the ST2 header on /data,
+	// like newAuth firmware.
+	ForwardURL string
+	// SessionCookie is the session cookie name; default _appwebSessionId_.
+	SessionCookie string
+	// PowerOff starts the host powered off.
+	PowerOff bool
+	// SEL is the raw event log, one "id|timestamp|severity|description"
+	// record per entry.
+	SEL []string
+	// Data overrides /data?get= responses: the inner XML of the element
+	// for each key, e.g. {"fwVersion": "2.92 (Build 05)"}. Keys not built
+	// in are answered from here or with an empty element.
+	Data map[string]string
+}
+
+// Server is a mock iDRAC6 over TLS. Its certificate is self-signed, like
+// a real iDRAC6's, which idrac.Client accepts by default.
+type Server struct {
+	*httptest.Server
+
+	opts   Options
+	cookie string
+	st2    string
+
+	mu         sync.Mutex
+	powerOn    bool
+	sel        []string
+	sessions   map[string]bool
+	nextID     int
+	fault      Fault
+	faultCount int
+	logins     int
+	logouts    int
+	sets       []string
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer(opts Options) *Server {
+	s := &Server{
+		opts:     opts,
+		cookie:   opts.SessionCookie,
+		powerOn:  !opts.PowerOff,
+		sel:      append([]string(nil), opts.SEL...),
+		sessions: make(map[string]bool),
+	}
+	if s.cookie == "" {
+		s.cookie = DefaultSessionCookie
+	}
+	if s.opts.ForwardURL == "" {
+		s.opts.ForwardURL = "index.html"
+	}
+	if _, query, ok := strings.Cut(s.opts.ForwardURL, "?"); ok {
+		for _, param := range strings.Split(query, ",") {
+			if v, ok := strings.CutPrefix(param, "ST2="); ok {
+				s.st2 = v
+			}
+		}
+	}
+	s.Server = httptest.NewTLSServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Addr returns the server's host:port, for idrac.NewClient.
+func (s *Server) Addr() string {
+	return strings.TrimPrefix(s.URL, "https://")
+}
+
+// SetFault makes the next n /data requests fail with f; n <= 0 keeps
+// failing until SetFault(FaultNone, 0).
+func (s *Server) SetFault(f Fault, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fault, s.faultCount = f, n
+}
+
+// ExpireSessions invalidates every session, so the next /data request
+// gets 401 and the client must log in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.sessions)
+}
+
+// PowerOn reports the simulated power state.
+func (s *Server) PowerOn() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.powerOn
+}
+
+// SetPowerOn changes the simulated power state, e.g. to mimic an OS
+// shutting down on its own.
+func (s *Server) SetPowerOn(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.powerOn = on
+}
+
+// Logins returns the number of successful logins.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Logouts returns the number of logouts.
+func (s *Server) Logouts() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logouts
+}
+
+// Sets returns the /data?set= commands received, in order.
+func (s *Server) Sets() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.sets...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/start.html":
+		s.mu.Lock()
+		s.nextID++
+		id := fmt.Sprintf("sess-%d", s.nextID)
+		s.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: s.cookie, Value: id})
+		fmt.Fprint(w, `<html><body>start</body></html>`)
+	case "/data/login":
+		s.login(w, r)
+	case "/data/logout":
+		if c, err := r.Cookie(s.cookie); err == nil {
+			s.mu.Lock()
+			delete(s.sessions, c.Value)
+			s.logouts++
+			s.mu.Unlock()
+		}
+		fmt.Fprint(w, `<root><status>ok</status></root>`)
+	case "/data":
+		s.data(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	c, err := r.Cookie(s.cookie)
+	if r.Method != http.MethodPost || err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	result := s.opts.AuthResult
+	if result == 0 && s.opts.Username != "" {
+		if err := r.ParseForm(); err != nil ||
+			r.PostForm.Get("user") != s.opts.Username || r.PostForm.Get("password") != s.opts.Password {
+			result = 1
+		}
+	}
+	if result == 0 {
+		s.mu.Lock()
+		s.sessions[c.Value] = true
+		s.logins++
+		s.mu.Unlock()
+	}
+	fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<root><authResult>%d</authResult><forwardUrl>%s</forwardUrl><errorMsg></errorMsg></root>`,
+		result, html.EscapeString(s.opts.ForwardURL))
+}
+
+func (s *Server) data(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := r.Cookie(s.cookie)
+	if err != nil || !s.sessions[c.Value] || s.st2 != "" && r.Header.Get("ST2") != s.st2 {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if s.fault != FaultNone {
+		fault := s.fault
+		if s.faultCount > 0 {
+			if s.faultCount--; s.faultCount == 0 {
+				s.fault = FaultNone
+			}
+		}
+		switch fault {
+		case FaultUnauthorized:
+			w.WriteHeader(http.StatusUnauthorized)
+		case FaultHTML:
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><head><title>Login</title></head><body>Session expired</body></html>`)
+		case FaultMalformedXML:
+			fmt.Fprint(w, `<?xml version="1.0"?><root><pwState>1</pw`)
+		case FaultServerError:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	q := r.URL.Query()
+	if set := q.Get("set"); set != "" {
+		s.set(set)
+		fmt.Fprint(w, `<root><status>ok</status></root>`)
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(`<root>`)
+	for _, key := range strings.Split(q.Get("get"), ",") {
+		if key == "" {
+			continue
+		}
+		b.WriteString(s.element(key))
+	}
+	b.WriteString(`</root>`)
+	fmt.Fprint(w, b.String())
+}
+
+// set applies a /data?set= command. Called with s.mu held.
+func (s *Server) set(cmd string) {
+	s.sets = append(s.sets, cmd)
+	key, val, _ := strings.Cut(cmd, ":")
+	switch key {
+	case "pwState":
+		switch n, _ := strconv.Atoi(val); n {
+		case 0, 5: // off, graceful shutdown
+			s.powerOn = false
+		case 1, 2, 3: // on, restart, reset
+			s.powerOn = true
+		}
+	case "selClr":
+		s.sel = nil
+	}
+}
+
+// element returns the XML answering a /data?get= key. Sensor types are
+// answered with a bare <sensortype> block, as iDRAC6 does. Called with
+// s.mu held.
+func (s *Server) element(key string) string {
+	v := s.value(key)
+	if strings.HasPrefix(v, "<sensortype>") {
+		return v
+	}
+	return fmt.Sprintf("<%s>%s</%s>", key, v, key)
+}
+
+// value returns the inner XML for a /data?get= key. Called with s.mu held.
+func (s *Server) value(key string) string {
+	if v, ok := s.opts.Data[key]; ok {
+		return v
+	}
+	switch key {
+	case "pwState":
+		if s.powerOn {
+			return "1"
+		}
+		return "0"
+	case "sel":
+		return html.EscapeString(strings.Join(s.sel, "\n"))
+	case "temperatures":
+		return sensorXML(1,
+			sensor{"System Board Ambient Temp", "23", "degrees C", "8", "42", "3", "47"},
+			sensor{"CPU1 Temp", "45", "degrees C", "", "85", "", "90"})
+	case "fans":
+		return sensorXML(4,
+			sensor{"FAN 1 RPM", "3600", "RPM", "", "", "720", ""},
+			sensor{"FAN 2 RPM", "3480", "RPM", "", "", "720", ""})
+	case "voltages":
+		return sensorXML(2,
+			sensor{"CPU1 VCORE PG", "1", "", "", "", "", ""},
+			sensor{"System Board 3.3V PG", "1", "", "", "", "", ""})
+	}
+	return defaultData[key]
+}
+
+// defaultData answers the system information keys.
+var defaultData = map[string]string{
+	"hostName":     "r710-test",
+	"sysDesc":      "PowerEdge R710",
+	"sysRev":       "II",
+	"biosVer":      "6.6.0",
+	"fwVersion":    "1.99 (Build 4)",
+	"LCCfwVersion": "1.5.5.27",
+	"osName":       "Ubuntu 22.04",
+	"svcTag":       "ABC1234",
+}
+
+type sensor struct {
+	name, reading, units, minWarning, maxWarning, minFailure, maxFailure string
+}
+
+// sensorXML renders sensors in the iDRAC6 threshold sensor layout.
+func sensorXML(id int, sensors ...sensor) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<sensortype><sensorid>%d</sensorid><thresholdSensorList>", id)
+	for _, s := range sensors {
+		fmt.Fprintf(&b, "<sensor><sensorStatus>Normal</sensorStatus><name>%s</name><reading>%s</reading><units>%s</units>"+
+			"<minWarning>%s</minWarning><maxWarning>%s</maxWarning><minFailure>%s</minFailure><maxFailure>%s</maxFailure></sensor>",
+			s.name, s.reading, s.units, s.minWarning, s.maxWarning, s.minFailure, s.maxFailure)
+	}
+	b.WriteString("</thresholdSensorList></sensortype>")
+	return b.String()
+}
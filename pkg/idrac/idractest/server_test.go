@@ -0,0 +1,82 @@
+package idractest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac"
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+func TestServer(t *testing.T) {
+	srv := idractest.NewServer(idractest.Options{
+		Username: "root",
+		Password: "calvin",
+		SEL:      []string{"1|2024-01-01 12:00:00|Normal|System Boot"},
+	})
+	defer srv.Close()
+
+	c := idrac.NewClient(srv.Addr(), "root", "calvin")
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if got := c.FirmwareVersion(); got != "1.99 (Build 4)" {
+		t.Errorf("FirmwareVersion() = %q", got)
+	}
+
+	sensors, err := c.GetSensors()
+	if err != nil || len(sensors.Temperatures) != 2 || sensors.Temperatures[0].Value != 23 || len(sensors.Fans) != 2 {
+		t.Errorf("GetSensors() = %+v, %v", sensors, err)
+	}
+	sel, err := c.GetSEL()
+	if err != nil || len(sel.Entries) != 1 || sel.Entries[0].Description != "System Boot" {
+		t.Errorf("GetSEL() = %+v, %v", sel, err)
+	}
+
+	if err := c.SetPowerByName("off"); err != nil {
+		t.Fatal(err)
+	}
+	if status, err := c.GetPowerState(); err != nil || status.State != idrac.PowerOff || srv.PowerOn() {
+		t.Errorf("power after off = %+v, %v", status, err)
+	}
+	if sets := srv.Sets(); len(sets) != 1 || sets[0] != "pwState:0" {
+		t.Errorf("Sets() = %v", sets)
+	}
+
+	// An expired session costs one re-login.
+	srv.ExpireSessions()
+	if _, err := c.GetPowerState(); err != nil || srv.Logins() != 2 {
+		t.Errorf("after expiry: err = %v, logins = %d", err, srv.Logins())
+	}
+
+	bad := idrac.NewClient(srv.Addr(), "root", "wrong")
+	if err := bad.Login(); !errors.Is(err, idrac.ErrBadCredentials) {
+		t.Errorf("Login() with a bad password = %v", err)
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	srv := idractest.NewServer(idractest.Options{})
+	defer srv.Close()
+	c := idrac.NewClient(srv.Addr(), "root", "calvin")
+	if err := c.Login(); err != nil {
+		t.Fatal(err)
+	}
+
+	srv.SetFault(idractest.FaultMalformedXML, 1)
+	if _, err := c.GetPowerState(); err == nil {
+		t.Error("GetPowerState() should fail on malformed XML")
+	}
+	srv.SetFault(idractest.FaultHTML, 1)
+	if _, err := c.GetPowerState(); err == nil {
+		t.Error("GetPowerState() should fail on an HTML body")
+	}
+	if _, err := c.GetPowerState(); err != nil {
+		t.Errorf("GetPowerState() after the faults = %v", err)
+	}
+
+	srv.SetFault(idractest.FaultUnauthorized, 0)
+	if _, err := c.GetPowerState(); err == nil {
+		t.Error("GetPowerState() should fail while every request is 401")
+	}
+}
@@ -0,0 +1,81 @@
+package idrac
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IntrusionState is the chassis intrusion switch state.
+type IntrusionState string
+
+const (
+	IntrusionClosed  IntrusionState = "closed"
+	IntrusionOpen    IntrusionState = "open"
+	IntrusionUnknown IntrusionState = "unknown"
+)
+
+// Intrusion is the chassis intrusion sensor reading. LastChanged is the
+// timestamp of the newest intrusion entry in the SEL, as the iDRAC reports
+// it, when one is known.
+type Intrusion struct {
+	State       IntrusionState `json:"state"`
+	Sensor      string         `json:"sensor,omitempty"`
+	LastChanged string         `json:"lastChanged,omitempty"`
+}
+
+// GetIntrusion reads the chassis intrusion sensor from "racadm
+// getsensorinfo". Systems without an intrusion switch report unknown.
+func (a *Admin) GetIntrusion() (*Intrusion, error) {
+	output, err := a.racadm.Run("getsensorinfo")
+	if err != nil {
+		return nil, fmt.Errorf("getting sensor info: %w", err)
+	}
+	return parseIntrusion(output), nil
+}
+
+// sensorColumns splits a getsensorinfo row on the padding between columns.
+var sensorColumns = regexp.MustCompile(`\s{2,}|\t+`)
+
+// parseIntrusion finds the INTRUSION section of getsensorinfo output and
+// classifies its first row. Firmware differs in which columns it prints
+// ("Closed", "Open", "Breach Detected", ...), so the row is matched on
+// its words rather than a fixed layout.
+func parseIntrusion(output string) *Intrusion {
+	in := &Intrusion{State: IntrusionUnknown}
+	inSection := false
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if label, typ, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(label), "Sensor Type") {
+			inSection = strings.EqualFold(strings.TrimSpace(typ), "INTRUSION")
+			continue
+		}
+		if !inSection || line == "" || strings.HasPrefix(line, "<") {
+			continue
+		}
+
+		in.Sensor = sensorColumns.Split(line, 2)[0]
+		row := strings.ToLower(line)
+		switch {
+		case strings.Contains(row, "not detected"):
+			in.State = IntrusionClosed
+		case strings.Contains(row, "open"), strings.Contains(row, "breach"), strings.Contains(row, "detected"):
+			in.State = IntrusionOpen
+		case strings.Contains(row, "closed"), strings.Contains(row, "secure"):
+			in.State = IntrusionClosed
+		}
+		break
+	}
+	return in
+}
+
+// LastIntrusionEvent returns the newest SEL entry about chassis intrusion.
+func (d *SELData) LastIntrusionEvent() (SELEntry, bool) {
+	for i := len(d.Entries) - 1; i >= 0; i-- {
+		desc := strings.ToLower(d.Entries[i].Description)
+		if strings.Contains(desc, "intrusion") || strings.Contains(desc, "chassis is open") || strings.Contains(desc, "chassis is closed") {
+			return d.Entries[i], true
+		}
+	}
+	return SELEntry{}, false
+}
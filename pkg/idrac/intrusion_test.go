@@ -0,0 +1,73 @@
+package idrac
+
+import "testing"
+
+const sampleGetSensorInfo = `
+Sensor Type : POWER
+<Sensor Name>                   <Status>         <Type>
+PS1 Status                      Present          AC
+PS2 Status                      Present          AC
+
+Sensor Type : TEMPERATURE
+<Sensor Name>                   <Status>   <Reading>  <lc>   <uc>
+System Board Ambient Temp       Ok         23 C       3C     47C
+
+Sensor Type : INTRUSION
+<Sensor Name>                   <Intrusion>      <Status>
+System Board Intrusion          Closed           Power ON
+
+Sensor Type : BATTERY
+<Sensor Name>                   <Status>         <Reading>
+System Board CMOS Battery       Ok               Present
+`
+
+func TestParseIntrusion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   IntrusionState
+	}{
+		{"closed", sampleGetSensorInfo, IntrusionClosed},
+		{"open", "Sensor Type : INTRUSION\n<Sensor Name>  <Intrusion>\nSystem Board Intrusion  Open  Power ON\n", IntrusionOpen},
+		{"breach", "Sensor Type : INTRUSION\nSystem Board Intrusion  Breach Detected\n", IntrusionOpen},
+		{"not detected", "Sensor Type : INTRUSION\nSystem Board Intrusion  Not Detected\n", IntrusionClosed},
+		{"no switch", "Sensor Type : POWER\nPS1 Status  Present  AC\n", IntrusionUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := parseIntrusion(tt.output)
+			if in.State != tt.want {
+				t.Errorf("state = %q, want %q", in.State, tt.want)
+			}
+			if tt.want != IntrusionUnknown && in.Sensor != "System Board Intrusion" {
+				t.Errorf("sensor = %q", in.Sensor)
+			}
+		})
+	}
+}
+
+func TestGetIntrusion(t *testing.T) {
+	fake := &fakeRACADM{output: sampleGetSensorInfo}
+	in, err := (&Admin{racadm: fake}).GetIntrusion()
+	if err != nil {
+		t.Fatalf("GetIntrusion() error = %v", err)
+	}
+	if in.State != IntrusionClosed || len(fake.calls) != 1 || fake.calls[0] != "getsensorinfo" {
+		t.Errorf("GetIntrusion() = %+v, calls %v", in, fake.calls)
+	}
+}
+
+func TestLastIntrusionEvent(t *testing.T) {
+	sel := &SELData{Entries: []SELEntry{
+		{ID: "1", Timestamp: "Mon Jan 05 2015 10:00:00", Description: "The chassis is open while the power is off."},
+		{ID: "2", Timestamp: "Mon Jan 05 2015 10:05:00", Description: "The chassis is closed while the power is off."},
+		{ID: "3", Timestamp: "Mon Jan 05 2015 11:00:00", Description: "Power supply redundancy is lost."},
+	}}
+	e, ok := sel.LastIntrusionEvent()
+	if !ok || e.ID != "2" {
+		t.Errorf("LastIntrusionEvent() = %+v, %v; want entry 2", e, ok)
+	}
+	if _, ok := (&SELData{}).LastIntrusionEvent(); ok {
+		t.Error("empty SEL should have no intrusion event")
+	}
+}
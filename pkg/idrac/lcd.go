@@ -0,0 +1,68 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxLCDMessageLen is the longest user string cfgLcdUserDefinedString
+// holds; the front panel scrolls anything wider than its display.
+const maxLCDMessageLen = 62
+
+// lcdUserDefined is the cfgLcdConfiguration value that shows the user
+// string rather than the model name, service tag, or another preset.
+const lcdUserDefined = "0"
+
+// LCD is the front-panel LCD's user-defined message. Displayed reports
+// whether the panel shows it; otherwise it shows the preset selected by
+// cfgLcdConfiguration.
+type LCD struct {
+	Message   string `json:"message"`
+	Displayed bool   `json:"displayed"`
+}
+
+// ValidateLCDMessage checks that msg fits the LCD user string and can be
+// passed to RACADM inside double quotes: printable ASCII without quotes,
+// backslashes, or backticks.
+func ValidateLCDMessage(msg string) error {
+	if msg == "" || len(msg) > maxLCDMessageLen {
+		return fmt.Errorf("LCD message must be 1-%d characters", maxLCDMessageLen)
+	}
+	for _, r := range msg {
+		if r < ' ' || r > '~' || strings.ContainsRune(`"'\`+"`", r) {
+			return errors.New("LCD message may only contain printable ASCII without quotes, backslashes, or backticks")
+		}
+	}
+	return nil
+}
+
+// GetLCDMessage returns the front-panel LCD's user string (cfgLcdInfo)
+// and whether it is the one displayed. Servers without an LCD, such as
+// blades, report the group's defaults.
+func (a *Admin) GetLCDMessage() (*LCD, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgLcdInfo")
+	if err != nil {
+		return nil, fmt.Errorf("getting LCD message: %w", err)
+	}
+	group := parseConfigGroup(output)
+	return &LCD{
+		Message:   group["cfgLcdUserDefinedString"],
+		Displayed: group["cfgLcdConfiguration"] == lcdUserDefined,
+	}, nil
+}
+
+// SetLCDMessage sets the LCD user string and switches the panel to show
+// it.
+func (a *Admin) SetLCDMessage(msg string) error {
+	if err := ValidateLCDMessage(msg); err != nil {
+		return err
+	}
+	if _, err := a.racadm.Run("config", "-g", "cfgLcdInfo", "-o", "cfgLcdUserDefinedString", `"`+msg+`"`); err != nil {
+		return fmt.Errorf("setting LCD message: %w", err)
+	}
+	if _, err := a.racadm.Run("config", "-g", "cfgLcdInfo", "-o", "cfgLcdConfiguration", lcdUserDefined); err != nil {
+		return fmt.Errorf("displaying LCD message: %w", err)
+	}
+	return nil
+}
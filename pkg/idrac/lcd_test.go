@@ -0,0 +1,39 @@
+package idrac
+
+import "testing"
+
+func TestValidateLCDMessage(t *testing.T) {
+	for _, msg := range []string{"MAINT", "Do not power off - J. Doe 555-0100"} {
+		if err := ValidateLCDMessage(msg); err != nil {
+			t.Errorf("ValidateLCDMessage(%q) error = %v", msg, err)
+		}
+	}
+	long := "0123456789012345678901234567890123456789012345678901234567890123"
+	for _, msg := range []string{"", long, `say "hi"`, "tab\there", "$(reboot)`"} {
+		if err := ValidateLCDMessage(msg); err == nil {
+			t.Errorf("ValidateLCDMessage(%q) should fail", msg)
+		}
+	}
+}
+
+func TestLCDMessage(t *testing.T) {
+	fake := &fakeRACADM{output: "cfgLcdConfiguration=0\ncfgLcdUserDefinedString=Rack 4 U12\n# cfgLcdQualifierWatt=0\n"}
+	a := &Admin{racadm: fake}
+
+	lcd, err := a.GetLCDMessage()
+	if err != nil || lcd.Message != "Rack 4 U12" || !lcd.Displayed {
+		t.Errorf("GetLCDMessage() = %+v, %v", lcd, err)
+	}
+
+	fake.calls = nil
+	if err := a.SetLCDMessage("In maintenance"); err != nil {
+		t.Fatalf("SetLCDMessage() error = %v", err)
+	}
+	want := []string{
+		`config -g cfgLcdInfo -o cfgLcdUserDefinedString "In maintenance"`,
+		"config -g cfgLcdInfo -o cfgLcdConfiguration 0",
+	}
+	if len(fake.calls) != len(want) || fake.calls[0] != want[0] || fake.calls[1] != want[1] {
+		t.Errorf("commands = %q, want %q", fake.calls, want)
+	}
+}
@@ -0,0 +1,66 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// License is the iDRAC6 license tier.
+type License string
+
+const (
+	LicenseEnterprise License = "enterprise"
+	LicenseExpress    License = "express"
+	LicenseUnknown    License = "unknown"
+)
+
+// ErrRequiresEnterprise is returned for features iDRAC6 Express lacks.
+var ErrRequiresEnterprise error = &Error{Status: http.StatusNotImplemented, Code: CodeRequiresEnterprise, Err: errors.New("requires iDRAC Enterprise")}
+
+// Capabilities describes the features available on an iDRAC.
+type Capabilities struct {
+	License        License `json:"license"`
+	VirtualMedia   bool    `json:"virtualMedia"`
+	VirtualConsole bool    `json:"virtualConsole"`
+}
+
+// Capabilities returns the feature set for a license. Unknown licenses are
+// treated permissively so detection failures never block a working feature.
+func (l License) Capabilities() Capabilities {
+	enterprise := l != LicenseExpress
+	return Capabilities{
+		License:        l,
+		VirtualMedia:   enterprise,
+		VirtualConsole: enterprise,
+	}
+}
+
+// DetectLicense determines whether the iDRAC is Express or Enterprise from
+// the cfgRacTuning configuration group.
+func (a *Admin) DetectLicense() (License, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgRacTuning")
+	if err != nil {
+		return LicenseUnknown, fmt.Errorf("detecting license: %w", err)
+	}
+	return parseLicense(output), nil
+}
+
+// parseLicense infers the license from getconfig output. Some firmware
+// names the tier outright; otherwise the console redirection properties,
+// which only exist on Enterprise, decide it.
+func parseLicense(output string) License {
+	lower := strings.ToLower(output)
+	switch {
+	case strings.Contains(lower, "enterprise"):
+		return LicenseEnterprise
+	case strings.Contains(lower, "express"):
+		return LicenseExpress
+	case strings.Contains(lower, "cfgractuneconredir"):
+		return LicenseEnterprise
+	case strings.Contains(lower, "cfgractune"):
+		return LicenseExpress
+	}
+	return LicenseUnknown
+}
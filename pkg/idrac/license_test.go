@@ -0,0 +1,78 @@
+package idrac
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseLicense(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   License
+	}{
+		{
+			name: "enterprise by console redirection",
+			output: `cfgRacTuneHttpPort=80
+cfgRacTuneHttpsPort=443
+cfgRacTuneConRedirPort=5900
+cfgRacTuneConRedirEnable=1`,
+			want: LicenseEnterprise,
+		},
+		{
+			name: "express without console redirection",
+			output: `cfgRacTuneHttpPort=80
+cfgRacTuneHttpsPort=443
+cfgRacTuneSshPort=22`,
+			want: LicenseExpress,
+		},
+		{
+			name:   "explicit express",
+			output: "# iDRAC6 Express\ncfgRacTuneHttpPort=80",
+			want:   LicenseExpress,
+		},
+		{
+			name:   "unrecognized output",
+			output: "ERROR: Invalid object group",
+			want:   LicenseUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLicense(tt.output); got != tt.want {
+				t.Errorf("parseLicense() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectLicense(t *testing.T) {
+	fake := &fakeRACADM{output: "cfgRacTuneConRedirEnable=1"}
+	a := &Admin{racadm: fake}
+
+	license, err := a.DetectLicense()
+	if err != nil {
+		t.Fatalf("DetectLicense() error = %v", err)
+	}
+	if license != LicenseEnterprise {
+		t.Errorf("license = %q, want enterprise", license)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "getconfig -g cfgRacTuning" {
+		t.Errorf("calls = %v, want [getconfig -g cfgRacTuning]", fake.calls)
+	}
+
+	fake.err = errors.New("ssh: connection refused")
+	if license, err := a.DetectLicense(); err == nil || license != LicenseUnknown {
+		t.Errorf("DetectLicense() = %q, %v; want unknown and an error", license, err)
+	}
+}
+
+func TestLicenseCapabilities(t *testing.T) {
+	if caps := LicenseExpress.Capabilities(); caps.VirtualMedia || caps.VirtualConsole {
+		t.Errorf("Express capabilities = %+v, want no virtual media/console", caps)
+	}
+	if caps := LicenseUnknown.Capabilities(); !caps.VirtualMedia {
+		t.Error("unknown license should not disable virtual media")
+	}
+}
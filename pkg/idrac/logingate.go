@@ -0,0 +1,89 @@
+package idrac
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// loginGate admits at most limit logins to one host at a time, in arrival
+// order, and records how long callers queued.
+type loginGate struct {
+	mu      sync.Mutex
+	limit   int
+	active  int
+	waiters []chan struct{}
+
+	waits    int64
+	waitTime time.Duration
+}
+
+// acquire takes a slot, queueing behind earlier callers while all are in
+// use. If ctx ends first it returns ctx's error without a slot.
+func (g *loginGate) acquire(ctx context.Context) error {
+	g.mu.Lock()
+	if g.active < g.limit && len(g.waiters) == 0 {
+		g.active++
+		g.mu.Unlock()
+		return nil
+	}
+	ready := make(chan struct{})
+	g.waiters = append(g.waiters, ready)
+	g.mu.Unlock()
+
+	start := time.Now()
+	select {
+	case <-ready:
+		g.recordWait(time.Since(start))
+		return nil
+	case <-ctx.Done():
+		g.mu.Lock()
+		for i, w := range g.waiters {
+			if w == ready {
+				g.waiters = append(g.waiters[:i], g.waiters[i+1:]...)
+				g.mu.Unlock()
+				g.recordWait(time.Since(start))
+				return ctx.Err()
+			}
+		}
+		g.mu.Unlock()
+		// The slot was handed over as ctx ended; pass it on.
+		g.release()
+		g.recordWait(time.Since(start))
+		return ctx.Err()
+	}
+}
+
+// release frees a slot, handing it straight to the longest waiter if any.
+func (g *loginGate) release() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.waiters) == 0 {
+		g.active--
+		return
+	}
+	next := g.waiters[0]
+	g.waiters = g.waiters[1:]
+	close(next)
+}
+
+func (g *loginGate) recordWait(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.waits++
+	g.waitTime += d
+}
+
+// LoginQueueStats reports time spent queueing for a host's login gate.
+type LoginQueueStats struct {
+	// Waits is how many callers had to queue.
+	Waits int64 `json:"waits"`
+	// WaitTime is their total time in the queue.
+	WaitTime time.Duration `json:"waitTime"`
+}
+
+func (g *loginGate) stats() LoginQueueStats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return LoginQueueStats{Waits: g.waits, WaitTime: g.waitTime}
+}
@@ -0,0 +1,123 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+// waitQueued waits until n callers are queued on g.
+func waitQueued(t *testing.T, g *loginGate, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		g.mu.Lock()
+		queued := len(g.waiters)
+		g.mu.Unlock()
+		if queued == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queued = %d, want %d", queued, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLoginGate_FIFO(t *testing.T) {
+	g := &loginGate{limit: 1}
+	if err := g.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i := 1; i <= 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.acquire(context.Background()) //nolint:errcheck
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			g.release()
+		}()
+		waitQueued(t, g, i)
+	}
+
+	g.release()
+	wg.Wait()
+	if !slices.Equal(order, []int{1, 2, 3}) {
+		t.Errorf("admission order = %v, want arrival order", order)
+	}
+	if st := g.stats(); st.Waits != 3 || st.WaitTime <= 0 {
+		t.Errorf("stats = %+v, want 3 waits", st)
+	}
+}
+
+func TestLoginGate_Cancel(t *testing.T) {
+	g := &loginGate{limit: 1}
+	g.acquire(context.Background()) //nolint:errcheck
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- g.acquire(ctx) }()
+	waitQueued(t, g, 1)
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("acquire() = %v, want context.Canceled", err)
+	}
+
+	// The cancelled waiter left the queue, so release frees the slot.
+	g.release()
+	if g.active != 0 || len(g.waiters) != 0 {
+		t.Errorf("active = %d, waiters = %d; want an idle gate", g.active, len(g.waiters))
+	}
+}
+
+func TestPool_ConcurrentGetSharesOneLogin(t *testing.T) {
+	srv := idractest.NewServer(idractest.Options{})
+	defer srv.Close()
+
+	p := NewPool()
+	target := Target{ID: "s1", Host: strings.TrimPrefix(srv.URL, "https://"), Username: "root", Password: "calvin"}
+
+	clients := make([]*Client, 8)
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c, err := p.Get(target)
+			if err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+			clients[i] = c
+		}()
+	}
+	wg.Wait()
+
+	for _, c := range clients[1:] {
+		if c != clients[0] {
+			t.Fatal("concurrent Gets should share one client")
+		}
+	}
+	if srv.Logins() != 1 || srv.Logouts() != 0 {
+		t.Errorf("logins = %d, logouts = %d; want one login and no discarded sessions", srv.Logins(), srv.Logouts())
+	}
+	if _, ok := p.LoginQueueStats()["s1"]; !ok {
+		t.Error("LoginQueueStats() should report s1")
+	}
+
+	p.Evict("s1")
+	if _, ok := p.LoginQueueStats()["s1"]; ok {
+		t.Error("Evict() should drop the login gate for s1")
+	}
+}
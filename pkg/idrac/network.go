@@ -0,0 +1,49 @@
+package idrac
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// dnsLabelRe matches a single DNS label (RFC 1123): letters, digits, and
+// hyphens, not starting or ending with a hyphen, at most 63 characters.
+var dnsLabelRe = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]{0,61}[A-Za-z0-9])?$`)
+
+// ValidateIDRACName checks that name is usable as the iDRAC's DNS name.
+func ValidateIDRACName(name string) error {
+	if !dnsLabelRe.MatchString(name) {
+		return fmt.Errorf("invalid iDRAC name %q: must be 1-63 letters, digits, or hyphens, not starting or ending with a hyphen", name)
+	}
+	return nil
+}
+
+// GetIDRACName returns the iDRAC's own DNS name (cfgDNSRacName), which
+// identifies the BMC rather than the host operating system.
+func (a *Admin) GetIDRACName() (string, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgLanNetworking", "-o", "cfgDNSRacName")
+	if err != nil {
+		return "", fmt.Errorf("getting iDRAC name: %w", err)
+	}
+	return parseConfigValue(output, "cfgDNSRacName"), nil
+}
+
+// SetIDRACName sets the iDRAC's DNS name.
+func (a *Admin) SetIDRACName(name string) error {
+	if err := ValidateIDRACName(name); err != nil {
+		return err
+	}
+	if _, err := a.racadm.Run("config", "-g", "cfgLanNetworking", "-o", "cfgDNSRacName", name); err != nil {
+		return fmt.Errorf("setting iDRAC name: %w", err)
+	}
+	return nil
+}
+
+// parseConfigValue returns a single property from "getconfig -o" output,
+// which is either the bare value or a "name=value" line.
+func parseConfigValue(output, name string) string {
+	if v, ok := parseConfigGroup(output)[name]; ok {
+		return v
+	}
+	return strings.TrimSpace(output)
+}
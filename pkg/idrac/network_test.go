@@ -0,0 +1,44 @@
+package idrac
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIDRACName(t *testing.T) {
+	for _, name := range []string{"idrac-r710", "r710", "A1", strings.Repeat("a", 63)} {
+		if err := ValidateIDRACName(name); err != nil {
+			t.Errorf("ValidateIDRACName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-r710", "r710-", "r710.lab", "r710 lab", "r_710", strings.Repeat("a", 64)} {
+		if err := ValidateIDRACName(name); err == nil {
+			t.Errorf("ValidateIDRACName(%q) should fail", name)
+		}
+	}
+}
+
+func TestIDRACName(t *testing.T) {
+	fake := &fakeRACADM{output: "idrac-r710\n"}
+	a := &Admin{racadm: fake}
+
+	name, err := a.GetIDRACName()
+	if err != nil || name != "idrac-r710" {
+		t.Errorf("GetIDRACName() = %q, %v; want idrac-r710", name, err)
+	}
+
+	fake.output = "cfgDNSRacName=idrac-r710"
+	if name, _ := a.GetIDRACName(); name != "idrac-r710" {
+		t.Errorf("GetIDRACName() with name=value output = %q, want idrac-r710", name)
+	}
+
+	if err := a.SetIDRACName("bad name"); err == nil {
+		t.Error("SetIDRACName() should reject an invalid name")
+	}
+	if err := a.SetIDRACName("idrac-r610"); err != nil {
+		t.Fatalf("SetIDRACName() error = %v", err)
+	}
+	if last := fake.calls[len(fake.calls)-1]; last != "config -g cfgLanNetworking -o cfgDNSRacName idrac-r610" {
+		t.Errorf("command = %q", last)
+	}
+}
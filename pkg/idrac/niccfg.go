@@ -0,0 +1,164 @@
+package idrac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NIC modes reported in NICConfig.Mode, from cfgNicSelection.
+const (
+	NICModeShared             = "shared"
+	NICModeSharedFailoverLOM2 = "shared-failover-lom2"
+	NICModeDedicated          = "dedicated"
+	NICModeSharedFailoverAll  = "shared-failover-all"
+)
+
+// nicModes maps cfgNicSelection values to modes.
+var nicModes = map[string]string{
+	"0": NICModeShared,
+	"1": NICModeSharedFailoverLOM2,
+	"2": NICModeDedicated,
+	"3": NICModeSharedFailoverAll,
+}
+
+// NICConfig is the iDRAC's own network port: whether it has a dedicated
+// port or shares a host LAN-on-motherboard (LOM) port, the live link, and
+// the configured VLAN and speed. Warnings flag settings known to cause
+// intermittent management connectivity.
+type NICConfig struct {
+	Enabled bool   `json:"enabled"`
+	Mode    string `json:"mode"`
+	// Failover is set for the shared modes that move to another LOM when
+	// LOM1 loses its link.
+	Failover bool `json:"failover"`
+
+	LinkDetected bool   `json:"linkDetected"`
+	Speed        string `json:"speed,omitempty"`
+	Duplex       string `json:"duplex,omitempty"`
+	// AutoNegotiate is the configured setting; without it the port runs
+	// at ConfiguredSpeed and, with FullDuplex, full duplex.
+	AutoNegotiate   bool   `json:"autoNegotiate"`
+	ConfiguredSpeed string `json:"configuredSpeed,omitempty"`
+	FullDuplex      bool   `json:"fullDuplex"`
+	MTU             int    `json:"mtu,omitempty"`
+
+	VLANEnabled  bool `json:"vlanEnabled"`
+	VLANID       int  `json:"vlanId,omitempty"`
+	VLANPriority int  `json:"vlanPriority,omitempty"`
+
+	DHCP      bool   `json:"dhcp"`
+	IPAddress string `json:"ipAddress,omitempty"`
+	Netmask   string `json:"netmask,omitempty"`
+	Gateway   string `json:"gateway,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// GetNICConfig reads the iDRAC's network port from "racadm getniccfg"
+// (link state) and the cfgLanNetworking and cfgNetTuning groups (mode,
+// VLAN, speed). iDRAC6 predates "racadm get iDRAC.NIC".
+func (a *Admin) GetNICConfig() (*NICConfig, error) {
+	niccfg, err := a.racadm.Run("getniccfg")
+	if err != nil {
+		return nil, fmt.Errorf("getting NIC config: %w", err)
+	}
+	lan, err := a.racadm.Run("getconfig", "-g", "cfgLanNetworking")
+	if err != nil {
+		return nil, fmt.Errorf("getting LAN settings: %w", err)
+	}
+	tuning, err := a.racadm.Run("getconfig", "-g", "cfgNetTuning")
+	if err != nil {
+		return nil, fmt.Errorf("getting NIC tuning: %w", err)
+	}
+	return parseNICConfig(niccfg, lan, tuning), nil
+}
+
+// parseNICConfig combines getniccfg output with the cfgLanNetworking and
+// cfgNetTuning groups. getniccfg's current addresses win over the
+// configured ones; the mode comes from cfgNicSelection, falling back to
+// getniccfg's "NIC Selection" text.
+func parseNICConfig(niccfg, lan, tuning string) *NICConfig {
+	sections := parseSysInfoSections(niccfg)
+	ipv4, lom := sections["IPv4 settings"], sections["LOM Status"]
+	lanCfg := parseConfigGroup(lan)
+	tuneCfg := parseConfigGroup(tuning)
+	num := func(s string) int {
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+
+	n := &NICConfig{
+		Enabled:       lanCfg["cfgNicEnable"] == "1" || lanCfg["cfgNicEnable"] == "" && ipv4["NIC Enabled"] == "1",
+		Mode:          nicModes[lanCfg["cfgNicSelection"]],
+		LinkDetected:  strings.EqualFold(lom["Link Detected"], "Yes"),
+		Speed:         lom["Speed"],
+		Duplex:        lom["Duplex Mode"],
+		AutoNegotiate: tuneCfg["cfgNetTuningNicAutoneg"] == "1",
+		FullDuplex:    tuneCfg["cfgNetTuningNicFullDuplex"] == "1",
+		MTU:           num(tuneCfg["cfgNetTuningNicMtu"]),
+		VLANEnabled:   lanCfg["cfgNicVLanEnable"] == "1",
+		VLANID:        num(lanCfg["cfgNicVLanID"]),
+		VLANPriority:  num(lanCfg["cfgNicVLanPriority"]),
+		DHCP:          lanCfg["cfgNicUseDhcp"] == "1",
+		IPAddress:     firstNonEmpty(ipv4["IP Address"], lanCfg["cfgNicIPAddress"]),
+		Netmask:       firstNonEmpty(ipv4["Subnet Mask"], lanCfg["cfgNicNetmask"]),
+		Gateway:       firstNonEmpty(ipv4["Gateway"], lanCfg["cfgNicGateway"]),
+	}
+	switch tuneCfg["cfgNetTuningNic100MB"] {
+	case "0":
+		n.ConfiguredSpeed = "10Mb/s"
+	case "1":
+		n.ConfiguredSpeed = "100Mb/s"
+	case "2":
+		n.ConfiguredSpeed = "1000Mb/s"
+	}
+	if n.Mode == "" {
+		n.Mode = nicModeFromText(lom["NIC Selection"])
+	}
+	n.Failover = n.Mode == NICModeSharedFailoverLOM2 || n.Mode == NICModeSharedFailoverAll
+	n.Warnings = nicWarnings(n)
+	return n
+}
+
+// nicModeFromText maps getniccfg's "NIC Selection" wording to a mode.
+func nicModeFromText(s string) string {
+	s = strings.ToLower(s)
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "dedicated"):
+		return NICModeDedicated
+	case strings.Contains(s, "all"):
+		return NICModeSharedFailoverAll
+	case strings.Contains(s, "failover"):
+		return NICModeSharedFailoverLOM2
+	default:
+		return NICModeShared
+	}
+}
+
+// nicWarnings flags settings that commonly cause management outages.
+func nicWarnings(n *NICConfig) []string {
+	var warnings []string
+	shared := n.Mode != "" && n.Mode != NICModeDedicated
+	if shared && !n.Failover {
+		warnings = append(warnings, "shared LOM without failover: management drops whenever LOM1 loses its link, e.g. while the host OS resets or re-teams its NICs")
+	}
+	if shared && !n.AutoNegotiate {
+		warnings = append(warnings, "auto-negotiation is off on a shared LOM: a speed or duplex mismatch with the host's own settings or the switch causes intermittent loss")
+	}
+	if n.Mode == NICModeDedicated && !n.LinkDetected {
+		warnings = append(warnings, "dedicated mode has no link: the dedicated port needs iDRAC6 Enterprise and a cable in the iDRAC port")
+	}
+	return warnings
+}
+
+func firstNonEmpty(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,122 @@
+package idrac
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleNICCfg = `IPv4 settings:
+NIC Enabled          = 1
+IPv4 Enabled         = 1
+DHCP Enabled         = 0
+IP Address           = 192.168.1.172
+Subnet Mask          = 255.255.255.0
+Gateway              = 192.168.1.1
+
+IPv6 settings:
+IPv6 Enabled         = 0
+DHCP6 Enabled        = 1
+IP Address 1         = ::
+Gateway              = ::
+
+LOM Status:
+NIC Selection        = Shared
+Link Detected        = Yes
+Speed                = 100Mb/s
+Duplex Mode          = Full Duplex
+`
+
+const sampleLanNetworking = `cfgNicEnable=1
+cfgNicIPAddress=192.168.1.172
+cfgNicNetmask=255.255.255.0
+cfgNicGateway=192.168.1.1
+cfgNicUseDhcp=0
+# cfgNicMacAddress=00:24:e8:3e:4b:bd
+cfgNicSelection=0
+cfgNicVLanEnable=1
+cfgNicVLanID=42
+cfgNicVLanPriority=3
+cfgDNSRacName=idrac-r710
+`
+
+const sampleNetTuning = `cfgNetTuningNicAutoneg=0
+cfgNetTuningNic100MB=1
+cfgNetTuningNicFullDuplex=1
+cfgNetTuningNicMtu=1500
+`
+
+// scriptedRACADM answers each command line from a map.
+type scriptedRACADM struct {
+	outputs map[string]string
+	calls   []string
+}
+
+func (s *scriptedRACADM) Run(args ...string) (string, error) {
+	cmd := strings.Join(args, " ")
+	s.calls = append(s.calls, cmd)
+	return s.outputs[cmd], nil
+}
+
+func TestGetNICConfig(t *testing.T) {
+	fake := &scriptedRACADM{outputs: map[string]string{
+		"getniccfg":                     sampleNICCfg,
+		"getconfig -g cfgLanNetworking": sampleLanNetworking,
+		"getconfig -g cfgNetTuning":     sampleNetTuning,
+	}}
+	a := &Admin{racadm: fake}
+
+	n, err := a.GetNICConfig()
+	if err != nil {
+		t.Fatalf("GetNICConfig() error = %v", err)
+	}
+	if !n.Enabled || n.Mode != NICModeShared || n.Failover {
+		t.Errorf("mode = %+v", n)
+	}
+	if !n.LinkDetected || n.Speed != "100Mb/s" || n.Duplex != "Full Duplex" {
+		t.Errorf("link = %v %q %q", n.LinkDetected, n.Speed, n.Duplex)
+	}
+	if n.AutoNegotiate || n.ConfiguredSpeed != "100Mb/s" || !n.FullDuplex || n.MTU != 1500 {
+		t.Errorf("tuning = %+v", n)
+	}
+	if !n.VLANEnabled || n.VLANID != 42 || n.VLANPriority != 3 {
+		t.Errorf("VLAN = %v %d %d", n.VLANEnabled, n.VLANID, n.VLANPriority)
+	}
+	if n.DHCP || n.IPAddress != "192.168.1.172" || n.Gateway != "192.168.1.1" {
+		t.Errorf("IPv4 = %+v", n)
+	}
+	// Shared without failover and without auto-negotiation.
+	if len(n.Warnings) != 2 {
+		t.Errorf("warnings = %q, want 2", n.Warnings)
+	}
+	if !slices.Equal(fake.calls, []string{"getniccfg", "getconfig -g cfgLanNetworking", "getconfig -g cfgNetTuning"}) {
+		t.Errorf("commands = %q", fake.calls)
+	}
+}
+
+func TestParseNICConfig_Modes(t *testing.T) {
+	for _, tt := range []struct {
+		selection, text string
+		want            string
+		failover        bool
+	}{
+		{"1", "", NICModeSharedFailoverLOM2, true},
+		{"2", "", NICModeDedicated, false},
+		{"3", "", NICModeSharedFailoverAll, true},
+		{"", "Shared with Failover LOM2", NICModeSharedFailoverLOM2, true},
+		{"", "Shared with Failover all LOMs", NICModeSharedFailoverAll, true},
+		{"", "Dedicated", NICModeDedicated, false},
+	} {
+		lan := ""
+		if tt.selection != "" {
+			lan = "cfgNicSelection=" + tt.selection
+		}
+		n := parseNICConfig("LOM Status:\nNIC Selection = "+tt.text+"\nLink Detected = No\n", lan, "cfgNetTuningNicAutoneg=1")
+		if n.Mode != tt.want || n.Failover != tt.failover {
+			t.Errorf("selection %q / %q: mode = %q, failover = %v; want %q, %v", tt.selection, tt.text, n.Mode, n.Failover, tt.want, tt.failover)
+		}
+		if dedicatedNoLink := tt.want == NICModeDedicated; dedicatedNoLink != (len(n.Warnings) == 1) {
+			t.Errorf("%s without link: warnings = %q", tt.want, n.Warnings)
+		}
+	}
+}
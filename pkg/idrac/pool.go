@@ -0,0 +1,245 @@
+package idrac
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Target identifies an iDRAC host and how to connect to it.
+type Target struct {
+	ID       string
+	Host     string
+	Username string
+	Password string
+	Options  []Option
+}
+
+// pooledClient is a logged-in client with its last-use time.
+type pooledClient struct {
+	client   *Client
+	lastUsed atomic.Int64 // unix nanoseconds
+}
+
+func (pc *pooledClient) touch() {
+	pc.lastUsed.Store(time.Now().UnixNano())
+}
+
+func (pc *pooledClient) idleSince(now time.Time) time.Duration {
+	return now.Sub(time.Unix(0, pc.lastUsed.Load()))
+}
+
+// Pool caches logged-in clients keyed by target ID so sessions are reused
+// across requests. iDRAC6 allows only a handful of concurrent sessions, so
+// consumers should share one Pool rather than logging in per operation.
+//
+// Logins go through a per-target FIFO gate: when many requests find a
+// target without a session, they queue in arrival order and, with the
+// default limit of one, all share the session the first one establishes.
+// Different targets log in in parallel.
+type Pool struct {
+	clients    sync.Map // map[string]*pooledClient
+	gates      sync.Map // map[string]*loginGate
+	loginLimit int
+
+	mu      sync.Mutex
+	retired ClientStats // counters from clients no longer in the pool
+}
+
+// PoolOption configures a Pool.
+type PoolOption func(*Pool)
+
+// WithLoginLimit sets how many logins to the same target may run at once
+// (default 1). Callers beyond the limit queue in arrival order.
+func WithLoginLimit(n int) PoolOption {
+	return func(p *Pool) {
+		if n > 0 {
+			p.loginLimit = n
+		}
+	}
+}
+
+// NewPool creates an empty client pool.
+func NewPool(opts ...PoolOption) *Pool {
+	p := &Pool{loginLimit: 1}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// Lookup returns the cached client for id without logging in.
+func (p *Pool) Lookup(id string) (*Client, bool) {
+	v, ok := p.clients.Load(id)
+	if !ok {
+		return nil, false
+	}
+	pc := v.(*pooledClient)
+	pc.touch()
+	return pc.client, true
+}
+
+// Get returns the cached client for t.ID, creating and logging in a new
+// one if none exists.
+func (p *Pool) Get(t Target) (*Client, error) {
+	return p.GetContext(context.Background(), t)
+}
+
+// GetContext is Get with a context that bounds the wait for the target's
+// login gate and the login itself.
+func (p *Pool) GetContext(ctx context.Context, t Target) (*Client, error) {
+	if client, ok := p.Lookup(t.ID); ok {
+		return client, nil
+	}
+
+	gate := p.gate(t.ID)
+	if err := gate.acquire(ctx); err != nil {
+		return nil, fmt.Errorf("waiting to log in to %s: %w", t.Host, err)
+	}
+	defer gate.release()
+
+	// A caller ahead in the queue may have logged in already.
+	if client, ok := p.Lookup(t.ID); ok {
+		return client, nil
+	}
+
+	client := NewClient(t.Host, t.Username, t.Password, t.Options...)
+	if err := client.LoginContext(ctx); err != nil {
+		p.retire(client)
+		return nil, fmt.Errorf("login to %s failed: %w", t.Host, err)
+	}
+
+	pc := &pooledClient{client: client}
+	pc.touch()
+	if existing, loaded := p.clients.LoadOrStore(t.ID, pc); loaded {
+		// Another caller logged in concurrently; keep theirs and free ours.
+		p.discard(t.ID, client)
+		existing.(*pooledClient).touch()
+		return existing.(*pooledClient).client, nil
+	}
+	return client, nil
+}
+
+// gate returns the login gate for id.
+func (p *Pool) gate(id string) *loginGate {
+	if g, ok := p.gates.Load(id); ok {
+		return g.(*loginGate)
+	}
+	g, _ := p.gates.LoadOrStore(id, &loginGate{limit: p.loginLimit})
+	return g.(*loginGate)
+}
+
+// LoginQueueStats returns, per target ID, the time callers have spent
+// queueing to log in.
+func (p *Pool) LoginQueueStats() map[string]LoginQueueStats {
+	out := make(map[string]LoginQueueStats)
+	p.gates.Range(func(key, v interface{}) bool {
+		out[key.(string)] = v.(*loginGate).stats()
+		return true
+	})
+	return out
+}
+
+// Evict logs out and removes the client for id, if cached, along with its
+// login gate, so a removed target leaves nothing behind. Callers already
+// queued on the old gate finish on it.
+func (p *Pool) Evict(id string) {
+	if v, ok := p.clients.LoadAndDelete(id); ok {
+		p.discard(id, v.(*pooledClient).client)
+	}
+	p.gates.Delete(id)
+}
+
+// Forget removes the client for id, if cached, without logging out, for an
+// iDRAC that is about to drop every session itself, such as one being
+// reset.
+func (p *Pool) Forget(id string) {
+	if v, ok := p.clients.LoadAndDelete(id); ok {
+		p.retire(v.(*pooledClient).client)
+	}
+}
+
+// EvictIdle logs out and removes clients idle for at least ttl, returning
+// how many were evicted. The next Get for an evicted target logs in again.
+func (p *Pool) EvictIdle(now time.Time, ttl time.Duration) int {
+	evicted := 0
+	p.clients.Range(func(key, v interface{}) bool {
+		pc := v.(*pooledClient)
+		if pc.idleSince(now) < ttl {
+			return true
+		}
+		if p.clients.CompareAndDelete(key, v) {
+			p.discard(key.(string), pc.client)
+			evicted++
+		}
+		return true
+	})
+	return evicted
+}
+
+// SweepIdle runs EvictIdle periodically until stop is closed.
+func (p *Pool) SweepIdle(ttl time.Duration, stop <-chan struct{}) {
+	interval := ttl / 2
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			p.EvictIdle(now, ttl)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Len returns the number of cached clients.
+func (p *Pool) Len() int {
+	n := 0
+	p.clients.Range(func(_, _ interface{}) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Stats returns counters aggregated across all clients the pool has created.
+func (p *Pool) Stats() ClientStats {
+	p.mu.Lock()
+	total := p.retired
+	p.mu.Unlock()
+
+	p.clients.Range(func(_, v interface{}) bool {
+		cs := v.(*pooledClient).client.Stats()
+		total.Logins += cs.Logins
+		total.LoginFailures += cs.LoginFailures
+		total.Retries += cs.Retries
+		total.BusyRetries += cs.BusyRetries
+		return true
+	})
+	return total
+}
+
+// discard logs out a client that is leaving the pool.
+func (p *Pool) discard(id string, client *Client) {
+	if err := client.Logout(); err != nil {
+		log.Printf("Logout of %s failed: %v", id, err)
+	}
+	p.retire(client)
+}
+
+func (p *Pool) retire(client *Client) {
+	cs := client.Stats()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retired.Logins += cs.Logins
+	p.retired.LoginFailures += cs.LoginFailures
+	p.retired.Retries += cs.Retries
+	p.retired.BusyRetries += cs.BusyRetries
+}
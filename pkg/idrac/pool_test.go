@@ -0,0 +1,105 @@
+package idrac
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+func TestPool_GetReusesClient(t *testing.T) {
+	server := idractest.NewServer(idractest.Options{})
+	defer server.Close()
+
+	p := NewPool()
+	target := Target{ID: "s1", Host: strings.TrimPrefix(server.URL, "https://"), Username: "root", Password: "calvin"}
+
+	a, err := p.Get(target)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	b, err := p.Get(target)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if a != b {
+		t.Error("Get() should return the cached client")
+	}
+	if p.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", p.Len())
+	}
+	if p.Stats().Logins != 1 {
+		t.Errorf("Logins = %d, want 1", p.Stats().Logins)
+	}
+
+	p.Evict("s1")
+	if p.Len() != 0 {
+		t.Errorf("Len() after Evict = %d, want 0", p.Len())
+	}
+	if server.Logouts() != 1 {
+		t.Errorf("logouts = %d, want 1", server.Logouts())
+	}
+	// Counters survive eviction
+	if p.Stats().Logins != 1 {
+		t.Errorf("Logins after Evict = %d, want 1", p.Stats().Logins)
+	}
+
+	// Forget drops the client without logging out.
+	if _, err := p.Get(target); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	p.Forget("s1")
+	if p.Len() != 0 || server.Logouts() != 1 {
+		t.Errorf("after Forget: Len() = %d, logouts = %d, want 0 and 1", p.Len(), server.Logouts())
+	}
+	if p.Stats().Logins != 2 {
+		t.Errorf("Logins after Forget = %d, want 2", p.Stats().Logins)
+	}
+}
+
+func TestPool_EvictIdle(t *testing.T) {
+	server := idractest.NewServer(idractest.Options{})
+	defer server.Close()
+
+	p := NewPool()
+	target := Target{ID: "s1", Host: strings.TrimPrefix(server.URL, "https://"), Username: "root", Password: "calvin"}
+	if _, err := p.Get(target); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	if n := p.EvictIdle(time.Now(), time.Minute); n != 0 {
+		t.Errorf("evicted %d fresh clients, want 0", n)
+	}
+	if n := p.EvictIdle(time.Now().Add(2*time.Minute), time.Minute); n != 1 {
+		t.Errorf("evicted %d idle clients, want 1", n)
+	}
+	if server.Logouts() != 1 {
+		t.Errorf("logouts = %d, want 1", server.Logouts())
+	}
+
+	// Next use logs in again
+	if _, err := p.Get(target); err != nil {
+		t.Fatalf("Get() after eviction error = %v", err)
+	}
+	if p.Stats().Logins != 2 {
+		t.Errorf("Logins = %d, want 2", p.Stats().Logins)
+	}
+}
+
+func TestPool_LoginFailureNotCached(t *testing.T) {
+	server := mockIDRAC(t, 1, "")
+	defer server.Close()
+
+	p := NewPool()
+	target := Target{ID: "s1", Host: strings.TrimPrefix(server.URL, "https://"), Username: "root", Password: "wrong"}
+	if _, err := p.Get(target); err == nil {
+		t.Fatal("Get() should fail on bad credentials")
+	}
+	if p.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", p.Len())
+	}
+	if p.Stats().LoginFailures != 1 {
+		t.Errorf("LoginFailures = %d, want 1", p.Stats().LoginFailures)
+	}
+}
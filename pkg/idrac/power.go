@@ -0,0 +1,139 @@
+package idrac
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PowerState represents the server power state.
+type PowerState int
+
+const (
+	PowerOff     PowerState = 0
+	PowerOn      PowerState = 1
+	PowerInvalid PowerState = 2
+)
+
+func (s PowerState) String() string {
+	switch s {
+	case PowerOff:
+		return "off"
+	case PowerOn:
+		return "on"
+	default:
+		return "unknown"
+	}
+}
+
+// PowerAction represents a power control action.
+type PowerAction int
+
+const (
+	ActionPowerOff     PowerAction = 0
+	ActionPowerOn      PowerAction = 1
+	ActionPowerRestart PowerAction = 2
+	ActionPowerReset   PowerAction = 3
+	ActionNMI          PowerAction = 4
+	ActionGracefulShut PowerAction = 5
+)
+
+// ValidPowerActions maps action names to their numeric values.
+var ValidPowerActions = map[string]PowerAction{
+	"off":      ActionPowerOff,
+	"on":       ActionPowerOn,
+	"restart":  ActionPowerRestart,
+	"reset":    ActionPowerReset,
+	"nmi":      ActionNMI,
+	"shutdown": ActionGracefulShut,
+}
+
+type powerResponse struct {
+	XMLName xml.Name `xml:"root"`
+	PwState string   `xml:"pwState"`
+}
+
+// PowerStatus holds the current power state.
+type PowerStatus struct {
+	State  PowerState `json:"state"`
+	Status string     `json:"status"`
+}
+
+// GetPowerState returns the current power state.
+func (c *Client) GetPowerState() (*PowerStatus, error) {
+	return c.GetPowerStateContext(context.Background())
+}
+
+// GetPowerStateContext is GetPowerState with a context for cancellation and tracing.
+func (c *Client) GetPowerStateContext(ctx context.Context) (*PowerStatus, error) {
+	data, err := c.GetContext(ctx, "pwState")
+	if err != nil {
+		return nil, fmt.Errorf("getting power state: %w", err)
+	}
+
+	var resp powerResponse
+	if err := decodeXML(data, &resp); err != nil {
+		return nil, fmt.Errorf("parsing power state: %w", err)
+	}
+
+	state := parsePowerState(resp.PwState)
+	return &PowerStatus{
+		State:  state,
+		Status: state.String(),
+	}, nil
+}
+
+// parsePowerState maps a pwState value to a PowerState.
+func parsePowerState(s string) PowerState {
+	switch s {
+	case "0":
+		return PowerOff
+	case "1":
+		return PowerOn
+	}
+	return PowerInvalid
+}
+
+// SetPower executes a power action.
+func (c *Client) SetPower(action PowerAction) error {
+	return c.SetPowerContext(context.Background(), action)
+}
+
+// SetPowerContext is SetPower with a context for cancellation and tracing.
+func (c *Client) SetPowerContext(ctx context.Context, action PowerAction) error {
+	_, err := c.SetContext(ctx, fmt.Sprintf("pwState:%d", action))
+	if err != nil {
+		return fmt.Errorf("setting power state: %w", err)
+	}
+	return nil
+}
+
+// SetPowerByName executes a power action by name.
+func (c *Client) SetPowerByName(name string) error {
+	return c.SetPowerByNameContext(context.Background(), name)
+}
+
+// SetPowerByNameContext is SetPowerByName with a context for cancellation and tracing.
+func (c *Client) SetPowerByNameContext(ctx context.Context, name string) error {
+	action, ok := ValidPowerActions[name]
+	if !ok {
+		return fmt.Errorf("unknown power action: %q (valid: off, on, restart, reset, nmi, shutdown)", name)
+	}
+	return c.SetPowerContext(ctx, action)
+}
+
+// optionalWatts extracts the leading wattage from values like "245" or
+// "285 W | 973 Btu/hr", or returns nil if there is none.
+func optionalWatts(s string) *int {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return nil
+	}
+	n, err := strconv.Atoi(strings.TrimSuffix(fields[0], "W"))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
@@ -0,0 +1,132 @@
+package idrac
+
+import "fmt"
+
+// PowerReading is a power measurement and when it was taken.
+type PowerReading struct {
+	Watts     int    `json:"watts"`
+	Timestamp string `json:"timestamp,omitempty"`
+}
+
+// PowerWindow summarizes power consumption over a trailing period.
+type PowerWindow struct {
+	AverageWatts int          `json:"averageWatts"`
+	Min          PowerReading `json:"min"`
+	Max          PowerReading `json:"max"`
+}
+
+// PowerStats holds the iDRAC's power consumption history.
+type PowerStats struct {
+	CurrentWatts int          `json:"currentWatts"`
+	Peak         PowerReading `json:"peak"`
+	LastHour     PowerWindow  `json:"lastHour"`
+	LastDay      PowerWindow  `json:"lastDay"`
+	LastWeek     PowerWindow  `json:"lastWeek"`
+}
+
+// GetPowerStats returns current, peak, and trailing min/max/average power
+// consumption from the cfgServerPower configuration group.
+func (a *Admin) GetPowerStats() (*PowerStats, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgServerPower")
+	if err != nil {
+		return nil, fmt.Errorf("getting power stats: %w", err)
+	}
+	return parsePowerStats(parseConfigGroup(output)), nil
+}
+
+// ResetPowerStats clears the peak power consumption counter by writing
+// the write-only cfgServerPowerConsumptionClear property;
+// cfgServerPeakPowerConsumption itself is read-only.
+func (a *Admin) ResetPowerStats() error {
+	if _, err := a.racadm.Run("config", "-g", "cfgServerPower", "-o", "cfgServerPowerConsumptionClear", "1"); err != nil {
+		return fmt.Errorf("resetting peak power: %w", err)
+	}
+	return nil
+}
+
+// PowerDetail is the power state with the consumption, potential draw,
+// and cap readings of cfgServerPower. A reading is nil when the firmware
+// leaves it out.
+type PowerDetail struct {
+	PowerStatus
+	InputWatts *int `json:"inputWatts,omitempty"`
+	PeakWatts  *int `json:"peakWatts,omitempty"`
+	// MinPotentialWatts and MaxPotentialWatts bound what the installed
+	// hardware can draw, the web UI's power budget.
+	MinPotentialWatts *int  `json:"minPotentialWatts,omitempty"`
+	MaxPotentialWatts *int  `json:"maxPotentialWatts,omitempty"`
+	CapEnabled        *bool `json:"capEnabled,omitempty"`
+	CapWatts          *int  `json:"capWatts,omitempty"`
+	// HeadroomWatts is what remains at the current draw under the cap when
+	// it is enabled, or under MaxPotentialWatts otherwise.
+	HeadroomWatts *int `json:"headroomWatts,omitempty"`
+}
+
+// GetPowerDetail returns the cfgServerPower readings. The group does not
+// carry the power state, so PowerStatus is PowerInvalid; fill it in from
+// Client.GetPowerState.
+func (a *Admin) GetPowerDetail() (*PowerDetail, error) {
+	output, err := a.racadm.Run("getconfig", "-g", "cfgServerPower")
+	if err != nil {
+		return nil, fmt.Errorf("getting power detail: %w", err)
+	}
+	return parsePowerDetail(parseConfigGroup(output)), nil
+}
+
+func parsePowerDetail(props map[string]string) *PowerDetail {
+	d := &PowerDetail{
+		PowerStatus:       PowerStatus{State: PowerInvalid, Status: PowerInvalid.String()},
+		InputWatts:        optionalWatts(props["cfgServerActualPowerConsumption"]),
+		PeakWatts:         optionalWatts(props["cfgServerPeakPowerConsumption"]),
+		MinPotentialWatts: optionalWatts(props["cfgServerMinPowerCapacity"]),
+		MaxPotentialWatts: optionalWatts(props["cfgServerMaxPowerCapacity"]),
+		CapWatts:          optionalWatts(props["cfgServerPowerCapWatts"]),
+	}
+	switch props["cfgServerPowerCapEnable"] {
+	case "0":
+		d.CapEnabled = new(bool)
+	case "1":
+		enabled := true
+		d.CapEnabled = &enabled
+	}
+	limit := d.MaxPotentialWatts
+	if d.CapEnabled != nil && *d.CapEnabled {
+		limit = d.CapWatts
+	}
+	if limit != nil && d.InputWatts != nil {
+		headroom := *limit - *d.InputWatts
+		d.HeadroomWatts = &headroom
+	}
+	return d
+}
+
+func parsePowerStats(props map[string]string) *PowerStats {
+	window := func(period string) PowerWindow {
+		prefix := "cfgServerPowerLast" + period
+		return PowerWindow{
+			AverageWatts: parseWatts(props[prefix+"Avg"]),
+			Min:          PowerReading{Watts: parseWatts(props[prefix+"MinPower"]), Timestamp: props[prefix+"MinTime"]},
+			Max:          PowerReading{Watts: parseWatts(props[prefix+"MaxPower"]), Timestamp: props[prefix+"MaxTime"]},
+		}
+	}
+
+	return &PowerStats{
+		CurrentWatts: parseWatts(props["cfgServerActualPowerConsumption"]),
+		Peak: PowerReading{
+			Watts:     parseWatts(props["cfgServerPeakPowerConsumption"]),
+			Timestamp: props["cfgServerPeakPowerConsumptionTimestamp"],
+		},
+		LastHour: window("Hour"),
+		LastDay:  window("Day"),
+		LastWeek: window("Week"),
+	}
+}
+
+// parseWatts extracts the leading wattage from values like
+// "285 W | 973 Btu/hr", returning 0 if there is none.
+func parseWatts(s string) int {
+	if n := optionalWatts(s); n != nil {
+		return *n
+	}
+	return 0
+}
@@ -0,0 +1,93 @@
+package idrac
+
+import "testing"
+
+const cfgServerPowerOutput = `# cfgServerPowerStatus=1
+# cfgServerActualPowerConsumption=196 W | 669 Btu/hr
+# cfgServerPeakPowerConsumption=285 W | 973 Btu/hr
+# cfgServerPeakPowerConsumptionTimestamp=Thu Mar 04 14:55:36 2010
+# cfgServerPowerLastHourAvg=198 W | 676 Btu/hr
+# cfgServerPowerLastHourMinPower=189 W | 645 Btu/hr
+# cfgServerPowerLastHourMinTime=Thu Mar 04 16:05:11 2010
+# cfgServerPowerLastHourMaxPower=214 W | 730 Btu/hr
+# cfgServerPowerLastHourMaxTime=Thu Mar 04 16:21:40 2010
+# cfgServerPowerLastDayAvg=201 W | 686 Btu/hr
+# cfgServerPowerLastWeekMaxPower=285W
+cfgServerPowerCapWatts=400 W`
+
+func TestGetPowerStats(t *testing.T) {
+	fake := &fakeRACADM{output: cfgServerPowerOutput}
+	a := &Admin{racadm: fake}
+
+	stats, err := a.GetPowerStats()
+	if err != nil {
+		t.Fatalf("GetPowerStats() error = %v", err)
+	}
+	if fake.calls[0] != "getconfig -g cfgServerPower" {
+		t.Errorf("call = %q, want getconfig -g cfgServerPower", fake.calls[0])
+	}
+
+	if stats.CurrentWatts != 196 {
+		t.Errorf("CurrentWatts = %d, want 196", stats.CurrentWatts)
+	}
+	if stats.Peak.Watts != 285 || stats.Peak.Timestamp != "Thu Mar 04 14:55:36 2010" {
+		t.Errorf("Peak = %+v, want 285 W at Thu Mar 04 14:55:36 2010", stats.Peak)
+	}
+	if stats.LastHour.AverageWatts != 198 || stats.LastHour.Min.Watts != 189 || stats.LastHour.Max.Watts != 214 {
+		t.Errorf("LastHour = %+v", stats.LastHour)
+	}
+	if stats.LastHour.Max.Timestamp != "Thu Mar 04 16:21:40 2010" {
+		t.Errorf("LastHour.Max.Timestamp = %q", stats.LastHour.Max.Timestamp)
+	}
+	if stats.LastDay.AverageWatts != 201 {
+		t.Errorf("LastDay.AverageWatts = %d, want 201", stats.LastDay.AverageWatts)
+	}
+	if stats.LastWeek.Max.Watts != 285 {
+		t.Errorf("LastWeek.Max.Watts = %d, want 285 (unspaced unit)", stats.LastWeek.Max.Watts)
+	}
+}
+
+func TestGetPowerDetail(t *testing.T) {
+	a := &Admin{racadm: &fakeRACADM{output: cfgServerPowerOutput + `
+# cfgServerMinPowerCapacity=175 W | 597 Btu/hr
+# cfgServerMaxPowerCapacity=570 W | 1945 Btu/hr
+cfgServerPowerCapEnable=0`}}
+
+	d, err := a.GetPowerDetail()
+	if err != nil {
+		t.Fatalf("GetPowerDetail() error = %v", err)
+	}
+	if d.State != PowerInvalid || *d.InputWatts != 196 || *d.PeakWatts != 285 || *d.MinPotentialWatts != 175 {
+		t.Errorf("GetPowerDetail() = %+v", d)
+	}
+	if d.CapEnabled == nil || *d.CapEnabled || *d.CapWatts != 400 {
+		t.Errorf("cap = %v/%v, want 400 W disabled", d.CapEnabled, d.CapWatts)
+	}
+	if d.HeadroomWatts == nil || *d.HeadroomWatts != 374 {
+		t.Errorf("HeadroomWatts = %v, want 374 under the maximum potential draw", d.HeadroomWatts)
+	}
+
+	// With the cap enabled, headroom is measured against it.
+	d = parsePowerDetail(map[string]string{"cfgServerActualPowerConsumption": "196 W", "cfgServerPowerCapEnable": "1", "cfgServerPowerCapWatts": "300 W"})
+	if d.HeadroomWatts == nil || *d.HeadroomWatts != 104 {
+		t.Errorf("HeadroomWatts = %v, want 104 under the cap", d.HeadroomWatts)
+	}
+
+	// Firmware that reports nothing leaves every reading out.
+	if d := parsePowerDetail(map[string]string{}); d.InputWatts != nil || d.CapEnabled != nil || d.HeadroomWatts != nil {
+		t.Errorf("parsePowerDetail(empty) = %+v, want readings omitted", d)
+	}
+}
+
+func TestResetPowerStats(t *testing.T) {
+	fake := &fakeRACADM{}
+	a := &Admin{racadm: fake}
+
+	if err := a.ResetPowerStats(); err != nil {
+		t.Fatalf("ResetPowerStats() error = %v", err)
+	}
+	want := "config -g cfgServerPower -o cfgServerPowerConsumptionClear 1"
+	if len(fake.calls) != 1 || fake.calls[0] != want {
+		t.Errorf("calls = %v, want [%s]", fake.calls, want)
+	}
+}
@@ -0,0 +1,174 @@
+package idrac
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// SELEntry represents a System Event Log entry.
+type SELEntry struct {
+	ID          string `json:"id"`
+	Timestamp   string `json:"timestamp"`
+	Severity    string `json:"severity"`
+	Description string `json:"description"`
+	Entity      string `json:"entity,omitempty"`
+}
+
+// SELData holds the system event log.
+type SELData struct {
+	Entries    []SELEntry `json:"entries"`
+	TotalCount int        `json:"totalCount"`
+}
+
+type selResponse struct {
+	XMLName xml.Name `xml:"root"`
+	SEL     string   `xml:"sel"`
+}
+
+// GetSEL returns the System Event Log entries.
+func (c *Client) GetSEL() (*SELData, error) {
+	return c.GetSELContext(context.Background())
+}
+
+// GetSELContext is GetSEL with a context for cancellation and tracing.
+func (c *Client) GetSELContext(ctx context.Context) (*SELData, error) {
+	data, err := c.GetContext(ctx, "sel")
+	if err != nil {
+		return nil, fmt.Errorf("getting SEL: %w", err)
+	}
+
+	var resp selResponse
+	if err := decodeXML(data, &resp); err != nil {
+		return nil, fmt.Errorf("parsing SEL: %w", err)
+	}
+
+	entries := parseSEL(resp.SEL)
+
+	return &SELData{
+		Entries:    entries,
+		TotalCount: len(entries),
+	}, nil
+}
+
+// ClearSEL clears the System Event Log.
+func (c *Client) ClearSEL() error {
+	return c.ClearSELContext(context.Background())
+}
+
+// ClearSELContext is ClearSEL with a context for cancellation and tracing.
+func (c *Client) ClearSELContext(ctx context.Context) error {
+	_, err := c.SetContext(ctx, "selClr:1")
+	if err != nil {
+		return fmt.Errorf("clearing SEL: %w", err)
+	}
+	return nil
+}
+
+// parseSEL parses the raw SEL string into structured entries.
+// Format varies by firmware but typically: "id|timestamp|severity|description\n..."
+func parseSEL(raw string) []SELEntry {
+	var entries []SELEntry
+
+	raw = strings.TrimSpace(stripCDATA(raw))
+	if raw == "" {
+		return entries
+	}
+
+	lines := strings.Split(raw, "\n")
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		entry := parseSELLine(line)
+		if entry.ID != "" {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
+
+// parseSELLine parses a single SEL entry line. Fields are split before
+// entities are unescaped, so an encoded delimiter stays in its field.
+func parseSELLine(line string) SELEntry {
+	// Try pipe-delimited format: "1|2024-01-01 12:00:00|Normal|System Boot"
+	parts := strings.SplitN(line, "|", 4)
+	if len(parts) >= 4 {
+		return SELEntry{
+			ID:          cleanText(parts[0]),
+			Timestamp:   cleanText(parts[1]),
+			Severity:    cleanText(parts[2]),
+			Description: cleanText(parts[3]),
+		}
+	}
+
+	// Try semicolon-delimited
+	parts = strings.SplitN(line, ";", 4)
+	if len(parts) >= 4 {
+		return SELEntry{
+			ID:          cleanText(parts[0]),
+			Timestamp:   cleanText(parts[1]),
+			Severity:    cleanText(parts[2]),
+			Description: cleanText(parts[3]),
+		}
+	}
+
+	// Fallback: treat entire line as description
+	return SELEntry{
+		ID:          "0",
+		Description: cleanText(line),
+		Severity:    "Unknown",
+	}
+}
+
+// Normalized SEL severities.
+const (
+	SeverityNormal   = "normal"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// NormalizeSeverity maps the firmware's severity text ("Ok", "Non-Critical",
+// "Critical", "Error", ...) to normal, warning, or critical.
+func NormalizeSeverity(severity string) string {
+	s := strings.ToLower(severity)
+	switch {
+	case strings.Contains(s, "non-critical"), strings.Contains(s, "warn"):
+		return SeverityWarning
+	case strings.Contains(s, "critical"), strings.Contains(s, "error"), strings.Contains(s, "non-recoverable"):
+		return SeverityCritical
+	default:
+		return SeverityNormal
+	}
+}
+
+// SELSummary counts SEL entries by normalized severity.
+type SELSummary struct {
+	TotalCount     int       `json:"totalCount"`
+	Normal         int       `json:"normal"`
+	Warning        int       `json:"warning"`
+	Critical       int       `json:"critical"`
+	LatestCritical *SELEntry `json:"latestCritical,omitempty"`
+}
+
+// Summarize counts the log's entries by severity and finds the most recent
+// critical one. Entries are in log order, oldest first.
+func (d *SELData) Summarize() *SELSummary {
+	s := &SELSummary{TotalCount: len(d.Entries)}
+	for i := range d.Entries {
+		switch NormalizeSeverity(d.Entries[i].Severity) {
+		case SeverityCritical:
+			s.Critical++
+			s.LatestCritical = &d.Entries[i]
+		case SeverityWarning:
+			s.Warning++
+		default:
+			s.Normal++
+		}
+	}
+	return s
+}
@@ -0,0 +1,164 @@
+package idrac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetSELRange returns SEL entries via "racadm getsel", starting at the
+// 1-based record number start. count limits the number of entries; zero
+// means all remaining records. Unlike the web API, RACADM can fetch a
+// slice of the log without transferring all of it.
+func (a *Admin) GetSELRange(start, count int) ([]SELEntry, error) {
+	if start < 1 {
+		start = 1
+	}
+	args := []string{"getsel", "-s", strconv.Itoa(start)}
+	if count > 0 {
+		args = append(args, "-c", strconv.Itoa(count))
+	}
+
+	output, err := a.racadm.Run(args...)
+	if err != nil {
+		return nil, fmt.Errorf("getting SEL range: %w", err)
+	}
+	return parseRACADMSEL(output), nil
+}
+
+// GetSELSince returns SEL entries with a record ID greater than since,
+// oldest first. getsel -s takes a position in the log, not a record ID, so
+// it reads back from the newest record a chunk at a time until it reaches
+// one at or below since: a poll for a few new entries costs one count and
+// one range read. total is the number of records in the log.
+func (a *Admin) GetSELSince(since int) (entries []SELEntry, total int, err error) {
+	total, err = a.SELCount()
+	if err != nil {
+		return nil, 0, err
+	}
+	var newer []SELEntry
+	for end := total; end > 0; end -= SELChunkSize {
+		start := max(end-SELChunkSize+1, 1)
+		chunk, err := a.GetSELRange(start, end-start+1)
+		if err != nil {
+			return nil, 0, err
+		}
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if id, err := strconv.Atoi(chunk[i].ID); err == nil && id <= since {
+				return append(chunk[i+1:], newer...), total, nil
+			}
+		}
+		newer = append(chunk, newer...)
+	}
+	return newer, total, nil
+}
+
+// GetSELLast returns the most recent n SEL entries and the number of
+// records in the log.
+func (a *Admin) GetSELLast(n int) (entries []SELEntry, total int, err error) {
+	total, err = a.SELCount()
+	if err != nil {
+		return nil, 0, err
+	}
+	start := total - n + 1
+	entries, err = a.GetSELRange(start, n)
+	return entries, total, err
+}
+
+// SELChunkSize is the default number of records StreamSEL fetches per
+// RACADM call.
+const SELChunkSize = 500
+
+// StreamSEL reads the SEL after its first skip records in chunks of up to
+// chunk records, calling fn with each chunk so a large log never has to be
+// held in memory at once. skip counts positions in the log, as getsel -s
+// does, not record IDs. It stops at the end of the log or when fn returns
+// an error, which is returned as is.
+func (a *Admin) StreamSEL(skip, chunk int, fn func([]SELEntry) error) error {
+	if chunk < 1 {
+		chunk = SELChunkSize
+	}
+	for start := skip + 1; ; start += chunk {
+		entries, err := a.GetSELRange(start, chunk)
+		if err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			if err := fn(entries); err != nil {
+				return err
+			}
+		}
+		if len(entries) < chunk {
+			return nil
+		}
+	}
+}
+
+// SELCount returns the number of records in the SEL ("racadm getsel -i").
+func (a *Admin) SELCount() (int, error) {
+	output, err := a.racadm.Run("getsel", "-i")
+	if err != nil {
+		return 0, fmt.Errorf("getting SEL count: %w", err)
+	}
+	// "Total Records: 42"
+	for _, line := range strings.Split(output, "\n") {
+		key, val, ok := strings.Cut(line, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "Total Records") {
+			n, err := strconv.Atoi(strings.TrimSpace(val))
+			if err != nil {
+				return 0, fmt.Errorf("parsing SEL count %q: %w", val, err)
+			}
+			return n, nil
+		}
+	}
+	return 0, fmt.Errorf("SEL count not found in output")
+}
+
+// parseRACADMSEL parses getsel output, which is a series of records:
+//
+//	Record:      1
+//	Date/Time:   11/20/2009 15:49:20
+//	Source:      system
+//	Severity:    Ok
+//	Description: Log cleared
+//	-----------------------------------------------
+func parseRACADMSEL(output string) []SELEntry {
+	var entries []SELEntry
+	var cur SELEntry
+
+	flush := func() {
+		if cur.ID != "" {
+			entries = append(entries, cur)
+		}
+		cur = SELEntry{}
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "---") {
+			flush()
+			continue
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "record":
+			flush()
+			cur.ID = val
+		case "date/time":
+			cur.Timestamp = val
+		case "source":
+			cur.Entity = val
+		case "severity":
+			cur.Severity = val
+		case "description":
+			cur.Description = val
+		}
+	}
+	flush()
+
+	return entries
+}
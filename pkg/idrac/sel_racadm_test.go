@@ -0,0 +1,120 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const racadmSELOutput = `Record:      41
+Date/Time:   11/20/2009 15:49:20
+Source:      system
+Severity:    Ok
+Description: Log cleared
+-------------------------------------------------------------------------------
+Record:      42
+Date/Time:   11/21/2009 08:01:12
+Source:      system
+Severity:    Critical
+Description: The system board ambient temperature is greater than the upper critical threshold.
+-------------------------------------------------------------------------------
+`
+
+func TestParseRACADMSEL(t *testing.T) {
+	entries := parseRACADMSEL(racadmSELOutput)
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].ID != "41" || entries[0].Severity != "Ok" || entries[0].Description != "Log cleared" {
+		t.Errorf("unexpected first entry: %+v", entries[0])
+	}
+	if entries[1].Timestamp != "11/21/2009 08:01:12" {
+		t.Errorf("Timestamp = %q, want 11/21/2009 08:01:12", entries[1].Timestamp)
+	}
+	if entries[1].Entity != "system" {
+		t.Errorf("Entity = %q, want system", entries[1].Entity)
+	}
+}
+
+func TestGetSELSince(t *testing.T) {
+	fake := &scriptedRACADM{outputs: map[string]string{
+		"getsel -i":        "Total Records: 2",
+		"getsel -s 1 -c 2": racadmSELOutput,
+	}}
+	a := &Admin{racadm: fake}
+
+	// Records 41 and 42 sit at positions 1 and 2.
+	entries, total, err := a.GetSELSince(41)
+	if err != nil {
+		t.Fatalf("GetSELSince() error = %v", err)
+	}
+	if len(entries) != 1 || entries[0].ID != "42" || total != 2 {
+		t.Errorf("entries = %+v of %d, want record 42 only of 2", entries, total)
+	}
+	if got := strings.Join(fake.calls, "|"); got != "getsel -i|getsel -s 1 -c 2" {
+		t.Errorf("calls = %s, want a count and one range read", got)
+	}
+
+	if entries, _, _ := a.GetSELSince(40); len(entries) != 2 {
+		t.Errorf("got %d entries since 40, want 2", len(entries))
+	}
+}
+
+func TestSELCount(t *testing.T) {
+	a := &Admin{racadm: &fakeRACADM{output: "Total Records: 42"}}
+	n, err := a.SELCount()
+	if err != nil {
+		t.Fatalf("SELCount() error = %v", err)
+	}
+	if n != 42 {
+		t.Errorf("SELCount() = %d, want 42", n)
+	}
+}
+
+// pagedRACADM serves "getsel -s <start> -c <count>" from a list of records.
+type pagedRACADM struct {
+	records int
+	calls   []string
+}
+
+func (p *pagedRACADM) Run(args ...string) (string, error) {
+	p.calls = append(p.calls, strings.Join(args, " "))
+	start, _ := strconv.Atoi(args[2])
+	count, _ := strconv.Atoi(args[4])
+	var b strings.Builder
+	for id := start; id < start+count && id <= p.records; id++ {
+		fmt.Fprintf(&b, "Record: %d\nSeverity: Ok\nDescription: event %d\n----\n", id, id)
+	}
+	return b.String(), nil
+}
+
+func TestStreamSEL(t *testing.T) {
+	fake := &pagedRACADM{records: 7}
+	a := &Admin{racadm: fake}
+
+	var ids []string
+	err := a.StreamSEL(1, 3, func(entries []SELEntry) error {
+		for _, e := range entries {
+			ids = append(ids, e.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamSEL() error = %v", err)
+	}
+	if got := strings.Join(ids, ","); got != "2,3,4,5,6,7" {
+		t.Errorf("streamed IDs = %s, want 2..7", got)
+	}
+	want := []string{"getsel -s 2 -c 3", "getsel -s 5 -c 3", "getsel -s 8 -c 3"}
+	if strings.Join(fake.calls, "|") != strings.Join(want, "|") {
+		t.Errorf("calls = %v, want %v", fake.calls, want)
+	}
+
+	stop := errors.New("client went away")
+	fake.calls = nil
+	if err := a.StreamSEL(0, 3, func([]SELEntry) error { return stop }); err != stop || len(fake.calls) != 1 {
+		t.Errorf("StreamSEL() = %v after %d calls, want callback error after 1", err, len(fake.calls))
+	}
+}
@@ -0,0 +1,179 @@
+package idrac
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+func TestParseSEL(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantCount int
+	}{
+		{"pipe delimited", "1|2024-01-01 12:00:00|Normal|System Boot\n2|2024-01-01 12:05:00|Warning|Temperature above threshold", 2},
+		{"empty", "", 0},
+		{"semicolon delimited", "1;2024-01-01;Critical;Disk failure", 1},
+		{"fallback single line", "Unknown event data", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := parseSEL(tt.raw)
+			if len(entries) != tt.wantCount {
+				t.Errorf("got %d entries, want %d", len(entries), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestParseSELLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantID   string
+		wantSev  string
+		wantDesc string
+	}{
+		{"pipe format", "42|2024-06-15 10:30:00|Normal|System powered on", "42", "Normal", "System powered on"},
+		{"semicolon format", "7;2024-06-15;Critical;PSU failure", "7", "Critical", "PSU failure"},
+		{"fallback", "raw event text", "0", "Unknown", "raw event text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := parseSELLine(tt.line)
+			if e.ID != tt.wantID {
+				t.Errorf("ID = %q, want %q", e.ID, tt.wantID)
+			}
+			if e.Severity != tt.wantSev {
+				t.Errorf("Severity = %q, want %q", e.Severity, tt.wantSev)
+			}
+			if e.Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", e.Description, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestGetSEL(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start.html":
+			http.SetCookie(w, &http.Cookie{Name: "_appwebSessionId_", Value: "sess"})
+			fmt.Fprint(w, `<html></html>`)
+		case "/data/login":
+			fmt.Fprint(w, `<root><authResult>0</authResult><forwardUrl>index.html</forwardUrl></root>`)
+		case "/data":
+			fmt.Fprint(w, `<root><sel>1|2024-01-01 12:00:00|Normal|Boot
+2|2024-01-01 12:05:00|Warning|Temp high</sel></root>`)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("localhost", "root", "calvin")
+	c.baseURL = server.URL
+	c.http = server.Client()
+	_ = c.Login()
+
+	sel, err := c.GetSEL()
+	if err != nil {
+		t.Fatalf("GetSEL() error = %v", err)
+	}
+
+	if sel.TotalCount != 2 {
+		t.Errorf("TotalCount = %d, want 2", sel.TotalCount)
+	}
+	if sel.Entries[0].Description != "Boot" {
+		t.Errorf("first entry description = %q, want Boot", sel.Entries[0].Description)
+	}
+}
+
+func TestGetSEL_EncodedPayloads(t *testing.T) {
+	tests := []struct {
+		name, sel string
+	}{
+		{"cdata with entities", `<![CDATA[1|2024-01-01 12:00:00|Critical|CPU1 Temp &gt; 90 C &amp; rising]]>`},
+		{"double-encoded entities", `1|2024-01-01 12:00:00|Critical|CPU1 Temp &amp;gt; 90 C &amp;amp; rising`},
+		{"entity-encoded cdata", `&lt;![CDATA[1|2024-01-01 12:00:00|Critical|CPU1 Temp &amp;gt; 90 C &amp;amp; rising]]&gt;`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := idractest.NewServer(idractest.Options{Data: map[string]string{"sel": tt.sel}})
+			defer srv.Close()
+
+			sel, err := NewClient(srv.Addr(), "root", "calvin").GetSEL()
+			if err != nil {
+				t.Fatalf("GetSEL() error = %v", err)
+			}
+			if len(sel.Entries) != 1 {
+				t.Fatalf("entries = %+v, want 1", sel.Entries)
+			}
+			e := sel.Entries[0]
+			if e.ID != "1" || e.Severity != "Critical" || e.Description != "CPU1 Temp > 90 C & rising" {
+				t.Errorf("entry = %+v", e)
+			}
+		})
+	}
+}
+
+func TestSELSummarize(t *testing.T) {
+	sel := &SELData{Entries: []SELEntry{
+		{ID: "1", Severity: "Ok", Description: "Log cleared"},
+		{ID: "2", Severity: "Critical", Description: "PS1 failure"},
+		{ID: "3", Severity: "Non-Critical", Description: "Fan 2 low"},
+		{ID: "4", Severity: "Critical", Description: "CPU1 temp high"},
+		{ID: "5", Severity: "Normal", Description: "PS1 ok"},
+	}}
+
+	s := sel.Summarize()
+	if s.TotalCount != 5 || s.Normal != 2 || s.Warning != 1 || s.Critical != 2 {
+		t.Errorf("Summarize() = %+v, want 2 normal, 1 warning, 2 critical", s)
+	}
+	if s.LatestCritical == nil || s.LatestCritical.ID != "4" {
+		t.Errorf("LatestCritical = %+v, want entry 4", s.LatestCritical)
+	}
+
+	if s := (&SELData{}).Summarize(); s.LatestCritical != nil || s.TotalCount != 0 {
+		t.Errorf("empty Summarize() = %+v", s)
+	}
+}
+
+func FuzzParseSEL(f *testing.F) {
+	f.Add("1|2024-01-01 12:00:00|Normal|Boot\n2|2024-01-01 12:05:00|Warning|Temp high")
+	f.Add("1;2024-01-01;Critical;PSU lost")
+	f.Add("<![CDATA[1|t|Critical|a &amp;gt; b]]>")
+	f.Add("garbage without delimiters")
+	f.Add("||||\n;;;;\n\x00|\x00|\x00|\x00")
+	f.Fuzz(func(t *testing.T, raw string) {
+		entries := parseSEL(raw)
+		if len(entries) > strings.Count(raw, "\n")+1 {
+			t.Fatalf("%d entries from %d lines", len(entries), strings.Count(raw, "\n")+1)
+		}
+		for _, e := range entries {
+			if e.ID == "" {
+				t.Fatalf("entry without ID: %+v", e)
+			}
+			if strings.ContainsRune(e.ID+e.Timestamp+e.Severity+e.Description, 0) {
+				t.Fatalf("NUL left in entry: %+v", e)
+			}
+		}
+	})
+}
+
+func FuzzParseSELLine(f *testing.F) {
+	f.Add("1|2024-01-01 12:00:00|Normal|Boot")
+	f.Add("1;2024-01-01;Critical;a;b;c")
+	f.Add("&#124;|&amp;|<![CDATA[|]]>")
+	f.Fuzz(func(t *testing.T, line string) {
+		e := parseSELLine(line)
+		if strings.Contains(line, "|") && strings.Count(line, "|") >= 3 && e.ID == "0" && e.Severity == "Unknown" {
+			t.Fatalf("pipe-delimited line fell back to description: %q", line)
+		}
+	})
+}
@@ -0,0 +1,393 @@
+package idrac
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SensorReading represents a single sensor value.
+type SensorReading struct {
+	Name     string  `json:"name"`
+	RawName  string  `json:"rawName,omitempty"`
+	Value    float64 `json:"value"`
+	Unit     string  `json:"unit"`
+	Status   string  `json:"status"`
+	Warning  float64 `json:"warning,omitempty"`
+	Critical float64 `json:"critical,omitempty"`
+	// MinWarning and MinCritical are low-side thresholds: a stopped fan or
+	// an undervolted rail fails low. Negative rails (e.g. -12V) have
+	// negative thresholds.
+	MinWarning  float64 `json:"minWarning,omitempty"`
+	MinCritical float64 `json:"minCritical,omitempty"`
+	// Health is SeverityNormal, SeverityWarning, or SeverityCritical as
+	// judged from the reading and the thresholds that matter for its type
+	// (high for temperatures, low for fans, both for voltages),
+	// SensorUnknown when the reading could not be parsed, or empty when the
+	// sensor has none (e.g. power-good rails).
+	Health string `json:"health,omitempty"`
+}
+
+// SensorUnknown is the Health of a sensor that has thresholds but whose
+// reading is unreadable, such as a fan the iDRAC reports as "N/A".
+const SensorUnknown = "unknown"
+
+// SensorData holds all sensor readings grouped by type.
+type SensorData struct {
+	Temperatures []SensorReading `json:"temperatures"`
+	Fans         []SensorReading `json:"fans"`
+	Voltages     []SensorReading `json:"voltages"`
+	// Errors maps a sensor type that could not be read ("temperatures",
+	// "fans", or "voltages") to why, so a failed type is not mistaken for
+	// one with no sensors.
+	Errors map[string]string `json:"errors,omitempty"`
+}
+
+// XML structures for iDRAC6 sensor responses
+// The actual format uses nested XML elements:
+// <root><sensortype><thresholdSensorList><sensor>...</sensor></thresholdSensorList></sensortype></root>
+
+type sensorXMLRoot struct {
+	XMLName  xml.Name       `xml:"root"`
+	Sensors  sensorTypeWrap `xml:"sensortype"`
+	PowerOn  string         `xml:"powerOn"`
+	RawTemps string         `xml:"temperatures"`
+	RawFans  string         `xml:"fans"`
+	RawVolts string         `xml:"voltages"`
+}
+
+type sensorTypeWrap struct {
+	SensorID  string        `xml:"sensorid"`
+	Threshold sensorListXML `xml:"thresholdSensorList"`
+}
+
+type sensorListXML struct {
+	Sensors []sensorXML `xml:"sensor"`
+}
+
+type sensorXML struct {
+	Status     string `xml:"sensorStatus"`
+	Name       string `xml:"name"`
+	Reading    string `xml:"reading"`
+	Units      string `xml:"units"`
+	MinWarning string `xml:"minWarning"`
+	MaxWarning string `xml:"maxWarning"`
+	MinFailure string `xml:"minFailure"`
+	MaxFailure string `xml:"maxFailure"`
+}
+
+// sensorTypes are the sensor groups GetSensors reads, in result order.
+var sensorTypes = []string{"temperatures", "fans", "voltages"}
+
+// GetSensors returns all sensor readings (temperatures, fans, voltages).
+// Makes separate requests for each sensor type, concurrently, since
+// iDRAC6 returns different XML structures per type. A type that fails is
+// left empty and recorded in SensorData.Errors.
+func (c *Client) GetSensors() (*SensorData, error) {
+	return c.GetSensorsContext(context.Background())
+}
+
+// GetSensorsContext is GetSensors with a context for cancellation and tracing.
+func (c *Client) GetSensorsContext(ctx context.Context) (*SensorData, error) {
+	readings := make([][]SensorReading, len(sensorTypes))
+	errs := make([]error, len(sensorTypes))
+	var wg sync.WaitGroup
+	for i, sensorType := range sensorTypes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readings[i], errs[i] = c.getSensorType(ctx, sensorType)
+		}()
+	}
+	wg.Wait()
+
+	result := &SensorData{Temperatures: readings[0], Fans: readings[1], Voltages: readings[2]}
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if result.Errors == nil {
+			result.Errors = make(map[string]string)
+		}
+		result.Errors[sensorTypes[i]] = err.Error()
+	}
+
+	return result, nil
+}
+
+// getSensorType fetches and parses a single sensor type.
+func (c *Client) getSensorType(ctx context.Context, sensorType string) ([]SensorReading, error) {
+	data, err := c.GetContext(ctx, sensorType)
+	if err != nil {
+		return nil, fmt.Errorf("getting %s: %w", sensorType, err)
+	}
+
+	// Try XML element format first (proper iDRAC6 response)
+	var root sensorXMLRoot
+	if err := decodeXML(data, &root); err == nil {
+		if len(root.Sensors.Threshold.Sensors) > 0 {
+			return parseXMLSensors(root.Sensors.Threshold.Sensors, sensorType), nil
+		}
+	}
+
+	// Fallback: try legacy pipe-delimited string format
+	raw := extractRawSensorString(data, sensorType)
+	if raw != "" {
+		unit := sensorTypeUnit(sensorType)
+		return parseLegacySensors(raw, unit), nil
+	}
+
+	return nil, nil
+}
+
+// GetTemperatures returns temperature sensor readings.
+func (c *Client) GetTemperatures() ([]SensorReading, error) {
+	return c.getSensorType(context.Background(), "temperatures")
+}
+
+// parseXMLSensors converts XML sensor elements of the given type
+// ("temperatures", "fans", or "voltages") to SensorReadings.
+func parseXMLSensors(sensors []sensorXML, sensorType string) []SensorReading {
+	var readings []SensorReading
+	for _, s := range sensors {
+		if sensorType == "voltages" {
+			readings = append(readings, parseVoltageSensor(s))
+			continue
+		}
+
+		r := SensorReading{
+			Name:   cleanText(s.Name),
+			Value:  parseFloat(cleanText(s.Reading)),
+			Unit:   cleanText(s.Units),
+			Status: strings.ToLower(cleanText(s.Status)),
+		}
+
+		// Use maxWarning/maxFailure as thresholds
+		if w := parseFloat(cleanText(s.MaxWarning)); w > 0 {
+			r.Warning = w
+		}
+		if c := parseFloat(cleanText(s.MaxFailure)); c > 0 {
+			r.Critical = c
+		}
+		// and keep the low side: a stopped fan fails low.
+		r.MinWarning, _ = parseSignedValue(s.MinWarning)
+		r.MinCritical, _ = parseSignedValue(s.MinFailure)
+		r.Health = xmlSensorHealth(s, sensorType)
+
+		readings = append(readings, r)
+	}
+	return readings
+}
+
+// parseVoltageSensor converts a voltage rail, keeping both threshold
+// sides. Readings and thresholds may be signed ("+12.1", "-11.9") or carry
+// a unit suffix ("3.31 V"); names such as "System Board 3.3V" are kept as
+// they are.
+func parseVoltageSensor(s sensorXML) SensorReading {
+	r := SensorReading{
+		Name:   cleanText(s.Name),
+		Unit:   cleanText(s.Units),
+		Status: strings.ToLower(cleanText(s.Status)),
+	}
+	r.Value, _ = parseSignedValue(s.Reading)
+	r.MinWarning, _ = parseSignedValue(s.MinWarning)
+	r.Warning, _ = parseSignedValue(s.MaxWarning)
+	r.MinCritical, _ = parseSignedValue(s.MinFailure)
+	r.Critical, _ = parseSignedValue(s.MaxFailure)
+	r.Health = xmlSensorHealth(s, "voltages")
+	return r
+}
+
+// HealthBounds returns which threshold sides decide a sensor type's
+// health: temperatures fail hot, fans fail slow (a stopped fan reads
+// zero), and voltage rails fail either way.
+func HealthBounds(sensorType string) (low, high bool) {
+	switch sensorType {
+	case "temperatures":
+		return false, true
+	case "fans":
+		return true, false
+	}
+	return true, true
+}
+
+// parseSignedValue parses a reading or threshold that may carry a sign,
+// a Unicode minus (or "&minus;"), or a trailing unit. ok is false for "N/A", blanks, and
+// anything non-numeric.
+func parseSignedValue(s string) (v float64, ok bool) {
+	s = strings.ReplaceAll(cleanText(s), "\u2212", "-")
+	s = strings.TrimSpace(strings.TrimRightFunc(s, func(r rune) bool {
+		return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == ' '
+	}))
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil
+}
+
+// xmlSensorHealth judges a sensor element on the threshold sides that
+// matter for its type. Any threshold the iDRAC reports counts, including
+// 0; only a blank or "N/A" one is unset. A sensor with thresholds but an
+// unreadable reading (a missing fan reads "N/A") is SensorUnknown.
+func xmlSensorHealth(s sensorXML, sensorType string) string {
+	low, high := HealthBounds(sensorType)
+	bound := func(raw string, side bool) float64 {
+		if v, ok := parseSignedValue(raw); ok && side {
+			return v
+		}
+		return math.NaN()
+	}
+	value, ok := parseSignedValue(s.Reading)
+	if !ok {
+		value = math.NaN()
+	}
+	return judgeHealth(value,
+		bound(s.MinWarning, low), bound(s.MinFailure, low),
+		bound(s.MaxWarning, high), bound(s.MaxFailure, high))
+}
+
+// SensorHealth judges a reading against its non-zero thresholds on the
+// sides HealthBounds gives for its type. It is for readings built in code,
+// such as demo sensors or ones with configured thresholds, where a zero
+// threshold means none; parsed readings already carry Health, judged
+// with any threshold the iDRAC reports, 0 included.
+func SensorHealth(r SensorReading, low, high bool) string {
+	bound := func(v float64, side bool) float64 {
+		if v == 0 || !side {
+			return math.NaN()
+		}
+		return v
+	}
+	return judgeHealth(r.Value,
+		bound(r.MinWarning, low), bound(r.MinCritical, low),
+		bound(r.Warning, high), bound(r.Critical, high))
+}
+
+// judgeHealth compares value with its bounds, NaN marking a bound the
+// sensor does not have: at or beyond a critical bound is SeverityCritical,
+// at or beyond a warning bound SeverityWarning. A sensor with no bounds
+// has no health, and a NaN value with bounds is SensorUnknown.
+func judgeHealth(value, minWarn, minCrit, maxWarn, maxCrit float64) string {
+	switch {
+	case math.IsNaN(minWarn) && math.IsNaN(minCrit) && math.IsNaN(maxWarn) && math.IsNaN(maxCrit):
+		return ""
+	case math.IsNaN(value):
+		return SensorUnknown
+	case value >= maxCrit, value <= minCrit:
+		return SeverityCritical
+	case value >= maxWarn, value <= minWarn:
+		return SeverityWarning
+	}
+	return SeverityNormal
+}
+
+// extractRawSensorString extracts a raw sensor string from XML for legacy format.
+func extractRawSensorString(data []byte, sensorType string) string {
+	type genericRoot struct {
+		XMLName      xml.Name `xml:"root"`
+		Temperatures string   `xml:"temperatures"`
+		Fans         string   `xml:"fans"`
+		Voltages     string   `xml:"voltages"`
+	}
+
+	var gr genericRoot
+	if err := decodeXML(data, &gr); err != nil {
+		return ""
+	}
+
+	switch sensorType {
+	case "temperatures":
+		return gr.Temperatures
+	case "fans":
+		return gr.Fans
+	case "voltages":
+		return gr.Voltages
+	}
+	return ""
+}
+
+func sensorTypeUnit(sensorType string) string {
+	switch sensorType {
+	case "temperatures":
+		return "C"
+	case "fans":
+		return "RPM"
+	case "voltages":
+		return "V"
+	default:
+		return ""
+	}
+}
+
+// parseLegacySensors handles the pipe-delimited format: "Name=value;status;warn;crit|..."
+func parseLegacySensors(raw string, unit string) []SensorReading {
+	var readings []SensorReading
+	for _, entry := range splitSensors(raw) {
+		r := parseSensorEntry(entry, unit)
+		if r.Name != "" {
+			readings = append(readings, r)
+		}
+	}
+	return readings
+}
+
+// splitSensors splits a raw sensor string into individual entries.
+func splitSensors(raw string) []string {
+	raw = strings.TrimSpace(stripCDATA(raw))
+	if raw == "" {
+		return nil
+	}
+	if strings.Contains(raw, "|") {
+		return strings.Split(raw, "|")
+	}
+	if strings.Contains(raw, "\n") {
+		return strings.Split(raw, "\n")
+	}
+	return []string{raw}
+}
+
+// parseSensorEntry parses a single legacy sensor entry like "Inlet Temp=23;ok;42;47"
+func parseSensorEntry(entry string, unit string) SensorReading {
+	entry = strings.TrimSpace(entry)
+	if entry == "" {
+		return SensorReading{}
+	}
+
+	r := SensorReading{Unit: unit, Status: "ok"}
+
+	parts := strings.SplitN(entry, "=", 2)
+	if len(parts) == 2 {
+		r.Name = cleanText(parts[0])
+		fields := strings.Split(parts[1], ";")
+		if len(fields) >= 1 {
+			r.Value = parseFloat(fields[0])
+		}
+		if len(fields) >= 2 {
+			r.Status = cleanText(fields[1])
+		}
+		if len(fields) >= 3 {
+			r.Warning = parseFloat(fields[2])
+		}
+		if len(fields) >= 4 {
+			r.Critical = parseFloat(fields[3])
+		}
+	} else {
+		r.Name = cleanText(entry)
+	}
+
+	return r
+}
+
+func parseFloat(s string) float64 {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "N/A" {
+		return 0
+	}
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
@@ -0,0 +1,420 @@
+package idrac
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/williamzujkowski/idrac6-manager/pkg/idrac/idractest"
+)
+
+func TestParseXMLSensors(t *testing.T) {
+	sensors := []sensorXML{
+		{
+			Status:     "Normal",
+			Name:       "system board ambient",
+			Reading:    "20",
+			Units:      "C",
+			MinWarning: "8",
+			MaxWarning: "42",
+			MinFailure: "3",
+			MaxFailure: "47",
+		},
+		{
+			Status:     "Normal",
+			Name:       "cpu1",
+			Reading:    "45",
+			Units:      "C",
+			MaxWarning: "85",
+			MaxFailure: "90",
+		},
+	}
+
+	readings := parseXMLSensors(sensors, "temperatures")
+	if len(readings) != 2 {
+		t.Fatalf("got %d readings, want 2", len(readings))
+	}
+
+	if readings[0].Name != "system board ambient" {
+		t.Errorf("name = %q, want system board ambient", readings[0].Name)
+	}
+	if readings[0].Value != 20 {
+		t.Errorf("value = %f, want 20", readings[0].Value)
+	}
+	if readings[0].Unit != "C" {
+		t.Errorf("unit = %q, want C", readings[0].Unit)
+	}
+	if readings[0].Status != "normal" {
+		t.Errorf("status = %q, want normal", readings[0].Status)
+	}
+	if readings[0].Warning != 42 {
+		t.Errorf("warning = %f, want 42", readings[0].Warning)
+	}
+	if readings[0].Critical != 47 {
+		t.Errorf("critical = %f, want 47", readings[0].Critical)
+	}
+	if readings[0].MinWarning != 8 || readings[0].MinCritical != 3 {
+		t.Errorf("min thresholds = %f/%f, want 8/3", readings[0].MinWarning, readings[0].MinCritical)
+	}
+	if readings[0].Health != SeverityNormal {
+		t.Errorf("health = %q, want normal", readings[0].Health)
+	}
+}
+
+func TestGetSensors_EncodedPayloads(t *testing.T) {
+	temps := `<sensortype><sensorid>1</sensorid><thresholdSensorList>` +
+		`<sensor><sensorStatus><![CDATA[Normal]]></sensorStatus><name><![CDATA[Inlet &amp; Exhaust]]></name>` +
+		`<reading>23</reading><units>degrees&amp;nbsp;C</units><maxFailure>&lt;![CDATA[47]]&gt;</maxFailure></sensor>` +
+		`<sensor><sensorStatus>Normal</sensorStatus><name>CPU1 &amp;gt; Core</name><reading>45</reading><units>C</units></sensor>` +
+		`</thresholdSensorList></sensortype>`
+	srv := idractest.NewServer(idractest.Options{Data: map[string]string{"temperatures": temps}})
+	defer srv.Close()
+
+	temperatures, err := NewClient(srv.Addr(), "root", "calvin").GetTemperatures()
+	if err != nil {
+		t.Fatalf("GetTemperatures() error = %v", err)
+	}
+	if len(temperatures) != 2 {
+		t.Fatalf("readings = %+v, want 2", temperatures)
+	}
+	if r := temperatures[0]; r.Name != "Inlet & Exhaust" || r.Unit != "degrees C" || r.Status != "normal" || r.Critical != 47 {
+		t.Errorf("CDATA reading = %+v", r)
+	}
+	if r := temperatures[1]; r.Name != "CPU1 > Core" {
+		t.Errorf("double-encoded name = %q", r.Name)
+	}
+
+	legacy := parseLegacySensors("<![CDATA[Fan &amp; PSU=3600;ok;;|FAN 2=3480;ok;;]]>", "RPM")
+	if len(legacy) != 2 || legacy[0].Name != "Fan & PSU" || legacy[1].Value != 3480 {
+		t.Errorf("legacy readings = %+v", legacy)
+	}
+}
+
+func TestGetSensors_PartialFailure(t *testing.T) {
+	srv := idractest.NewServer(idractest.Options{})
+	defer srv.Close()
+	failFans := func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Query().Get("get") == "fans" {
+				return nil, errors.New("connection reset")
+			}
+			return next.RoundTrip(req)
+		})
+	}
+
+	data, err := NewClient(srv.Addr(), "root", "calvin", WithMiddleware(failFans)).GetSensors()
+	if err != nil {
+		t.Fatalf("GetSensors() error = %v", err)
+	}
+	if len(data.Temperatures) == 0 || len(data.Voltages) == 0 || len(data.Fans) != 0 {
+		t.Errorf("readings = %+v, want temperatures and voltages only", data)
+	}
+	if len(data.Errors) != 1 || !strings.Contains(data.Errors["fans"], "connection reset") {
+		t.Errorf("Errors = %v, want the fans failure", data.Errors)
+	}
+
+	data, err = NewClient(srv.Addr(), "root", "calvin").GetSensors()
+	if err != nil || data.Errors != nil {
+		t.Errorf("GetSensors() = %+v, %v; want no errors", data, err)
+	}
+}
+
+func TestParseXMLSensors_Fans(t *testing.T) {
+	sensors := []sensorXML{
+		{
+			Status:     "Normal",
+			Name:       "system board 1",
+			Reading:    "1440",
+			Units:      "RPM",
+			MinWarning: "N/A",
+			MaxWarning: "N/A",
+			MinFailure: "720",
+			MaxFailure: "N/A",
+		},
+	}
+
+	readings := parseXMLSensors(sensors, "fans")
+	if len(readings) != 1 {
+		t.Fatalf("got %d readings, want 1", len(readings))
+	}
+	if readings[0].Value != 1440 {
+		t.Errorf("value = %f, want 1440", readings[0].Value)
+	}
+	// N/A should parse to 0
+	if readings[0].Warning != 0 {
+		t.Errorf("warning = %f, want 0 (N/A)", readings[0].Warning)
+	}
+	if readings[0].MinCritical != 720 || readings[0].Health != SeverityNormal {
+		t.Errorf("minCritical = %f, health = %q; want 720, normal", readings[0].MinCritical, readings[0].Health)
+	}
+}
+
+func TestSensorHealthBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		sensorType string
+		sensor     sensorXML
+		want       string
+	}{
+		{"stopped fan", "fans", sensorXML{Reading: "0", MinWarning: "1200", MinFailure: "720"}, SeverityCritical},
+		{"slow fan", "fans", sensorXML{Reading: "960", MinWarning: "1200", MinFailure: "720"}, SeverityWarning},
+		{"fan max side ignored", "fans", sensorXML{Reading: "9000", MinFailure: "720", MaxFailure: "8000"}, SeverityNormal},
+		{"hot CPU", "temperatures", sensorXML{Reading: "91", MaxWarning: "85", MaxFailure: "90"}, SeverityCritical},
+		{"cold inlet ignored", "temperatures", sensorXML{Reading: "2", MinFailure: "3", MaxFailure: "47"}, SeverityNormal},
+		{"undervolt", "voltages", sensorXML{Reading: "2.90", MinFailure: "2.97", MaxFailure: "3.63"}, SeverityCritical},
+		{"overvolt", "voltages", sensorXML{Reading: "3.60", MaxWarning: "3.56", MaxFailure: "3.63"}, SeverityWarning},
+		{"fan without low thresholds", "fans", sensorXML{Reading: "0", MaxFailure: "8000"}, ""},
+		{"unreadable fan", "fans", sensorXML{Reading: "N/A", MinWarning: "1200", MinFailure: "720"}, SensorUnknown},
+		{"unreadable without thresholds", "fans", sensorXML{Reading: "N/A", MinFailure: "N/A"}, ""},
+		{"zero floor", "voltages", sensorXML{Reading: "-0.10", MinFailure: "0", MaxFailure: "1.50"}, SeverityCritical},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readings := parseXMLSensors([]sensorXML{tt.sensor}, tt.sensorType)
+			if got := readings[0].Health; got != tt.want {
+				t.Errorf("health = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseXMLSensors_Voltages(t *testing.T) {
+	// Rails as an R710 reports them: signed readings, a negative rail,
+	// unit suffixes, and discrete power-good sensors without thresholds.
+	sensors := []sensorXML{
+		{Status: "Normal", Name: "System Board 3.3V", Reading: "3.31", Units: "Volts", MinWarning: "3.04", MaxWarning: "3.56", MinFailure: "2.97", MaxFailure: "3.63"},
+		{Status: "Normal", Name: "System Board 12V", Reading: "+12.10", Units: "Volts", MinFailure: "+10.80", MaxFailure: "+13.20"},
+		{Status: "Normal", Name: "System Board -12V", Reading: "-11.95", Units: "Volts", MinFailure: "-13.20", MaxFailure: "-10.80"},
+		{Status: "Normal", Name: "System Board 5V", Reading: "4.70 V", Units: "Volts", MinWarning: "4.75", MinFailure: "4.50", MaxWarning: "5.25", MaxFailure: "5.50"},
+		{Status: "Critical", Name: "CPU1 VCORE", Reading: "0.70", Units: "Volts", MinFailure: "0.80", MaxFailure: "1.50"},
+		{Status: "Normal", Name: "CPU1 VCORE PG", Reading: "1", MinWarning: "N/A", MaxWarning: "N/A", MinFailure: "N/A", MaxFailure: "N/A"},
+	}
+
+	readings := parseXMLSensors(sensors, "voltages")
+	if len(readings) != len(sensors) {
+		t.Fatalf("got %d readings, want %d", len(readings), len(sensors))
+	}
+
+	want := []struct {
+		name                                string
+		value, minWarn, warn, minCrit, crit float64
+		health                              string
+	}{
+		{"System Board 3.3V", 3.31, 3.04, 3.56, 2.97, 3.63, SeverityNormal},
+		{"System Board 12V", 12.10, 0, 0, 10.80, 13.20, SeverityNormal},
+		{"System Board -12V", -11.95, 0, 0, -13.20, -10.80, SeverityNormal},
+		{"System Board 5V", 4.70, 4.75, 5.25, 4.50, 5.50, SeverityWarning},
+		{"CPU1 VCORE", 0.70, 0, 0, 0.80, 1.50, SeverityCritical},
+		{"CPU1 VCORE PG", 1, 0, 0, 0, 0, ""},
+	}
+	for i, w := range want {
+		r := readings[i]
+		if r.Name != w.name || r.Value != w.value || r.MinWarning != w.minWarn || r.Warning != w.warn ||
+			r.MinCritical != w.minCrit || r.Critical != w.crit || r.Health != w.health {
+			t.Errorf("reading %d = %+v, want %+v", i, r, w)
+		}
+	}
+}
+
+func TestParseSignedValue(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   float64
+		wantOK bool
+	}{
+		{"3.31", 3.31, true},
+		{"+12.10", 12.10, true},
+		{"-11.95", -11.95, true},
+		{"\u221211.95", -11.95, true},
+		{" 4.70 V ", 4.70, true},
+		{"1.21Volts", 1.21, true},
+		{"N/A", 0, false},
+		{"", 0, false},
+		{"Good", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseSignedValue(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseSignedValue(%q) = %v, %v; want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseLegacySensors(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		unit      string
+		wantCount int
+		wantFirst string
+		wantValue float64
+	}{
+		{
+			name:      "pipe delimited",
+			raw:       "Inlet Temp=23;ok;42;47|Exhaust Temp=35;ok;70;75",
+			unit:      "C",
+			wantCount: 2,
+			wantFirst: "Inlet Temp",
+			wantValue: 23,
+		},
+		{
+			name:      "single sensor",
+			raw:       "CPU Temp=65;ok;90;95",
+			unit:      "C",
+			wantCount: 1,
+			wantFirst: "CPU Temp",
+			wantValue: 65,
+		},
+		{
+			name:      "empty string",
+			raw:       "",
+			unit:      "C",
+			wantCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readings := parseLegacySensors(tt.raw, tt.unit)
+			if len(readings) != tt.wantCount {
+				t.Errorf("got %d readings, want %d", len(readings), tt.wantCount)
+			}
+			if tt.wantCount > 0 && readings[0].Name != tt.wantFirst {
+				t.Errorf("first name = %q, want %q", readings[0].Name, tt.wantFirst)
+			}
+			if tt.wantCount > 0 && readings[0].Value != tt.wantValue {
+				t.Errorf("first value = %f, want %f", readings[0].Value, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestParseSensorEntry(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    string
+		unit     string
+		wantName string
+		wantVal  float64
+		wantStat string
+		wantWarn float64
+		wantCrit float64
+	}{
+		{
+			name:     "full entry",
+			entry:    "Inlet Temp=23;ok;42;47",
+			unit:     "C",
+			wantName: "Inlet Temp",
+			wantVal:  23,
+			wantStat: "ok",
+			wantWarn: 42,
+			wantCrit: 47,
+		},
+		{
+			name:     "value only",
+			entry:    "Fan1=5400",
+			unit:     "RPM",
+			wantName: "Fan1",
+			wantVal:  5400,
+			wantStat: "ok",
+		},
+		{
+			name:  "empty entry",
+			entry: "",
+			unit:  "C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := parseSensorEntry(tt.entry, tt.unit)
+			if r.Name != tt.wantName {
+				t.Errorf("Name = %q, want %q", r.Name, tt.wantName)
+			}
+			if r.Value != tt.wantVal {
+				t.Errorf("Value = %f, want %f", r.Value, tt.wantVal)
+			}
+			if r.Status != tt.wantStat && tt.wantStat != "" {
+				t.Errorf("Status = %q, want %q", r.Status, tt.wantStat)
+			}
+		})
+	}
+}
+
+func TestSplitSensors(t *testing.T) {
+	tests := []struct {
+		name      string
+		raw       string
+		wantCount int
+	}{
+		{"pipe delimited", "a|b|c", 3},
+		{"newline delimited", "a\nb\nc", 3},
+		{"single", "abc", 1},
+		{"empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := splitSensors(tt.raw)
+			if len(result) != tt.wantCount {
+				t.Errorf("got %d entries, want %d", len(result), tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  float64
+	}{
+		{"42", 42},
+		{"3.14", 3.14},
+		{" 23 ", 23},
+		{"", 0},
+		{"abc", 0},
+		{"N/A", 0},
+	}
+
+	for _, tt := range tests {
+		got := parseFloat(tt.input)
+		if got != tt.want {
+			t.Errorf("parseFloat(%q) = %f, want %f", tt.input, got, tt.want)
+		}
+	}
+}
+
+func FuzzParseSensorEntry(f *testing.F) {
+	f.Add("Inlet Temp=23;ok;42;47", "C")
+	f.Add("FAN 1=N/A;;;", "RPM")
+	f.Add("=;;;;;;", "")
+	f.Add("name==1e400;\x00;-Inf;NaN", "V")
+	f.Fuzz(func(t *testing.T, entry, unit string) {
+		r := parseSensorEntry(entry, unit)
+		if strings.TrimSpace(entry) != "" && r.Unit != unit {
+			t.Fatalf("unit = %q, want %q", r.Unit, unit)
+		}
+		if strings.ContainsRune(r.Name+r.Status, 0) {
+			t.Fatalf("NUL left in reading: %+v", r)
+		}
+	})
+}
+
+func FuzzSplitSensors(f *testing.F) {
+	f.Add("Inlet=23;ok;42;47|CPU1=45;ok;85;90")
+	f.Add("FAN 1=3600\nFAN 2=3480")
+	f.Add("<![CDATA[a=1|b=2]]>")
+	f.Add("|||\n\n")
+	f.Fuzz(func(t *testing.T, raw string) {
+		entries := splitSensors(raw)
+		if len(entries) > len(raw)+1 {
+			t.Fatalf("%d entries from %d bytes", len(entries), len(raw))
+		}
+		for _, r := range parseLegacySensors(raw, "C") {
+			if r.Name == "" {
+				t.Fatalf("reading without a name from %q", raw)
+			}
+		}
+	})
+}
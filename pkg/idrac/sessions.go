@@ -0,0 +1,83 @@
+package idrac
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Session is an active iDRAC6 session as reported by "racadm getssninfo".
+type Session struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	User      string `json:"user"`
+	IP        string `json:"ip"`
+	LoginTime string `json:"loginTime"`
+}
+
+// ListSessions returns the active iDRAC sessions.
+func (a *Admin) ListSessions() ([]Session, error) {
+	output, err := a.racadm.Run("getssninfo")
+	if err != nil {
+		return nil, fmt.Errorf("listing sessions: %w", err)
+	}
+	return parseSessions(output), nil
+}
+
+// consoleSessionTypes are the getssninfo Type values firmware versions use
+// for virtual console (KVM) sessions, lower-cased.
+var consoleSessionTypes = map[string]bool{"vkvm": true, "kvm": true, "vconsole": true, "virtualconsole": true}
+
+// ConsoleSessions returns the active virtual console sessions, so callers
+// can check whether someone is already connected before launching KVM.
+func (a *Admin) ConsoleSessions() ([]Session, error) {
+	sessions, err := a.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	console := []Session{}
+	for _, s := range sessions {
+		if consoleSessionTypes[strings.ToLower(s.Type)] {
+			console = append(console, s)
+		}
+	}
+	return console, nil
+}
+
+// KillSession closes the session with the given ID. iDRAC6 refuses new
+// logins once its session table is full, so this frees stuck sessions.
+func (a *Admin) KillSession(id string) error {
+	if _, err := strconv.Atoi(id); err != nil {
+		return fmt.Errorf("invalid session id %q", id)
+	}
+	if _, err := a.racadm.Run("closessn", "-i", id); err != nil {
+		return fmt.Errorf("closing session %s: %w", id, err)
+	}
+	return nil
+}
+
+// parseSessions parses the getssninfo table:
+//
+//	SSNID Type  User  IP Address     Login Date/Time
+//	---------------------------------------------------
+//	6     SSH   root  192.168.0.10   04/07/2010 12:00:34
+func parseSessions(output string) []Session {
+	var sessions []Session
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 {
+			continue
+		}
+		if _, err := strconv.Atoi(fields[0]); err != nil {
+			continue // header, separator, or noise
+		}
+		sessions = append(sessions, Session{
+			ID:        fields[0],
+			Type:      fields[1],
+			User:      fields[2],
+			IP:        fields[3],
+			LoginTime: strings.Join(fields[4:], " "),
+		})
+	}
+	return sessions
+}
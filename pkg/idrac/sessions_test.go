@@ -0,0 +1,76 @@
+package idrac
+
+import (
+	"strings"
+	"testing"
+)
+
+// fakeRACADM records commands and returns canned output.
+type fakeRACADM struct {
+	output string
+	err    error
+	calls  []string
+}
+
+func (f *fakeRACADM) Run(args ...string) (string, error) {
+	f.calls = append(f.calls, strings.Join(args, " "))
+	return f.output, f.err
+}
+
+func TestParseSessions(t *testing.T) {
+	output := `SSNID Type  User  IP Address     Login Date/Time
+-------------------------------------------------------------
+6     SSH   root  192.168.0.10   04/07/2010 12:00:34
+7     GUI   admin 10.0.0.5       04/07/2010 13:15:02
+`
+	sessions := parseSessions(output)
+	if len(sessions) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(sessions))
+	}
+	if sessions[0].ID != "6" || sessions[0].Type != "SSH" || sessions[0].User != "root" {
+		t.Errorf("unexpected first session: %+v", sessions[0])
+	}
+	if sessions[1].IP != "10.0.0.5" {
+		t.Errorf("IP = %q, want 10.0.0.5", sessions[1].IP)
+	}
+	if sessions[1].LoginTime != "04/07/2010 13:15:02" {
+		t.Errorf("LoginTime = %q, want 04/07/2010 13:15:02", sessions[1].LoginTime)
+	}
+}
+
+func TestKillSession(t *testing.T) {
+	fake := &fakeRACADM{}
+	a := &Admin{racadm: fake}
+
+	if err := a.KillSession("6"); err != nil {
+		t.Fatalf("KillSession() error = %v", err)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "closessn -i 6" {
+		t.Errorf("calls = %v, want [closessn -i 6]", fake.calls)
+	}
+
+	if err := a.KillSession("6; racadm racreset"); err == nil {
+		t.Error("KillSession() should reject non-numeric IDs")
+	}
+}
+
+func TestConsoleSessions(t *testing.T) {
+	fake := &fakeRACADM{output: `SSNID Type  User  IP Address     Login Date/Time
+-------------------------------------------------------------
+6     SSH   root  192.168.0.10   04/07/2010 12:00:34
+7     GUI   admin 10.0.0.5       04/07/2010 13:15:02
+8     VKVM  admin 10.0.0.5       04/07/2010 13:16:40
+`}
+	sessions, err := (&Admin{racadm: fake}).ConsoleSessions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 1 || sessions[0].ID != "8" {
+		t.Errorf("ConsoleSessions() = %+v, want only session 8", sessions)
+	}
+
+	fake.output = "SSNID Type User IP Address Login Date/Time\n"
+	if sessions, _ := (&Admin{racadm: fake}).ConsoleSessions(); sessions == nil || len(sessions) != 0 {
+		t.Errorf("ConsoleSessions() = %#v, want empty non-nil slice", sessions)
+	}
+}
@@ -0,0 +1,106 @@
+package idrac
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+)
+
+// SystemInfo holds system identification data.
+type SystemInfo struct {
+	Hostname    string `json:"hostname"`
+	Model       string `json:"model"`
+	ServiceTag  string `json:"serviceTag"`
+	BIOSVersion string `json:"biosVersion"`
+	FWVersion   string `json:"fwVersion"`
+	LCCVersion  string `json:"lccVersion"`
+	OSName      string `json:"osName,omitempty"`
+}
+
+type sysInfoResponse struct {
+	XMLName      xml.Name `xml:"root"`
+	HostName     string   `xml:"hostName"`
+	SysDesc      string   `xml:"sysDesc"`
+	SysRev       string   `xml:"sysRev"`
+	BiosVer      string   `xml:"biosVer"`
+	FwVersion    string   `xml:"fwVersion"`
+	LCCfwVersion string   `xml:"LCCfwVersion"`
+	OSName       string   `xml:"osName"`
+	SvcTag       string   `xml:"svcTag"`
+}
+
+// GetSystemInfo returns system identification and firmware info.
+func (c *Client) GetSystemInfo() (*SystemInfo, error) {
+	return c.GetSystemInfoContext(context.Background())
+}
+
+// GetSystemInfoContext is GetSystemInfo with a context for cancellation and tracing.
+func (c *Client) GetSystemInfoContext(ctx context.Context) (*SystemInfo, error) {
+	data, err := c.GetContext(ctx, "hostName", "sysDesc", "sysRev", "biosVer", "fwVersion", "LCCfwVersion", "osName", "svcTag")
+	if err != nil {
+		return nil, fmt.Errorf("getting system info: %w", err)
+	}
+
+	var resp sysInfoResponse
+	if err := decodeXML(data, &resp); err != nil {
+		return nil, fmt.Errorf("parsing system info: %w", err)
+	}
+
+	if resp.FwVersion != "" {
+		c.mu.Lock()
+		c.firmware = resp.FwVersion
+		c.mu.Unlock()
+	}
+
+	return &SystemInfo{
+		Hostname:    resp.HostName,
+		Model:       resp.SysDesc,
+		ServiceTag:  resp.SvcTag,
+		BIOSVersion: resp.BiosVer,
+		FWVersion:   resp.FwVersion,
+		LCCVersion:  resp.LCCfwVersion,
+		OSName:      resp.OSName,
+	}, nil
+}
+
+// FirmwareVersion returns the iDRAC firmware version (e.g. "1.99 (Build 4)"),
+// read at the first login unless given with WithFirmwareVersion and updated
+// by GetSystemInfo. It is "" before the first login or if the read failed.
+func (c *Client) FirmwareVersion() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.firmware
+}
+
+// detectFirmware reads fwVersion with the session just established so
+// feature code can branch on it before the first GetSystemInfo. Failures
+// are ignored; firmware that wants ST2 rejects the read if the login
+// response carried no tokens. Called with c.mu held.
+func (c *Client) detectFirmware(ctx context.Context) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/data?get=fwVersion", nil)
+	if err != nil {
+		return
+	}
+	req.AddCookie(&http.Cookie{Name: c.cookieName, Value: c.sessionID})
+	if c.newAuth && c.st2 != "" {
+		req.Header.Set("ST2", c.st2)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	body, err := readBody(resp)
+	if err != nil {
+		return
+	}
+	var info sysInfoResponse
+	if decodeXML(body, &info) == nil && info.FwVersion != "" {
+		c.firmware = info.FwVersion
+	}
+}
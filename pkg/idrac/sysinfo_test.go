@@ -57,3 +57,40 @@ func TestGetSystemInfo(t *testing.T) {
 		t.Errorf("FWVersion = %q, want 2.92", info.FWVersion)
 	}
 }
+
+func TestFirmwareVersionAtLogin(t *testing.T) {
+	var gets []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start.html":
+			http.SetCookie(w, &http.Cookie{Name: "_appwebSessionId_", Value: "sess"})
+		case "/data/login":
+			fmt.Fprint(w, `<root><authResult>0</authResult><forwardUrl>index.html</forwardUrl></root>`)
+		case "/data":
+			gets = append(gets, r.URL.Query().Get("get"))
+			fmt.Fprint(w, `<root><fwVersion>1.99 (Build 4)</fwVersion></root>`)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("localhost", "root", "calvin")
+	c.baseURL = server.URL
+	c.http = server.Client()
+	if got := c.FirmwareVersion(); got != "" {
+		t.Errorf("FirmwareVersion() before login = %q", got)
+	}
+	if err := c.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.FirmwareVersion(); got != "1.99 (Build 4)" {
+		t.Errorf("FirmwareVersion() = %q, want 1.99 (Build 4)", got)
+	}
+
+	// A known version is not read again on re-login.
+	if err := c.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if len(gets) != 1 || gets[0] != "fwVersion" {
+		t.Errorf("data requests = %v, want one fwVersion read", gets)
+	}
+}
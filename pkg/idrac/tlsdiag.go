@@ -0,0 +1,128 @@
+package idrac
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// legacyCipherSuites are the suites the client offers. iDRAC6 firmware
+// only negotiates TLS 1.0/1.1 with legacy RSA key exchange ciphers, which
+// Go no longer offers by default.
+var legacyCipherSuites = []uint16{
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_RSA_WITH_3DES_EDE_CBC_SHA,
+	tls.TLS_RSA_WITH_AES_128_CBC_SHA256,
+	tls.TLS_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_CBC_SHA,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+}
+
+// legacyTLSConfig returns the TLS settings the client uses for iDRAC6.
+func legacyTLSConfig() *tls.Config {
+	return &tls.Config{
+		InsecureSkipVerify: true, //nolint:gosec // iDRAC6 uses self-signed certs
+		// iDRAC6 only supports TLS 1.0/1.1 with legacy ciphers
+		MinVersion:   tls.VersionTLS10,
+		MaxVersion:   tls.VersionTLS12,
+		CipherSuites: legacyCipherSuites,
+	}
+}
+
+// TLSCertificate describes the certificate a server presented.
+type TLSCertificate struct {
+	Subject    string    `json:"subject"`
+	Issuer     string    `json:"issuer"`
+	NotBefore  time.Time `json:"notBefore"`
+	NotAfter   time.Time `json:"notAfter"`
+	Expired    bool      `json:"expired"`
+	SelfSigned bool      `json:"selfSigned"`
+	DNSNames   []string  `json:"dnsNames,omitempty"`
+}
+
+// TLSDiagnosis is the result of DiagnoseTLS. OfferedCiphersOK reports
+// whether a handshake with the client's own settings succeeded; if not,
+// DefaultCiphersOK reports whether Go's defaults fared better, which
+// distinguishes a cipher/version mismatch from an unreachable host.
+type TLSDiagnosis struct {
+	Address          string          `json:"address"`
+	OfferedCiphersOK bool            `json:"offeredCiphersOk"`
+	DefaultCiphersOK *bool           `json:"defaultCiphersOk,omitempty"`
+	Version          string          `json:"version,omitempty"`
+	CipherSuite      string          `json:"cipherSuite,omitempty"`
+	Certificate      *TLSCertificate `json:"certificate,omitempty"`
+	Error            string          `json:"error,omitempty"`
+	DefaultError     string          `json:"defaultError,omitempty"`
+}
+
+// DiagnoseTLS performs a TLS handshake with addr ("host" or "host:port",
+// port 443 by default, or a URL as accepted by ParseHost) and reports what
+// was negotiated. It needs no
+// credentials, so it works for hosts the client cannot log in to. The
+// certificate is reported but not verified.
+func DiagnoseTLS(ctx context.Context, addr string) *TLSDiagnosis {
+	if strings.Contains(addr, "://") {
+		if u, err := url.Parse(addr); err == nil && u.Host != "" {
+			addr = u.Host
+		}
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, "443")
+	}
+	d := &TLSDiagnosis{Address: addr}
+
+	state, err := handshake(ctx, addr, legacyTLSConfig())
+	if err == nil {
+		d.OfferedCiphersOK = true
+		d.describe(state)
+		return d
+	}
+	d.Error = err.Error()
+
+	defaults := &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10} //nolint:gosec // diagnostics only
+	state, err = handshake(ctx, addr, defaults)
+	ok := err == nil
+	d.DefaultCiphersOK = &ok
+	if ok {
+		d.describe(state)
+	} else {
+		d.DefaultError = err.Error()
+	}
+	return d
+}
+
+func handshake(ctx context.Context, addr string, cfg *tls.Config) (*tls.ConnectionState, error) {
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: 10 * time.Second}, Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	state := conn.(*tls.Conn).ConnectionState()
+	return &state, nil
+}
+
+func (d *TLSDiagnosis) describe(state *tls.ConnectionState) {
+	d.Version = tls.VersionName(state.Version)
+	d.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	if len(state.PeerCertificates) == 0 {
+		return
+	}
+	cert := state.PeerCertificates[0]
+	d.Certificate = &TLSCertificate{
+		Subject:    cert.Subject.String(),
+		Issuer:     cert.Issuer.String(),
+		NotBefore:  cert.NotBefore,
+		NotAfter:   cert.NotAfter,
+		Expired:    time.Now().After(cert.NotAfter),
+		SelfSigned: bytes.Equal(cert.RawIssuer, cert.RawSubject),
+		DNSNames:   cert.DNSNames,
+	}
+}
@@ -0,0 +1,48 @@
+package idrac
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiagnoseTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	d := DiagnoseTLS(context.Background(), strings.TrimPrefix(server.URL, "https://"))
+	if !d.OfferedCiphersOK || d.DefaultCiphersOK != nil {
+		t.Fatalf("diagnosis = %+v, want offered ciphers to succeed", d)
+	}
+	if d.Version != "TLS 1.2" || d.CipherSuite == "" {
+		t.Errorf("negotiated %q / %q", d.Version, d.CipherSuite)
+	}
+	if d.Certificate == nil || d.Certificate.NotAfter.IsZero() {
+		t.Errorf("certificate = %+v", d.Certificate)
+	}
+}
+
+func TestDiagnoseTLS_CipherMismatch(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.NotFoundHandler())
+	server.TLS = &tls.Config{MinVersion: tls.VersionTLS13}
+	server.StartTLS()
+	defer server.Close()
+
+	d := DiagnoseTLS(context.Background(), strings.TrimPrefix(server.URL, "https://"))
+	if d.OfferedCiphersOK || d.Error == "" {
+		t.Fatalf("diagnosis = %+v, want the legacy handshake to fail", d)
+	}
+	if d.DefaultCiphersOK == nil || !*d.DefaultCiphersOK || d.Version != "TLS 1.3" {
+		t.Errorf("diagnosis = %+v, want defaults to negotiate TLS 1.3", d)
+	}
+}
+
+func TestDiagnoseTLS_Unreachable(t *testing.T) {
+	d := DiagnoseTLS(context.Background(), "127.0.0.1:1")
+	if d.OfferedCiphersOK || d.DefaultCiphersOK == nil || *d.DefaultCiphersOK || d.DefaultError == "" {
+		t.Errorf("diagnosis = %+v, want both handshakes to fail", d)
+	}
+}
@@ -0,0 +1,108 @@
+package idrac
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// newAuthMinVersion is the first iDRAC6 firmware release that expects the
+// ST2 header on data requests.
+var newAuthMinVersion = [2]int{2, 92}
+
+// tokenPattern matches ST1/ST2 assignments in a page body, e.g.
+// `ST2=abc`, `var ST2 = "abc";` or `"ST2": "abc"`.
+var tokenPattern = regexp.MustCompile(`\b(ST[12])["']?\s*[=:]\s*["']?([A-Za-z0-9]+)`)
+
+// WithFirmwareVersion declares the controller's firmware version (as shown
+// by SystemInfo.FWVersion) so Login knows to hunt for ST1/ST2 tokens when
+// the login response's forwardUrl carries none. The client also learns the
+// version on its own from GetSystemInfo.
+func WithFirmwareVersion(v string) Option {
+	return func(c *Client) {
+		c.firmware = v
+	}
+}
+
+// requiresNewAuth reports whether firmware version fw (e.g. "2.92 (Build 05)")
+// uses token-based newAuth. Unparseable versions report false.
+func requiresNewAuth(fw string) bool {
+	fields := strings.Fields(fw)
+	if len(fields) == 0 {
+		return false
+	}
+	majorStr, minorStr, ok := strings.Cut(fields[0], ".")
+	if !ok {
+		return false
+	}
+	major, err := strconv.Atoi(majorStr)
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(minorStr)
+	if err != nil {
+		return false
+	}
+	if major != newAuthMinVersion[0] {
+		return major > newAuthMinVersion[0]
+	}
+	return minor >= newAuthMinVersion[1]
+}
+
+// tokensFromHeader picks up ST1/ST2 delivered as response headers, which
+// some firmware builds use instead of the forwardUrl query.
+func (c *Client) tokensFromHeader(h http.Header) {
+	if v := h.Get("ST1"); v != "" {
+		c.st1 = v
+		c.newAuth = true
+	}
+	if v := h.Get("ST2"); v != "" {
+		c.st2 = v
+		c.newAuth = true
+	}
+}
+
+// tokensFromBody picks up ST1/ST2 embedded in a page body.
+func (c *Client) tokensFromBody(body []byte) {
+	for _, m := range tokenPattern.FindAllSubmatch(body, -1) {
+		switch string(m[1]) {
+		case "ST1":
+			c.st1 = string(m[2])
+		case "ST2":
+			c.st2 = string(m[2])
+		}
+		c.newAuth = true
+	}
+}
+
+// fetchTokens requests the login forward page with the new session cookie
+// and looks for ST1/ST2 in its headers and body. Called with c.mu held.
+func (c *Client) fetchTokens(ctx context.Context, forwardURL string) {
+	page, _, _ := strings.Cut(forwardURL, "?")
+	if page == "" {
+		page = "index.html"
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/"+strings.TrimPrefix(page, "/"), nil)
+	if err != nil {
+		return
+	}
+	req.AddCookie(&http.Cookie{Name: c.cookieName, Value: c.sessionID})
+
+	resp, err := c.http.Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		c.tokensFromHeader(resp.Header)
+		if !c.newAuth {
+			if body, rerr := readBody(resp); rerr == nil {
+				c.tokensFromBody(body)
+			}
+		}
+	}
+
+	if !c.newAuth {
+		log.Printf("iDRAC %s: firmware %s expects ST1/ST2 tokens but none were found; continuing with cookie auth", c.host, c.firmware)
+	}
+}
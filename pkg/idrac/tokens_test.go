@@ -0,0 +1,143 @@
+package idrac
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// mockTokenIDRAC logs in with a tokenless forwardUrl. loginST2 is sent as a
+// login response header and pageBody is served as the forward page. Each
+// /data request records the ST2 header it carried.
+func mockTokenIDRAC(t *testing.T, loginST2, pageBody string, gotST2 *[]string, pageHits *int) *httptest.Server {
+	t.Helper()
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start.html":
+			http.SetCookie(w, &http.Cookie{Name: "_appwebSessionId_", Value: "sess"})
+		case "/data/login":
+			if loginST2 != "" {
+				w.Header().Set("ST1", "hdr1")
+				w.Header().Set("ST2", loginST2)
+			}
+			fmt.Fprint(w, `<root><authResult>0</authResult><forwardUrl>index.html</forwardUrl></root>`)
+		case "/index.html":
+			*pageHits++
+			fmt.Fprint(w, pageBody)
+		case "/data":
+			*gotST2 = append(*gotST2, r.Header.Get("ST2"))
+			fmt.Fprint(w, `<root><pwState>1</pwState></root>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestLogin_HeaderDeliveredTokens(t *testing.T) {
+	var got []string
+	var hits int
+	server := mockTokenIDRAC(t, "hdr2", "", &got, &hits)
+	defer server.Close()
+
+	c := NewClient("localhost", "root", "calvin")
+	c.baseURL = server.URL
+	c.http = server.Client()
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if !c.newAuth || c.st1 != "hdr1" || c.st2 != "hdr2" {
+		t.Fatalf("tokens = (%v, %q, %q), want (true, hdr1, hdr2)", c.newAuth, c.st1, c.st2)
+	}
+	if _, err := c.Get("pwState"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	// The login's firmware read and the Get both carry the token.
+	if len(got) != 2 || got[0] != "hdr2" || got[1] != "hdr2" {
+		t.Errorf("ST2 sent = %v, want [hdr2 hdr2]", got)
+	}
+	if hits != 0 {
+		t.Errorf("forward page fetched %d times, want 0", hits)
+	}
+}
+
+func TestLogin_TokensFromForwardPage(t *testing.T) {
+	var got []string
+	var hits int
+	server := mockTokenIDRAC(t, "", `<script>var ST1 = "page1"; var ST2 = "page2";</script>`, &got, &hits)
+	defer server.Close()
+
+	c := NewClient("localhost", "root", "calvin", WithFirmwareVersion("2.92 (Build 05)"))
+	c.baseURL = server.URL
+	c.http = server.Client()
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if hits != 1 {
+		t.Errorf("forward page fetched %d times, want 1", hits)
+	}
+	if c.st1 != "page1" || c.st2 != "page2" {
+		t.Errorf("tokens = (%q, %q), want (page1, page2)", c.st1, c.st2)
+	}
+}
+
+func TestLogin_TokenlessFallsBackToCookie(t *testing.T) {
+	var got []string
+	var hits int
+	server := mockTokenIDRAC(t, "", "<html>no tokens here</html>", &got, &hits)
+	defer server.Close()
+
+	c := NewClient("localhost", "root", "calvin", WithFirmwareVersion("2.92"))
+	c.baseURL = server.URL
+	c.http = server.Client()
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if c.newAuth {
+		t.Error("newAuth should be false without tokens")
+	}
+	if _, err := c.Get("pwState"); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != "" {
+		t.Errorf("ST2 sent = %v, want none", got)
+	}
+}
+
+func TestLogin_OldFirmwareSkipsForwardPage(t *testing.T) {
+	var got []string
+	var hits int
+	server := mockTokenIDRAC(t, "", `ST2=page2`, &got, &hits)
+	defer server.Close()
+
+	c := NewClient("localhost", "root", "calvin", WithFirmwareVersion("1.54"))
+	c.baseURL = server.URL
+	c.http = server.Client()
+
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if hits != 0 || c.newAuth {
+		t.Errorf("hits = %d, newAuth = %v; want 0, false", hits, c.newAuth)
+	}
+}
+
+func TestRequiresNewAuth(t *testing.T) {
+	tests := map[string]bool{
+		"2.92 (Build 05)": true,
+		"2.92":            true,
+		"2.90":            false,
+		"1.99 (Build 1)":  false,
+		"3.00":            true,
+		"":                false,
+		"unknown":         false,
+	}
+	for fw, want := range tests {
+		if got := requiresNewAuth(fw); got != want {
+			t.Errorf("requiresNewAuth(%q) = %v, want %v", fw, got, want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package idrac
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxPasswordLen is the longest password iDRAC6 accepts.
+const maxPasswordLen = 20
+
+// ValidatePassword checks that password can be set on an iDRAC6 account.
+// Besides the firmware's 20-character limit, characters that the RACADM
+// shell would interpret (whitespace, quotes, backslash) are rejected.
+func ValidatePassword(password string) error {
+	if password == "" || len(password) > maxPasswordLen {
+		return fmt.Errorf("password must be 1-%d characters", maxPasswordLen)
+	}
+	if !racadmSafe(password) {
+		return errors.New("password may only contain printable ASCII without spaces, quotes, or backslashes")
+	}
+	return nil
+}
+
+// UserIndex returns the cfgUserAdmin slot (1-16) of an iDRAC account.
+func (a *Admin) UserIndex(username string) (int, error) {
+	output, err := a.racadm.Run("getconfig", "-u", username)
+	if err != nil {
+		return 0, fmt.Errorf("looking up user %s: %w", username, err)
+	}
+	index, ok := parseConfigGroup(output)["cfgUserAdminIndex"]
+	if !ok {
+		return 0, fmt.Errorf("user %s not found", username)
+	}
+	n, err := strconv.Atoi(index)
+	if err != nil {
+		return 0, fmt.Errorf("parsing user index %q: %w", index, err)
+	}
+	return n, nil
+}
+
+// SetUserPassword sets the password of the account in cfgUserAdmin slot
+// index. The password never appears in returned errors.
+func (a *Admin) SetUserPassword(index int, password string) error {
+	if err := ValidatePassword(password); err != nil {
+		return err
+	}
+	_, err := a.racadm.Run("config", "-g", "cfgUserAdmin", "-o", "cfgUserAdminPassword", "-i", strconv.Itoa(index), password)
+	if err != nil {
+		return fmt.Errorf("setting password for user %d: %s", index, strings.ReplaceAll(err.Error(), password, "[REDACTED]"))
+	}
+	return nil
+}
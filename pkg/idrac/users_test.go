@@ -0,0 +1,56 @@
+package idrac
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	for _, p := range []string{"calvin", "N3w!P@ss#2024", strings.Repeat("x", 20)} {
+		if err := ValidatePassword(p); err != nil {
+			t.Errorf("ValidatePassword(%q) error = %v", p, err)
+		}
+	}
+	for _, p := range []string{"", strings.Repeat("x", 21), "has space", `quo"te`, "it's", `back\slash`, "tab\there", "ünïcode"} {
+		if err := ValidatePassword(p); err == nil {
+			t.Errorf("ValidatePassword(%q) should fail", p)
+		}
+	}
+}
+
+func TestUserIndex(t *testing.T) {
+	fake := &fakeRACADM{output: "# cfgUserAdminIndex=2\ncfgUserAdminUserName=root\n# cfgUserAdminPassword=******** (Write-Only)\ncfgUserAdminEnable=1"}
+	a := &Admin{racadm: fake}
+
+	n, err := a.UserIndex("root")
+	if err != nil || n != 2 {
+		t.Errorf("UserIndex() = %d, %v; want 2", n, err)
+	}
+	if fake.calls[0] != "getconfig -u root" {
+		t.Errorf("command = %q, want getconfig -u root", fake.calls[0])
+	}
+
+	fake.output = "ERROR: User not found"
+	if _, err := a.UserIndex("ghost"); err == nil {
+		t.Error("UserIndex() should fail for an unknown user")
+	}
+}
+
+func TestSetUserPassword(t *testing.T) {
+	fake := &fakeRACADM{}
+	a := &Admin{racadm: fake}
+
+	if err := a.SetUserPassword(2, "N3wPass!"); err != nil {
+		t.Fatalf("SetUserPassword() error = %v", err)
+	}
+	if want := "config -g cfgUserAdmin -o cfgUserAdminPassword -i 2 N3wPass!"; fake.calls[0] != want {
+		t.Errorf("command = %q, want %q", fake.calls[0], want)
+	}
+
+	fake.err = errors.New(`RACADM command "racadm config ... N3wPass!": exit status 1`)
+	err := a.SetUserPassword(2, "N3wPass!")
+	if err == nil || strings.Contains(err.Error(), "N3wPass!") {
+		t.Errorf("SetUserPassword() error = %v, want an error without the password", err)
+	}
+}
@@ -0,0 +1,149 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	racadmssh "github.com/williamzujkowski/idrac6-manager/internal/ssh"
+)
+
+// VirtualMediaStatus represents the current virtual media mount state.
+type VirtualMediaStatus struct {
+	Connected bool   `json:"connected"`
+	URL       string `json:"url,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+// ErrVirtualMediaBusy is returned by Mount and Unmount while another mount
+// or unmount on the same VirtualMedia is in progress.
+var ErrVirtualMediaBusy error = &Error{Status: http.StatusConflict, Code: CodeVirtualMediaBusy, Err: errors.New("a virtual media operation is already in progress")}
+
+// VirtualMedia manages virtual media via RACADM over SSH. Mounts and
+// unmounts are serialized: one that starts while another is running fails
+// with ErrVirtualMediaBusy instead of racing its disconnect-then-connect
+// sequence. Use one VirtualMedia per host.
+type VirtualMedia struct {
+	racadm contextRunner
+	// busy is held for the duration of a mount or unmount.
+	busy sync.Mutex
+}
+
+// NewVirtualMedia creates a new VirtualMedia manager.
+func NewVirtualMedia(host string, port int, username, password string, opts ...RACADMOption) *VirtualMedia {
+	return &VirtualMedia{
+		racadm: racadmssh.NewRACAdm(host, port, username, password, opts...),
+	}
+}
+
+// GetStatus returns the current virtual media connection status.
+func (vm *VirtualMedia) GetStatus() (*VirtualMediaStatus, error) {
+	return vm.GetStatusContext(context.Background())
+}
+
+// GetStatusContext is GetStatus with a context; cancelling ctx closes the
+// SSH session.
+func (vm *VirtualMedia) GetStatusContext(ctx context.Context) (*VirtualMediaStatus, error) {
+	output, err := vm.racadm.RunContext(ctx, "remoteimage", "-s")
+	if err != nil {
+		return nil, fmt.Errorf("checking virtual media status: %w", err)
+	}
+
+	status := &VirtualMediaStatus{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "Image is") {
+			if strings.Contains(line, "connected") {
+				status.Connected = true
+			}
+		}
+		if strings.HasPrefix(line, "Image Location") || strings.HasPrefix(line, "Share Name") {
+			parts := strings.SplitN(line, "=", 2)
+			if len(parts) == 2 {
+				status.URL = strings.TrimSpace(parts[1])
+			}
+		}
+	}
+
+	return status, nil
+}
+
+// Mount connects a remote image via NFS, CIFS, or HTTP.
+func (vm *VirtualMedia) Mount(imageURL string) error {
+	return vm.MountContext(context.Background(), imageURL)
+}
+
+// MountContext is Mount with a context; cancelling ctx closes the SSH
+// session.
+func (vm *VirtualMedia) MountContext(ctx context.Context, imageURL string) error {
+	if !vm.busy.TryLock() {
+		return ErrVirtualMediaBusy
+	}
+	defer vm.busy.Unlock()
+
+	// Disconnect any existing image first
+	_ = vm.unmount(ctx)
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("mounting image %q: %w", imageURL, err)
+	}
+
+	// racadm remoteimage -c -l <url>
+	_, err := vm.racadm.RunContext(ctx, "remoteimage", "-c", "-l", imageURL)
+	if err != nil {
+		return fmt.Errorf("mounting image %q: %w", imageURL, err)
+	}
+
+	return nil
+}
+
+// Unmount disconnects the current virtual media image. It is idempotent:
+// when no image is connected it succeeds without error.
+func (vm *VirtualMedia) Unmount() error {
+	return vm.UnmountContext(context.Background())
+}
+
+// UnmountContext is Unmount with a context; cancelling ctx closes the SSH
+// session.
+func (vm *VirtualMedia) UnmountContext(ctx context.Context) error {
+	if !vm.busy.TryLock() {
+		return ErrVirtualMediaBusy
+	}
+	defer vm.busy.Unlock()
+	return vm.unmount(ctx)
+}
+
+// unmount disconnects the image, treating "nothing connected" as success.
+// Called with vm.busy held.
+func (vm *VirtualMedia) unmount(ctx context.Context) error {
+	output, err := vm.racadm.RunContext(ctx, "remoteimage", "-d")
+	if err != nil {
+		// Some firmware exits non-zero when there is nothing to
+		// disconnect; the RACADM error carries its output.
+		if ctx.Err() == nil && notMounted(err.Error()) {
+			return nil
+		}
+		return fmt.Errorf("unmounting image: %w", err)
+	}
+	if strings.HasPrefix(strings.TrimSpace(output), "ERROR") && !notMounted(output) {
+		return fmt.Errorf("unmounting image: %w", &racadmssh.Error{Status: http.StatusBadGateway, Code: racadmssh.CodeCommand, Err: errors.New(strings.TrimSpace(output))})
+	}
+	return nil
+}
+
+// notMountedPhrases are fragments of lowercased RACADM output meaning
+// there was no image to disconnect.
+var notMountedPhrases = []string{"not connected", "not currently connected", "no image", "already disconnected"}
+
+// notMounted reports whether RACADM output says no image is connected.
+func notMounted(output string) bool {
+	output = strings.ToLower(output)
+	for _, phrase := range notMountedPhrases {
+		if strings.Contains(output, phrase) {
+			return true
+		}
+	}
+	return false
+}
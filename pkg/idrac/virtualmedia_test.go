@@ -0,0 +1,96 @@
+package idrac
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// blockingRACADM records commands and blocks each one until release is
+// closed.
+type blockingRACADM struct {
+	mu      sync.Mutex
+	calls   []string
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingRACADM) RunContext(_ context.Context, args ...string) (string, error) {
+	b.mu.Lock()
+	b.calls = append(b.calls, strings.Join(args, " "))
+	b.mu.Unlock()
+	b.started <- struct{}{}
+	<-b.release
+	return "", nil
+}
+
+func TestVirtualMedia_SerializesMounts(t *testing.T) {
+	runner := &blockingRACADM{started: make(chan struct{}, 4), release: make(chan struct{})}
+	vm := &VirtualMedia{racadm: runner}
+
+	done := make(chan error)
+	go func() { done <- vm.Mount("nfs://10.0.0.5/a.iso") }()
+	<-runner.started // the mount's unmount step is running
+
+	if err := vm.Mount("nfs://10.0.0.5/b.iso"); !errors.Is(err, ErrVirtualMediaBusy) {
+		t.Errorf("concurrent Mount() = %v, want ErrVirtualMediaBusy", err)
+	}
+	if err := vm.Unmount(); !errors.Is(err, ErrVirtualMediaBusy) {
+		t.Errorf("concurrent Unmount() = %v, want ErrVirtualMediaBusy", err)
+	}
+
+	close(runner.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Mount() error = %v", err)
+	}
+	want := []string{"remoteimage -d", "remoteimage -c -l nfs://10.0.0.5/a.iso"}
+	if !slices.Equal(runner.calls, want) {
+		t.Errorf("commands = %q, want %q", runner.calls, want)
+	}
+
+	// Once the mount finishes the lock is free again.
+	if err := vm.Unmount(); err != nil {
+		t.Errorf("Unmount() after mount = %v", err)
+	}
+	var e *Error
+	if !errors.As(ErrVirtualMediaBusy, &e) || e.HTTPStatus() != 409 {
+		t.Errorf("ErrVirtualMediaBusy status = %v", e)
+	}
+}
+
+// contextRACADM adapts fakeRACADM to contextRunner.
+type contextRACADM struct{ *fakeRACADM }
+
+func (c contextRACADM) RunContext(_ context.Context, args ...string) (string, error) {
+	return c.Run(args...)
+}
+
+func TestVirtualMedia_UnmountIdempotent(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		err     error
+		wantErr bool
+	}{
+		{"mounted", "Disable Remote File Started.", nil, false},
+		{"already unmounted", "ERROR: No image is currently connected.", nil, false},
+		{"already unmounted, non-zero exit", "", errors.New(`RACADM command "racadm remoteimage -d": exit status 1 (output: ERROR: Remote file share is not connected.)`), false},
+		{"genuine failure", "ERROR: Unable to disconnect remote file share.", nil, true},
+		{"connection failure", "", errors.New("SSH connect to 10.0.0.1:22: connection refused"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeRACADM{output: tt.output, err: tt.err}
+			vm := &VirtualMedia{racadm: contextRACADM{fake}}
+			if err := vm.Unmount(); (err != nil) != tt.wantErr {
+				t.Errorf("Unmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(fake.calls) != 1 || fake.calls[0] != "remoteimage -d" {
+				t.Errorf("commands = %q, want one remoteimage -d", fake.calls)
+			}
+		})
+	}
+}
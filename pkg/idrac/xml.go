@@ -0,0 +1,107 @@
+package idrac
+
+import (
+	"bytes"
+	"encoding/xml"
+	"html"
+	"io"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// xmlEncodingRe captures the encoding from a leading XML declaration.
+var xmlEncodingRe = regexp.MustCompile(`^<\?xml[^>]*\bencoding\s*=\s*["']([^"']+)["']`)
+
+// decodeXML unmarshals an iDRAC XML response into v, tolerating the common
+// iDRAC6 firmware quirks: missing XML declaration, stray bytes or HTML
+// before the document, invalid UTF-8, Latin-1 declared encodings, and HTML
+// entities such as &nbsp;.
+func decodeXML(data []byte, v interface{}) error {
+	d := xml.NewDecoder(bytes.NewReader(sanitizeXML(data)))
+	d.Strict = false
+	d.AutoClose = xml.HTMLAutoClose
+	d.Entity = xml.HTMLEntity
+	d.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) {
+		// sanitizeXML has already converted the body to UTF-8
+		return r, nil
+	}
+	return d.Decode(v)
+}
+
+// sanitizeXML strips junk before the document, converts Latin-1 bodies to
+// UTF-8, and replaces any remaining invalid UTF-8 sequences.
+func sanitizeXML(data []byte) []byte {
+	data = bytes.TrimLeft(data, "\ufeff\x00 \t\r\n")
+
+	// Skip anything before the XML declaration or <root> element.
+	if idx := indexDocumentStart(data); idx > 0 {
+		data = data[idx:]
+	}
+
+	if m := xmlEncodingRe.FindSubmatch(data); m != nil {
+		enc := strings.ToLower(string(m[1]))
+		latin1 := enc == "iso-8859-1" || enc == "latin1" || enc == "windows-1252"
+		if latin1 && !utf8.Valid(data) {
+			data = latin1ToUTF8(data)
+		}
+	}
+
+	if !utf8.Valid(data) {
+		data = bytes.ToValidUTF8(data, []byte("\uFFFD"))
+	}
+	return data
+}
+
+// indexDocumentStart returns the offset of the XML declaration or the
+// <root> element, or -1 if neither is present.
+func indexDocumentStart(data []byte) int {
+	if idx := bytes.Index(data, []byte("<?xml")); idx >= 0 {
+		return idx
+	}
+	return bytes.Index(data, []byte("<root"))
+}
+
+// latin1ToUTF8 converts ISO-8859-1 bytes to UTF-8.
+func latin1ToUTF8(data []byte) []byte {
+	buf := make([]rune, 0, len(data))
+	for _, b := range data {
+		buf = append(buf, rune(b))
+	}
+	return []byte(string(buf))
+}
+
+// stripCDATA removes CDATA markers left in text. The XML decoder unwraps
+// a real CDATA section itself, but some firmware entity-encodes the
+// markers or nests a section inside escaped text, so they survive decoding.
+func stripCDATA(s string) string {
+	if !strings.Contains(s, "<![CDATA[") {
+		return s
+	}
+	s = strings.ReplaceAll(s, "<![CDATA[", "")
+	return strings.ReplaceAll(s, "]]>", "")
+}
+
+// cleanText normalizes a parsed sensor or SEL field: CDATA markers are
+// removed, HTML entities that survived XML decoding (double-encoded
+// "&amp;gt;", or "&nbsp;" in text the decoder passed through) are
+// unescaped, non-breaking spaces become spaces, control characters such
+// as the NULs some firmware pads fields with are dropped, and the result
+// is trimmed.
+func cleanText(s string) string {
+	s = stripCDATA(s)
+	if strings.Contains(s, "&") {
+		s = html.UnescapeString(s)
+	}
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case r == '\u00a0', r == '\t':
+			return ' '
+		case unicode.IsControl(r):
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
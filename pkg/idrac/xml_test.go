@@ -0,0 +1,75 @@
+package idrac
+
+import (
+	"testing"
+)
+
+func TestDecodeXML_MalformedSamples(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"clean", `<?xml version="1.0" encoding="UTF-8"?><root><pwState>1</pwState></root>`, "1"},
+		{"no declaration", `<root><pwState>1</pwState></root>`, "1"},
+		{"leading whitespace and BOM", "\ufeff\r\n  <root><pwState>0</pwState></root>", "0"},
+		{"stray content before root", "HTTP/1.1 200 OK junk\r\n<root><pwState>1</pwState></root>", "1"},
+		{"html preamble", `<html><body>error</body></html><?xml version="1.0"?><root><pwState>1</pwState></root>`, "1"},
+		{"html entity", `<root><pwState>1&nbsp;</pwState></root>`, "1 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp powerResponse
+			if err := decodeXML([]byte(tt.data), &resp); err != nil {
+				t.Fatalf("decodeXML() error = %v", err)
+			}
+			if resp.PwState != tt.want {
+				t.Errorf("PwState = %q, want %q", resp.PwState, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeXML_InvalidUTF8(t *testing.T) {
+	data := []byte("<root><hostName>R710-\xff\xfeTEST</hostName></root>")
+
+	var resp sysInfoResponse
+	if err := decodeXML(data, &resp); err != nil {
+		t.Fatalf("decodeXML() error = %v", err)
+	}
+	if resp.HostName != "R710-\uFFFDTEST" {
+		t.Errorf("HostName = %q, want invalid bytes replaced", resp.HostName)
+	}
+}
+
+func TestDecodeXML_Latin1(t *testing.T) {
+	// "Büro" encoded as ISO-8859-1
+	data := []byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><root><hostName>B\xfcro</hostName></root>")
+
+	var resp sysInfoResponse
+	if err := decodeXML(data, &resp); err != nil {
+		t.Fatalf("decodeXML() error = %v", err)
+	}
+	if resp.HostName != "Büro" {
+		t.Errorf("HostName = %q, want Büro", resp.HostName)
+	}
+}
+
+func TestCleanText(t *testing.T) {
+	tests := map[string]string{
+		"  plain  ":                       "plain",
+		"<![CDATA[Inlet Temp]]>":          "Inlet Temp",
+		"Temp &gt; 90 &amp; rising":       "Temp > 90 & rising",
+		"Fan&nbsp;1":                      "Fan 1",
+		"PS1 \u00a0Status\u00a0":          "PS1  Status",
+		"<![CDATA[a]]> and <![CDATA[b]]>": "a and b",
+		"AT&T":                            "AT&T",
+		"CPU1\x00\x00 Temp\r":             "CPU1 Temp",
+	}
+	for in, want := range tests {
+		if got := cleanText(in); got != want {
+			t.Errorf("cleanText(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
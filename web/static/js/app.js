@@ -4,6 +4,9 @@ const App = {
     currentHost: 'default',
     refreshInterval: null,
     refreshMs: 5000,
+    // Deployment settings from /api/ui-config
+    config: { basePath: '', authRequired: false, features: {} },
+    apiKey: localStorage.getItem('idrac6-api-key') || '',
 
     // API helper
     async api(method, path, body) {
@@ -12,16 +15,37 @@ const App = {
             headers: { 'Content-Type': 'application/json' },
         };
         if (body) opts.body = JSON.stringify(body);
+        if (this.apiKey) opts.headers['X-API-Key'] = this.apiKey;
 
-        const resp = await fetch('/api' + path, opts);
+        // Relative URL so the UI works when mounted under a base path
+        const resp = await fetch('api' + path, opts);
         const data = await resp.json();
 
+        if (resp.status === 401 && this.config.authRequired) {
+            // Forget a rejected key so the next load asks again
+            localStorage.removeItem('idrac6-api-key');
+            this.apiKey = '';
+        }
         if (!resp.ok) {
             throw new Error(data.error || `HTTP ${resp.status}`);
         }
         return data;
     },
 
+    // Load deployment settings and ask for an API key if one is needed
+    async loadConfig() {
+        this.config = await this.api('GET', '/ui-config');
+        if (this.config.authRequired && !this.apiKey) {
+            this.apiKey = (prompt('API key') || '').trim();
+            if (this.apiKey) localStorage.setItem('idrac6-api-key', this.apiKey);
+        }
+        if (this.config.features.readOnly) {
+            document.querySelectorAll('.power-actions button, .vmedia-form button, #sel-clear')
+                .forEach(btn => { btn.disabled = true; btn.title = 'Server is in read-only mode'; });
+        }
+        document.getElementById('footer-version').textContent = this.config.version;
+    },
+
     // Show connection status
     setStatus(msg, type) {
         const el = document.getElementById('connection-status');
@@ -37,6 +61,8 @@ const App = {
     // Initialize the app
     async init() {
         try {
+            await this.loadConfig();
+
             // Load hosts
             const hosts = await this.api('GET', '/hosts');
             if (hosts.length > 0) {
@@ -133,8 +159,8 @@ async function refreshSEL() { Dashboard.refreshSEL(); }
 async function clearSEL() {
     if (!confirm('Clear all system event log entries?')) return;
     try {
-        await App.api('DELETE', App.hostPath('/sel'));
-        App.setStatus('Event log cleared', 'connected');
+        const result = await App.api('DELETE', App.hostPath('/sel?confirm=true'));
+        App.setStatus('Event log cleared (' + result.cleared + ' entries)', 'connected');
         Dashboard.refreshSEL();
     } catch (err) {
         App.setStatus('Clear SEL failed: ' + err.message, 'error');
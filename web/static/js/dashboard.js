@@ -65,7 +65,7 @@ const Dashboard = {
 
         container.innerHTML = sensors.map(s => {
             const statusClass = this.sensorStatusClass(s);
-            const pct = Math.min(100, (s.value / maxVal) * 100);
+            const pct = Math.min(100, (Math.abs(s.value) / maxVal) * 100);
             return `
                 <div class="sensor-item ${statusClass}">
                     <span class="sensor-name">${this.escapeHtml(s.name)}</span>
@@ -79,6 +79,9 @@ const Dashboard = {
     },
 
     sensorStatusClass(sensor) {
+        if (sensor.health) {
+            return { critical: 'sensor-crit', warning: 'sensor-warn', unknown: 'sensor-unknown' }[sensor.health] || 'sensor-ok';
+        }
         if (sensor.status === 'critical' || (sensor.critical > 0 && sensor.value >= sensor.critical)) {
             return 'sensor-crit';
         }
@@ -107,12 +110,12 @@ const Dashboard = {
             const sel = await App.api('GET', App.hostPath('/sel'));
             const body = document.getElementById('sel-body');
 
-            if (!sel.entries || sel.entries.length === 0) {
+            if (!sel.items || sel.items.length === 0) {
                 body.innerHTML = '<tr><td colspan="4" class="empty-state">No events</td></tr>';
                 return;
             }
 
-            body.innerHTML = sel.entries.map(e => `
+            body.innerHTML = sel.items.map(e => `
                 <tr>
                     <td>${this.escapeHtml(e.id)}</td>
                     <td>${this.escapeHtml(e.timestamp)}</td>